@@ -0,0 +1,63 @@
+package rdf
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strconv"
+)
+
+// BlankAllocator allocates blank node labels on behalf of a decoder.
+//
+// Blank node labels are scoped to the document they appear in, so two
+// documents using the label _:a are talking about two different nodes. A
+// BlankAllocator lets you decode several documents into the same graph
+// without their blank nodes being merged. Use one allocator per document.
+type BlankAllocator interface {
+	// New returns the label of a fresh, anonymous blank node.
+	New() string
+
+	// Scope returns the label to use for a blank node labelled l in the
+	// document. It must return the same label for every occurrence of l,
+	// and never a label returned by New.
+	Scope(l string) string
+}
+
+// prefixAllocator qualifies all blank node labels with a prefix.
+type prefixAllocator struct {
+	prefix string
+	n      int // anonymous blank node counter
+}
+
+// NewPrefixAllocator returns a BlankAllocator which qualifies blank node
+// labels with the given prefix. Anonymous blank nodes are labelled
+// prefix+"b1", prefix+"b2", and so on, while user labels are kept as
+// prefix+"_"+label, so the two can never clash.
+func NewPrefixAllocator(prefix string) BlankAllocator {
+	return &prefixAllocator{prefix: prefix}
+}
+
+// NewUUIDAllocator returns a BlankAllocator which qualifies blank node
+// labels with a random (version 4) UUID, making them unique across documents
+// without any coordination between the decoders.
+func NewUUIDAllocator() BlankAllocator {
+	var u [16]byte
+	if _, err := rand.Read(u[:]); err != nil {
+		panic(err)
+	}
+	u[6] = (u[6] & 0x0f) | 0x40 // version 4
+	u[8] = (u[8] & 0x3f) | 0x80 // variant 10
+	return &prefixAllocator{
+		prefix: fmt.Sprintf("%x-%x-%x-%x-%x-", u[0:4], u[4:6], u[6:8], u[8:10], u[10:]),
+	}
+}
+
+// New returns a fresh blank node label.
+func (a *prefixAllocator) New() string {
+	a.n++
+	return a.prefix + "b" + strconv.Itoa(a.n)
+}
+
+// Scope returns the prefixed form of the given blank node label.
+func (a *prefixAllocator) Scope(l string) string {
+	return a.prefix + "_" + l
+}
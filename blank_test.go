@@ -0,0 +1,103 @@
+package rdf
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBlankAllocator(t *testing.T) {
+	tests := []struct {
+		input  string
+		format Format
+		want   []Triple
+	}{
+		{`_:a <http://ex/p> [ <http://ex/q> _:a ] .`, Turtle, []Triple{
+			Triple{
+				Subj: Blank{id: "_:d1_a"},
+				Pred: IRI{str: "http://ex/p"},
+				Obj:  Blank{id: "_:d1b1"},
+			},
+			Triple{
+				Subj: Blank{id: "_:d1b1"},
+				Pred: IRI{str: "http://ex/q"},
+				Obj:  Blank{id: "_:d1_a"},
+			},
+		}},
+		{`_:b1 <http://ex/p> [] .`, Turtle, []Triple{
+			Triple{
+				Subj: Blank{id: "_:d1_b1"},
+				Pred: IRI{str: "http://ex/p"},
+				Obj:  Blank{id: "_:d1b1"},
+			},
+		}},
+		{"_:a <http://ex/p> _:b .\n", NTriples, []Triple{
+			Triple{
+				Subj: Blank{id: "_:d1_a"},
+				Pred: IRI{str: "http://ex/p"},
+				Obj:  Blank{id: "_:d1_b"},
+			},
+		}},
+		{`<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns:ex="http://ex/">
+  <rdf:Description rdf:nodeID="a">
+    <ex:p><rdf:Description /></ex:p>
+  </rdf:Description>
+</rdf:RDF>`, RDFXML, []Triple{
+			Triple{
+				Subj: Blank{id: "_:d1_a"},
+				Pred: IRI{str: "http://ex/p"},
+				Obj:  Blank{id: "_:d1b1"},
+			},
+		}},
+	}
+
+	for _, test := range tests {
+		dec := NewTripleDecoder(bytes.NewBufferString(test.input), test.format)
+		if err := dec.SetOption(BlankNodes, NewPrefixAllocator("d1")); err != nil {
+			t.Fatal(err)
+		}
+		triples, err := dec.DecodeAll()
+		if err != nil {
+			t.Fatalf("decoding %s => %v", test.input, err)
+		}
+		if !reflect.DeepEqual(triples, test.want) {
+			t.Errorf("decoding %s => %v; want %v", test.input, triples, test.want)
+		}
+	}
+}
+
+func TestBlankAllocatorQuads(t *testing.T) {
+	dec := NewQuadDecoder(bytes.NewBufferString("_:a <http://ex/p> _:b _:g .\n"), NQuads)
+	if err := dec.SetOption(BlankNodes, NewPrefixAllocator("x")); err != nil {
+		t.Fatal(err)
+	}
+	q, err := dec.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Quad{
+		Triple{
+			Subj: Blank{id: "_:x_a"},
+			Pred: IRI{str: "http://ex/p"},
+			Obj:  Blank{id: "_:x_b"},
+		},
+		Blank{id: "_:x_g"},
+	}
+	if !QuadsEqual(q, want) {
+		t.Errorf("decoding with BlankNodes option => %v; want %v", q, want)
+	}
+}
+
+func TestUUIDAllocator(t *testing.T) {
+	a, b := NewUUIDAllocator(), NewUUIDAllocator()
+	if a.Scope("x") == b.Scope("x") {
+		t.Errorf("two UUID allocators scoped label to the same label %q", a.Scope("x"))
+	}
+	if a.New() == a.New() {
+		t.Error("UUID allocator returned the same label twice")
+	}
+	if strings.Count(a.New(), "-") != 5 {
+		t.Errorf("UUID allocator label %q not prefixed by UUID", a.New())
+	}
+}
@@ -0,0 +1,325 @@
+// Command rdfvocab generates a Go source file with the terms of a RDFS or
+// OWL ontology as rdf.IRI values, one for each class and property in the
+// namespace of the ontology. The rdfs:comment of a term becomes the doc
+// comment of its variable.
+//
+// Usage:
+//
+//  rdfvocab -ns <namespace IRI> [-pkg <package>] [-o <file>] [-format ttl|nt|rdfxml] <ontology file>
+//
+// Example:
+//
+//  rdfvocab -ns http://example.org/onto# -pkg onto -o onto.go onto.ttl
+//
+// Terms are named as in the vocab packages; see
+// http://godoc.org/github.com/knakk/rdf/vocab.
+//
+// The input format is inferred from the file extension, unless given with the
+// -format flag. Use "-" to read the ontology from standard input.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/knakk/rdf"
+	"github.com/knakk/rdf/vocab/owl"
+	rdfns "github.com/knakk/rdf/vocab/rdf"
+	"github.com/knakk/rdf/vocab/rdfs"
+)
+
+var (
+	nsFlag     = flag.String("ns", "", "namespace IRI of the terms to generate (required)")
+	pkgFlag    = flag.String("pkg", "", "name of the generated package (default: name of output directory)")
+	outFlag    = flag.String("o", "", "output file (default: standard output)")
+	formatFlag = flag.String("format", "", "input format: ttl, nt or rdfxml (default: inferred from file extension)")
+)
+
+// classTypes are the types which makes a resource a class.
+var classTypes = map[rdf.IRI]bool{
+	rdfs.Class:    true,
+	rdfs.Datatype: true,
+	owl.Class:     true,
+}
+
+// propertyTypes are the types which makes a resource a property.
+var propertyTypes = map[rdf.IRI]bool{
+	rdfns.Property:                true,
+	owl.ObjectProperty:            true,
+	owl.DatatypeProperty:          true,
+	owl.AnnotationProperty:        true,
+	owl.OntologyProperty:          true,
+	owl.FunctionalProperty:        true,
+	owl.InverseFunctionalProperty: true,
+	owl.TransitiveProperty:        true,
+	owl.SymmetricProperty:         true,
+	owl.AsymmetricProperty:        true,
+	owl.ReflexiveProperty:         true,
+	owl.IrreflexiveProperty:       true,
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: rdfvocab -ns <namespace IRI> [flags] <ontology file>\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *nsFlag == "" || flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(flag.Arg(0)); err != nil {
+		fmt.Fprintf(os.Stderr, "rdfvocab: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(input string) error {
+	f, err := inputFormat(input, *formatFlag)
+	if err != nil {
+		return err
+	}
+	var r io.Reader = os.Stdin
+	if input != "-" {
+		file, err := os.Open(input)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		r = file
+	}
+	triples, err := rdf.NewTripleDecoder(r, f).DecodeAll()
+	if err != nil {
+		return fmt.Errorf("parsing %s: %v", input, err)
+	}
+
+	pkg := *pkgFlag
+	if pkg == "" {
+		pkg = "vocab"
+		if *outFlag != "" {
+			if abs, err := filepath.Abs(*outFlag); err == nil {
+				pkg = filepath.Base(filepath.Dir(abs))
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := generate(&buf, triples, *nsFlag, pkg, filepath.Base(input)); err != nil {
+		return err
+	}
+	if *outFlag == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	return os.WriteFile(*outFlag, buf.Bytes(), 0644)
+}
+
+// inputFormat returns the format given by name, or if name is empty,
+// the format inferred from the extension of the input file.
+func inputFormat(input, name string) (rdf.Format, error) {
+	if name == "" {
+		name = strings.TrimPrefix(strings.ToLower(filepath.Ext(input)), ".")
+	}
+	switch name {
+	case "ttl", "turtle":
+		return rdf.Turtle, nil
+	case "nt", "ntriples":
+		return rdf.NTriples, nil
+	case "rdf", "rdfxml", "owl", "xml":
+		return rdf.RDFXML, nil
+	case "":
+		return 0, errors.New("cannot infer input format; use the -format flag")
+	default:
+		return 0, fmt.Errorf("unsupported input format: %q", name)
+	}
+}
+
+// term is a class or property of the vocabulary.
+type term struct {
+	local   string // local name, i.e. the IRI without the namespace
+	name    string // Go name
+	comment string
+	class   bool
+}
+
+// generate writes the Go source for the terms in the namespace ns found in the
+// triples. The source is the name of the ontology file, mentioned in the header.
+func generate(w io.Writer, triples []rdf.Triple, ns, pkg, source string) error {
+	terms := make(map[string]*term)
+	comments := make(map[string]rdf.Literal)
+	for _, tr := range triples {
+		subj, ok := tr.Subj.(rdf.IRI)
+		if !ok || !strings.HasPrefix(subj.String(), ns) || subj.String() == ns {
+			continue
+		}
+		local := strings.TrimPrefix(subj.String(), ns)
+		pred, ok := tr.Pred.(rdf.IRI)
+		if !ok {
+			continue
+		}
+		switch pred {
+		case rdfns.Type:
+			obj, ok := tr.Obj.(rdf.IRI)
+			if !ok {
+				continue
+			}
+			if classTypes[obj] || propertyTypes[obj] {
+				t := terms[local]
+				if t == nil {
+					t = &term{local: local}
+					terms[local] = t
+				}
+				t.class = t.class || classTypes[obj]
+			}
+		case rdfs.Comment:
+			if l, ok := tr.Obj.(rdf.Literal); ok {
+				if c, ok := comments[local]; !ok || preferComment(l, c) {
+					comments[local] = l
+				}
+			}
+		}
+	}
+
+	var classes, props []*term
+	names := make(map[string]string) // Go name -> local name
+	for _, t := range terms {
+		if c, ok := comments[t.local]; ok {
+			t.comment = c.String()
+		}
+		t.name = goName(t.local)
+		if t.class {
+			classes = append(classes, t)
+		} else {
+			props = append(props, t)
+		}
+	}
+	if len(classes)+len(props) == 0 {
+		return fmt.Errorf("no classes or properties found in namespace %s", ns)
+	}
+	sort.Slice(classes, func(i, j int) bool { return classes[i].local < classes[j].local })
+	sort.Slice(props, func(i, j int) bool { return props[i].local < props[j].local })
+	for _, t := range classes {
+		if other, ok := names[t.name]; ok {
+			return fmt.Errorf("terms %q and %q have the same Go name: %s", other, t.local, t.name)
+		}
+		names[t.name] = t.local
+	}
+	for _, t := range props {
+		if _, ok := names[t.name]; ok {
+			t.name += "Property"
+		}
+		if other, ok := names[t.name]; ok {
+			return fmt.Errorf("terms %q and %q have the same Go name: %s", other, t.local, t.name)
+		}
+		names[t.name] = t.local
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by rdfvocab from %s; DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	b.WriteString("import \"github.com/knakk/rdf\"\n\n")
+	fmt.Fprintf(&b, "// NS is the namespace IRI of the vocabulary.\nconst NS = %q\n", ns)
+	writeTerms(&b, "Classes", classes)
+	writeTerms(&b, "Properties", props)
+	b.WriteString(`
+// iri returns the IRI of the term with the given local name in the vocabulary.
+func iri(local string) rdf.IRI {
+	u, err := rdf.NewIRI(NS + local)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+`)
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return fmt.Errorf("formatting generated source: %v", err)
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+// writeTerms writes a var block with the given terms.
+func writeTerms(b *bytes.Buffer, heading string, terms []*term) {
+	if len(terms) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n// %s:\nvar (\n", heading)
+	for i, t := range terms {
+		if t.comment != "" {
+			if i > 0 {
+				b.WriteByte('\n')
+			}
+			for _, line := range wrap(t.comment, 76) {
+				fmt.Fprintf(b, "\t// %s\n", line)
+			}
+		}
+		fmt.Fprintf(b, "\t%s = iri(%q)\n", t.name, t.local)
+	}
+	b.WriteString(")\n")
+}
+
+// preferComment reports whether the comment a is preferred over b; comments
+// in English, or without language, are preferred.
+func preferComment(a, b rdf.Literal) bool {
+	english := func(l rdf.Literal) bool {
+		return l.Lang() == "" || l.Lang() == "en" || strings.HasPrefix(l.Lang(), "en-")
+	}
+	return english(a) && !english(b)
+}
+
+// goName returns the exported Go name for a local name; the first letter in
+// upper case, and any characters not allowed in Go identifiers replaced by '_'.
+func goName(local string) string {
+	var b strings.Builder
+	for i, r := range local {
+		switch {
+		case i == 0 && unicode.IsUpper(unicode.ToUpper(r)):
+			b.WriteRune(unicode.ToUpper(r))
+		case i == 0:
+			// Identifiers must start with a letter; and exported ones
+			// with an upper case letter. Letters without an upper case,
+			// like those of many scripts, are kept after the prefix.
+			b.WriteRune('X')
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				b.WriteRune(r)
+			} else {
+				b.WriteRune('_')
+			}
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+// wrap splits the text into lines of at most width characters, if possible.
+func wrap(text string, width int) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return nil
+	}
+	var lines []string
+	line := words[0]
+	for _, w := range words[1:] {
+		if len(line)+1+len(w) > width {
+			lines = append(lines, line)
+			line = w
+			continue
+		}
+		line += " " + w
+	}
+	return append(lines, line)
+}
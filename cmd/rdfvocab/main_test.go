@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/knakk/rdf"
+)
+
+func TestGenerate(t *testing.T) {
+	input := `@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+@prefix rdfs: <http://www.w3.org/2000/01/rdf-schema#> .
+@prefix owl: <http://www.w3.org/2002/07/owl#> .
+@prefix ex: <http://example.org/onto#> .
+
+ex: a owl:Ontology ; rdfs:comment "An example ontology." .
+ex:Book a owl:Class ;
+	rdfs:comment "En bok."@nb, "A written or printed work consisting of pages glued or sewn together along one side and bound in covers."@en .
+ex:book a owl:ObjectProperty ; rdfs:comment "Links a chapter to its book." .
+ex:title a owl:DatatypeProperty, owl:FunctionalProperty .
+ex:page-count a rdf:Property .
+ex:firstEdition a ex:Book .
+<http://example.org/other#Thing> a owl:Class .
+`
+	triples, err := rdf.NewTripleDecoder(bytes.NewBufferString(input), rdf.Turtle).DecodeAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := generate(&out, triples, "http://example.org/onto#", "onto", "onto.ttl"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `// Code generated by rdfvocab from onto.ttl; DO NOT EDIT.
+
+package onto
+
+import "github.com/knakk/rdf"
+
+// NS is the namespace IRI of the vocabulary.
+const NS = "http://example.org/onto#"
+
+// Classes:
+var (
+	// A written or printed work consisting of pages glued or sewn together along
+	// one side and bound in covers.
+	Book = iri("Book")
+)
+
+// Properties:
+var (
+	// Links a chapter to its book.
+	BookProperty = iri("book")
+	Page_count   = iri("page-count")
+	Title        = iri("title")
+)
+
+// iri returns the IRI of the term with the given local name in the vocabulary.
+func iri(local string) rdf.IRI {
+	u, err := rdf.NewIRI(NS + local)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+`
+	if out.String() != want {
+		t.Errorf("generate =>\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestGenerateNameCollision(t *testing.T) {
+	for _, input := range []string{
+		"ex:a-b a owl:Class .\nex:a_b a owl:Class .\n",
+		"ex:A a owl:Class .\nex:a a rdf:Property .\nex:AProperty a owl:Class .\n",
+	} {
+		input = `@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+@prefix owl: <http://www.w3.org/2002/07/owl#> .
+@prefix ex: <http://example.org/onto#> .
+` + input
+		triples, err := rdf.NewTripleDecoder(bytes.NewBufferString(input), rdf.Turtle).DecodeAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out bytes.Buffer
+		err = generate(&out, triples, "http://example.org/onto#", "onto", "onto.ttl")
+		if err == nil || !strings.Contains(err.Error(), "have the same Go name") {
+			t.Errorf("generate(%q) => %v; want same Go name error", input, err)
+		}
+	}
+}
+
+func TestGoName(t *testing.T) {
+	tests := []struct {
+		local, want string
+	}{
+		{"label", "Label"},
+		{"Class", "Class"},
+		{"ISO639-2", "ISO639_2"},
+		{"mbox_sha1sum", "Mbox_sha1sum"},
+		{"v1.2", "V1_2"},
+		{"3D", "X3D"},
+		{"_x", "X_x"},
+		{"名前", "X名前"},
+	}
+	for _, tt := range tests {
+		if got := goName(tt.local); got != tt.want {
+			t.Errorf("goName(%q) => %q; want %q", tt.local, got, tt.want)
+		}
+	}
+}
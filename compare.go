@@ -0,0 +1,272 @@
+package rdf
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// CompareTerms returns an integer comparing two Terms, following the ordering
+// of SPARQL ORDER BY (http://www.w3.org/TR/sparql11-query/#modOrderBy). The
+// result is 0 if a and b are equal according to TermsEqual, -1 if a < b,
+// and +1 if a > b. A nil Term is less than any other Term.
+//
+// Terms are ordered by type: blank nodes, then IRIs, then literals, then quoted
+// triples. Blank nodes and IRIs are ordered by their identifiers, and quoted
+// triples as by CompareTriples. Numeric literals come before other literals,
+// and are ordered by value; NaN first, followed by -INF, the finite numbers
+// and +INF. Other literals are ordered by lexical form, then datatype and
+// language tag. Ties are broken by datatype and lexical form, so that the
+// ordering is total.
+//
+// CompareTerms does not allocate, and is suitable for sorting large numbers of Terms.
+func CompareTerms(a, b Term) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	ta, tb := a.Type(), b.Type()
+	if ta != tb {
+		if ta < tb {
+			return -1
+		}
+		return 1
+	}
+	switch ta {
+	case TermBlank:
+		return strings.Compare(a.(Blank).id, b.(Blank).id)
+	case TermIRI:
+		return strings.Compare(a.(IRI).str, b.(IRI).str)
+	case TermQuotedTriple:
+		return CompareTriples(a.(QuotedTriple).Triple, b.(QuotedTriple).Triple)
+	default:
+		return compareLiterals(a.(Literal), b.(Literal))
+	}
+}
+
+// CompareTriples returns an integer comparing two Triples, by subject, then
+// predicate, then object. See CompareTerms for the ordering of terms.
+func CompareTriples(a, b Triple) int {
+	if c := CompareTerms(a.Subj, b.Subj); c != 0 {
+		return c
+	}
+	if c := CompareTerms(a.Pred, b.Pred); c != 0 {
+		return c
+	}
+	return CompareTerms(a.Obj, b.Obj)
+}
+
+// CompareQuads returns an integer comparing two Quads, by subject, predicate
+// and object, then graph context. See CompareTerms for the ordering of terms.
+func CompareQuads(a, b Quad) int {
+	if c := CompareTriples(a.Triple, b.Triple); c != 0 {
+		return c
+	}
+	return CompareTerms(a.Ctx, b.Ctx)
+}
+
+// numericKind classifies the lexical form of a literal with a numeric datatype.
+type numericKind int
+
+const (
+	notNumeric numericKind = iota
+	numericExact
+	numericFloat
+)
+
+// numericKindOf returns the kind of the literal's value, and its float value if
+// it is a xsd:double or xsd:float. Literals with invalid lexical forms are not numeric.
+func numericKindOf(l Literal) (numericKind, float64) {
+	switch l.DataType {
+	case xsdDecimal:
+		if isDecimal(l.str) {
+			return numericExact, 0
+		}
+	case xsdDouble:
+		if f, ok := parseFloat(l.str, 64); ok {
+			return numericFloat, f
+		}
+	case xsdFloat:
+		if f, ok := parseFloat(l.str, 32); ok {
+			return numericFloat, f
+		}
+	default:
+		if valueSpaceOf(l.DataType) == spaceNumeric && isInteger(l.str) {
+			return numericExact, 0
+		}
+	}
+	return notNumeric, 0
+}
+
+func compareLiterals(a, b Literal) int {
+	ka, fa := numericKindOf(a)
+	kb, fb := numericKindOf(b)
+	switch {
+	case ka != notNumeric && kb == notNumeric:
+		return -1
+	case ka == notNumeric && kb != notNumeric:
+		return 1
+	case ka != notNumeric:
+		if c := compareNumeric(a.str, ka, fa, b.str, kb, fb); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.DataType.str, b.DataType.str); c != 0 {
+			return c
+		}
+		return strings.Compare(a.str, b.str)
+	}
+	if c := strings.Compare(a.str, b.str); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.DataType.str, b.DataType.str); c != 0 {
+		return c
+	}
+	return compareFold(a.lang, b.lang)
+}
+
+// compareNumeric compares two numbers by value. Exact numbers are compared by
+// their decimal lexical forms, and with floats by the exact decimal expansion
+// of the float.
+func compareNumeric(sa string, ka numericKind, fa float64, sb string, kb numericKind, fb float64) int {
+	if ka == numericFloat && kb == numericFloat {
+		return compareFloats(fa, fb)
+	}
+	if ka == numericExact && kb == numericExact {
+		return compareDecimals(sa, sb)
+	}
+	if ka == numericFloat {
+		return -compareFloatDecimal(sb, fa)
+	}
+	return compareFloatDecimal(sa, fb)
+}
+
+// compareFloats compares two floats, ordering NaN before any other value.
+func compareFloats(a, b float64) int {
+	switch {
+	case math.IsNaN(a) && math.IsNaN(b):
+		return 0
+	case math.IsNaN(a), a < b:
+		return -1
+	case math.IsNaN(b), a > b:
+		return 1
+	}
+	return 0
+}
+
+// compareFloatDecimal compares the value of an exact decimal lexical form with a float.
+func compareFloatDecimal(d string, f float64) int {
+	switch {
+	case math.IsNaN(f), math.IsInf(f, -1):
+		return 1
+	case math.IsInf(f, 1):
+		return -1
+	}
+	// Rounding is monotonic, so the decimal can only be equal to the
+	// float if it rounds to it.
+	if fd, ok := parseFloat(d, 64); ok && fd != f {
+		return compareFloats(fd, f)
+	}
+	// 1074 fractional digits are enough to hold the exact value of any float64.
+	var buf [1100]byte
+	return compareDecimals(d, strconv.AppendFloat(buf[:0], f, 'f', 1074, 64))
+}
+
+// compareDecimals compares two valid xsd:decimal (or xsd:integer) lexical forms by value.
+func compareDecimals[A, B string | []byte](a A, b B) int {
+	sa, ia, fa := splitDecimal(a)
+	sb, ib, fb := splitDecimal(b)
+	if sa != sb {
+		if sa < sb {
+			return -1
+		}
+		return 1
+	}
+	c := 0
+	if len(ia) != len(ib) {
+		c = 1
+		if len(ia) < len(ib) {
+			c = -1
+		}
+	} else if c = compareDigits(ia, ib); c == 0 {
+		c = compareDigits(fa, fb)
+	}
+	return c * sa
+}
+
+// splitDecimal splits a decimal lexical form into its sign (-1, 0 or 1), and
+// its integer and fractional digits, without leading and trailing zeros, respectively.
+func splitDecimal[T string | []byte](s T) (sign int, ints, frac T) {
+	sign = 1
+	if len(s) > 0 && (s[0] == '+' || s[0] == '-') {
+		if s[0] == '-' {
+			sign = -1
+		}
+		s = s[1:]
+	}
+	ints = s
+	for i := 0; i < len(s); i++ {
+		if s[i] == '.' {
+			ints, frac = s[:i], s[i+1:]
+			break
+		}
+	}
+	for len(ints) > 0 && ints[0] == '0' {
+		ints = ints[1:]
+	}
+	for len(frac) > 0 && frac[len(frac)-1] == '0' {
+		frac = frac[:len(frac)-1]
+	}
+	if len(ints) == 0 && len(frac) == 0 {
+		sign = 0
+	}
+	return sign, ints, frac
+}
+
+// compareDigits compares two strings of digits lexicographically.
+func compareDigits[A, B string | []byte](a A, b B) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+	return 0
+}
+
+// compareFold compares two ASCII strings case-insensitively.
+func compareFold(a, b string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		ca, cb := a[i], b[i]
+		if 'A' <= ca && ca <= 'Z' {
+			ca += 'a' - 'A'
+		}
+		if 'A' <= cb && cb <= 'Z' {
+			cb += 'a' - 'A'
+		}
+		if ca != cb {
+			if ca < cb {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,115 @@
+package rdf
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestCompareTerms(t *testing.T) {
+	// Terms in ascending order
+	terms := []Term{
+		nil,
+		Blank{id: "_:a"},
+		Blank{id: "_:b"},
+		IRI{str: "http://ex/a"},
+		IRI{str: "http://ex/b"},
+		NewTypedLiteral("NaN", xsdDouble),
+		NewTypedLiteral("-1e400", xsdDouble),
+		NewTypedLiteral("-INF", xsdFloat),
+		NewTypedLiteral("-10", xsdInteger),
+		NewTypedLiteral("-9.5", xsdDecimal),
+		NewTypedLiteral("-0.0", xsdDouble),
+		NewTypedLiteral("0", xsdInteger),
+		NewTypedLiteral("0.1", xsdDecimal),
+		NewTypedLiteral("0.1", xsdDouble),
+		NewTypedLiteral("0.1000000000000000055511151231257827021181583404541015626", xsdDecimal),
+		NewTypedLiteral("1", xsdByte),
+		NewTypedLiteral("1.0", xsdDecimal),
+		NewTypedLiteral("1.00", xsdDecimal),
+		NewTypedLiteral("1E0", xsdDouble),
+		NewTypedLiteral("01", xsdInteger),
+		NewTypedLiteral("1", xsdInteger),
+		NewTypedLiteral("9", xsdInteger),
+		NewTypedLiteral("10", xsdInt),
+		NewTypedLiteral("123456789012345678901234567890", xsdInteger),
+		NewTypedLiteral("INF", xsdDouble),
+		NewTypedLiteral("", xsdString),
+		NewTypedLiteral("1", xsdBoolean),
+		Literal{str: "a", lang: "en", DataType: rdfLangString},
+		Literal{str: "a", lang: "en-GB", DataType: rdfLangString},
+		NewTypedLiteral("a", xsdString),
+		NewTypedLiteral("x", xsdInteger),
+		NewTypedLiteral("x", xsdString),
+		QuotedTriple{Triple{Subj: IRI{str: "http://ex/a"}, Pred: IRI{str: "http://ex/p"}, Obj: IRI{str: "http://ex/a"}}},
+		QuotedTriple{Triple{Subj: IRI{str: "http://ex/a"}, Pred: IRI{str: "http://ex/p"}, Obj: NewTypedLiteral("1", xsdInteger)}},
+	}
+
+	for i, a := range terms {
+		for j, b := range terms {
+			want := 0
+			switch {
+			case i < j:
+				want = -1
+			case i > j:
+				want = 1
+			}
+			if got := CompareTerms(a, b); got != want {
+				t.Errorf("CompareTerms(%v, %v) => %d; want %d", a, b, got, want)
+			}
+		}
+	}
+
+	equal := []struct {
+		a, b Term
+	}{
+		{Literal{str: "a", lang: "en-GB", DataType: rdfLangString}, Literal{str: "a", lang: "en-gb", DataType: rdfLangString}},
+		{NewTypedLiteral("NaN", xsdDouble), NewTypedLiteral("NaN", xsdDouble)},
+	}
+	for _, tt := range equal {
+		if got := CompareTerms(tt.a, tt.b); got != 0 {
+			t.Errorf("CompareTerms(%v, %v) => %d; want 0", tt.a, tt.b, got)
+		}
+	}
+}
+
+func TestCompareTriples(t *testing.T) {
+	a, b, c := IRI{str: "http://ex/a"}, IRI{str: "http://ex/b"}, IRI{str: "http://ex/c"}
+	quads := []Quad{
+		{Triple{Subj: b, Pred: a, Obj: a}, c},
+		{Triple{Subj: a, Pred: b, Obj: a}, a},
+		{Triple{Subj: a, Pred: a, Obj: b}, a},
+		{Triple{Subj: a, Pred: a, Obj: a}, b},
+		{Triple{Subj: a, Pred: a, Obj: a}, a},
+	}
+	sort.Slice(quads, func(i, j int) bool { return CompareQuads(quads[i], quads[j]) < 0 })
+	want := []Quad{
+		{Triple{Subj: a, Pred: a, Obj: a}, a},
+		{Triple{Subj: a, Pred: a, Obj: a}, b},
+		{Triple{Subj: a, Pred: a, Obj: b}, a},
+		{Triple{Subj: a, Pred: b, Obj: a}, a},
+		{Triple{Subj: b, Pred: a, Obj: a}, c},
+	}
+	for i := range want {
+		if !QuadsEqual(quads[i], want[i]) {
+			t.Errorf("sorted quads[%d] => %v; want %v", i, quads[i], want[i])
+		}
+	}
+	if got := CompareTriples(want[3].Triple, want[4].Triple); got != -1 {
+		t.Errorf("CompareTriples(%v, %v) => %d; want -1", want[3].Triple, want[4].Triple, got)
+	}
+}
+
+func TestCompareTermsAllocs(t *testing.T) {
+	pairs := [][2]Term{
+		{IRI{str: "http://ex/a"}, IRI{str: "http://ex/b"}},
+		{Blank{id: "_:a"}, IRI{str: "http://ex/b"}},
+		{NewTypedLiteral("1.5", xsdDecimal), NewTypedLiteral("15", xsdInteger)},
+		{NewTypedLiteral("0.1", xsdDecimal), NewTypedLiteral("0.1", xsdDouble)},
+		{Literal{str: "a", lang: "en", DataType: rdfLangString}, Literal{str: "a", lang: "EN", DataType: rdfLangString}},
+	}
+	for _, p := range pairs {
+		if n := testing.AllocsPerRun(100, func() { CompareTerms(p[0], p[1]) }); n != 0 {
+			t.Errorf("CompareTerms(%v, %v) allocated %v times; want 0", p[0], p[1], n)
+		}
+	}
+}
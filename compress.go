@@ -0,0 +1,78 @@
+package rdf
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"strings"
+)
+
+// decompressor reads a document which may be compressed with gzip, bzip2
+// or zlib. The compression is detected from the magic bytes at the start
+// of the document, on the first read.
+type decompressor struct {
+	r        io.Reader // the document; the decompressed document after the first read
+	detected bool
+	err      error // error setting up the decompression, if any
+}
+
+// decompress returns a reader of the document read from r, decompressed if
+// it is compressed with gzip, bzip2 or zlib.
+func decompress(r io.Reader) io.Reader {
+	if _, ok := r.(*decompressor); ok {
+		return r
+	}
+	return &decompressor{r: r}
+}
+
+func (d *decompressor) Read(p []byte) (int, error) {
+	if !d.detected {
+		d.detected = true
+		d.r, d.err = detectCompression(d.r)
+	}
+	if d.err != nil {
+		return 0, d.err
+	}
+	return d.r.Read(p)
+}
+
+// detectCompression reads the first bytes of r, and returns a reader of
+// the document, decompressed if the bytes are the magic of a compression
+// format.
+func detectCompression(r io.Reader) (io.Reader, error) {
+	var buf [3]byte
+	n, err := io.ReadFull(r, buf[:])
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	magic := buf[:n]
+	r = io.MultiReader(bytes.NewReader(magic), r)
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		return gzip.NewReader(r)
+	case bytes.HasPrefix(magic, []byte("BZh")):
+		return bzip2.NewReader(r), nil
+	case len(magic) >= 2 && magic[0] == 0x78 && bytes.IndexByte([]byte{0x01, 0x5e, 0x9c, 0xda}, magic[1]) >= 0:
+		// Deflate with a 32K window, at one of the four compression
+		// levels. Other zlib headers are too likely to be plain text.
+		return zlib.NewReader(r)
+	}
+	return r, nil
+}
+
+// compressionExtensions are the file extensions of compressed files.
+var compressionExtensions = []string{".gz", ".bz2", ".zz"}
+
+// trimCompressionExt returns the file name without the extension of the
+// compression format, if any.
+func trimCompressionExt(name string) string {
+	lower := strings.ToLower(name)
+	for _, ext := range compressionExtensions {
+		if strings.HasSuffix(lower, ext) {
+			return name[:len(name)-len(ext)]
+		}
+	}
+	return name
+}
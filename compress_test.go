@@ -0,0 +1,160 @@
+package rdf
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// bzip2NT is the N-Triples document `<http://ex/s> <http://ex/p> "bz" .` compressed with bzip2.
+const bzip2NT = "\x42\x5a\x68\x39\x31\x41\x59\x26\x53\x59\x9e\x31\x83\x26\x00\x00\x08\x59\x80\x00\x10\x50\x01\x80\x15\x12\x40\x4c\x50\x20\x00\x31\x4c\x00\x13\x41\x2a\x68\xd1\xa6\x81\xea\x5a\x1d\x95\xc8\x20\x47\x47\x9a\xfa\x70\x6c\x45\x46\x10\x85\xf1\x77\x24\x53\x85\x09\x09\xe3\x18\x32\x60"
+
+func gzipString(s string) string {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write([]byte(s))
+	w.Close()
+	return buf.String()
+}
+
+func zlibString(s string) string {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write([]byte(s))
+	w.Close()
+	return buf.String()
+}
+
+func TestDecodeCompressed(t *testing.T) {
+	nt := "<http://ex/s> <http://ex/p> \"bz\" .\n"
+	for _, tt := range []struct {
+		name  string
+		input string
+	}{
+		{"plain", nt},
+		{"gzip", gzipString(nt)},
+		{"bzip2", bzip2NT},
+		{"zlib", zlibString(nt)},
+	} {
+		for _, f := range []Format{NTriples, Turtle} {
+			ts, err := NewTripleDecoder(strings.NewReader(tt.input), f).DecodeAll()
+			if err != nil || len(ts) != 1 || ts[0].Obj.String() != "bz" {
+				t.Errorf("%s %v: DecodeAll() => %v, %v", tt.name, f, ts, err)
+			}
+		}
+		qs, err := NewQuadDecoder(strings.NewReader(tt.input), NQuads).DecodeAll()
+		if err != nil || len(qs) != 1 {
+			t.Errorf("%s N-Quads: DecodeAll() => %v, %v", tt.name, qs, err)
+		}
+		qs, err = NewParallelDecoder(strings.NewReader(tt.input), NTriples, 2).DecodeAll()
+		if err != nil || len(qs) != 1 {
+			t.Errorf("%s parallel: DecodeAll() => %v, %v", tt.name, qs, err)
+		}
+		if f, r := DetectFormat(strings.NewReader(tt.input)); f != NTriples {
+			t.Errorf("%s: DetectFormat() => %v; want N-Triples", tt.name, f)
+		} else if b, err := io.ReadAll(r); err != nil || string(b) != nt {
+			t.Errorf("%s: DetectFormat() reader => %q, %v; want %q", tt.name, b, err, nt)
+		}
+	}
+
+	xml := `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"><rdf:Description rdf:about="http://ex/s"><rdf:value>x</rdf:value></rdf:Description></rdf:RDF>`
+	if ts, err := NewTripleDecoder(strings.NewReader(gzipString(xml)), RDFXML).DecodeAll(); err != nil || len(ts) != 1 {
+		t.Errorf("gzip RDF/XML: DecodeAll() => %v, %v", ts, err)
+	}
+
+	// Plain text starting like a zlib header is not decompressed.
+	ttl := "@prefix x: <http://ex/> .\nx:s x:p x:o .\n"
+	if ts, err := NewTripleDecoder(strings.NewReader(ttl), Turtle).DecodeAll(); err != nil || len(ts) != 1 {
+		t.Errorf("Turtle starting with x: DecodeAll() => %v, %v", ts, err)
+	}
+
+	// A truncated stream is reported after the complete lines before it.
+	var doc strings.Builder
+	for i := 0; i < 1000; i++ {
+		doc.WriteString(nt)
+	}
+	gz := gzipString(doc.String())
+	for _, f := range []Format{NTriples, Turtle, RDFXML} {
+		input := gz[:len(gz)/2]
+		if f == RDFXML {
+			desc := `<rdf:Description rdf:about="http://ex/s"><rdf:value>x</rdf:value></rdf:Description>`
+			input = gzipString(strings.Replace(xml, desc, strings.Repeat(desc, 1000), 1))
+			input = input[:len(input)/2]
+		}
+		n, err := 0, error(nil)
+		for _, err = range NewTripleDecoder(strings.NewReader(input), f).All() {
+			if err != nil {
+				break
+			}
+			n++
+		}
+		if !errors.Is(err, io.ErrUnexpectedEOF) || n == 0 {
+			t.Errorf("truncated gzip %v: %d triples, then %v; want some triples, then io.ErrUnexpectedEOF", f, n, err)
+		}
+	}
+
+	// A corrupt gzip header is reported.
+	if _, err := NewTripleDecoder(strings.NewReader("\x1f\x8bxxxxxxxxxx"), NTriples).Decode(); err == nil || err == io.EOF {
+		t.Errorf("corrupt gzip: Decode() => %v; want error", err)
+	}
+}
+
+func TestOpenCompressed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dump.NT.GZ")
+	if err := os.WriteFile(path, []byte(gzipString("<http://ex/s> <http://ex/p> <http://ex/o> .\n")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if ts, err := f.TripleDecoder().DecodeAll(); err != nil || len(ts) != 1 || f.Format != NTriples {
+		t.Errorf("Open(dump.NT.GZ) => %v %v, %v", f.Format, ts, err)
+	}
+}
+
+func TestEncodeGzip(t *testing.T) {
+	tr := Triple{Subj: IRI{str: "http://ex/s"}, Pred: IRI{str: "http://ex/p"}, Obj: IRI{str: "http://ex/o"}}
+	var buf bytes.Buffer
+	enc := NewTripleEncoder(&buf, Turtle)
+	enc.Gzip = true
+	if err := enc.Encode(tr); err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte{0x1f, 0x8b}) {
+		t.Fatalf("Turtle output is not gzip compressed: %q", buf.String())
+	}
+	if ts, err := NewTripleDecoder(&buf, Turtle).DecodeAll(); err != nil || len(ts) != 1 || !TriplesEqual(ts[0], tr) {
+		t.Errorf("decoding gzip Turtle => %v, %v; want [%v]", ts, err, tr)
+	}
+
+	qenc := NewQuadEncoder(&buf, NQuads)
+	qenc.Gzip = true
+	qenc.EncodeAll([]Quad{{Triple: tr, Ctx: IRI{str: "http://ex/g"}}})
+	if err := qenc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if qs, err := NewQuadDecoder(&buf, NQuads).DecodeAll(); err != nil || len(qs) != 1 {
+		t.Errorf("decoding gzip N-Quads => %v, %v", qs, err)
+	}
+
+	// Closing an encoder with no output writes an empty gzip stream.
+	enc = NewTripleEncoder(&buf, NTriples)
+	enc.Gzip = true
+	enc.Close()
+	if r, err := gzip.NewReader(&buf); err != nil {
+		t.Errorf("empty output: %v", err)
+	} else if b, err := io.ReadAll(r); err != nil || len(b) != 0 {
+		t.Errorf("empty output => %q, %v", b, err)
+	}
+}
@@ -1,11 +1,17 @@
 package rdf
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"runtime"
 )
 
+// ErrDecoderClosed is the error returned from Decode() when the Triple/Quad-Decoder is closed
+var ErrDecoderClosed = errors.New("Decoder is closed and cannot decode anymore")
+
 // A ParseOption allows to customize the behaviour of a decoder.
 type ParseOption int
 
@@ -15,52 +21,176 @@ const (
 	// relative IRIs: Turtle, RDF/XML, TriG, JSON-LD)
 	Base ParseOption = iota
 
+	// BlankNodes sets the BlankAllocator used to label blank nodes. When
+	// not set, anonymous blank nodes are labelled b1, b2.., and blank node
+	// labels from the document are kept as they are.
+	BlankNodes
+
+	// Intern sets a Dictionary in which the decoder interns IRIs, including
+	// the datatypes of literals, as they are decoded. Equal IRIs then share
+	// the memory of their strings, which saves a lot of memory when keeping
+	// many triples around.
+	Intern
+
 	// Strict mode determines how the decoder responds to errors.
 	// When true (the default), it will fail on any malformed input. When
 	// false, it will try to continue parsing, discarding only the malformed
-	// parts.
-	// Strict
+	// parts: N-Triples and N-Quads decoders skip the malformed line, the
+	// Turtle decoder skips to the end of the malformed statement, and the
+	// RDF/XML decoder skips the malformed node element. Triples decoded
+	// from a malformed statement or element before the error was found are
+	// still returned. Errors in the XML syntax itself cannot be recovered from.
+	Strict
+
+	// ErrOut receives the errors skipped when Strict is false. It can be an
+	// io.Writer, to which each error is written on a line of its own, or a
+	// func(error) which is called with each error. When not set, the errors
+	// are discarded.
+	ErrOut
+
+	// Ordered determines the order the ParallelDecoder returns quads in.
+	// When true (the default), they are returned in the order of the
+	// document. When false, the quads of a chunk of the document are
+	// returned as soon as the chunk is parsed.
+	Ordered
+
+	// The Max... options limit the resources a document can make the
+	// decoder use, to guard against hostile input. Each is an int; zero,
+	// the default, means no limit. A document exceeding a limit fails with
+	// an error wrapping ErrLiteralTooLong, ErrIRITooLong, ErrLineTooLong,
+	// ErrNestingTooDeep, ErrXMLTooDeep or ErrTooManyTriples, even when
+	// Strict is false.
+
+	// MaxLiteralLen limits the length in bytes of literals, as written in
+	// the document. The RDF/XML decoder checks it only after the XML token
+	// holding the literal is read whole into memory; use MaxLineLen, or
+	// limit the size of the input, to bound the memory used by RDF/XML
+	// documents.
+	MaxLiteralLen
+
+	// MaxIRILen limits the length in bytes of IRIs, as written in the
+	// document; in Turtle, the length of the prefix and of the local name of
+	// a prefixed name are limited separately. Like MaxLiteralLen, the
+	// RDF/XML decoder checks it only after the XML token holding the IRI is
+	// read whole into memory.
+	MaxIRILen
+
+	// MaxLineLen limits the length in bytes of lines. Lines are read whole
+	// into memory, so a document without line breaks is otherwise read
+	// whole.
+	MaxLineLen
+
+	// MaxNesting limits how deep collections and blank node property lists
+	// can be nested in Turtle.
+	MaxNesting
 
-	// ErrOut
+	// MaxXMLDepth limits how deep elements can be nested in RDF/XML.
+	MaxXMLDepth
+
+	// MaxTriples limits the number of triples (or quads) decoded from a
+	// document.
+	MaxTriples
 )
 
 // TripleDecoder parses RDF documents (serializations of an RDF graph).
 //
 // For streaming parsing, use the Decode() method to decode a single Triple
-// at a time. Or, if you want to read the whole document in one go, use DecodeAll().
+// at a time, or range over All(). Or, if you want to read the whole document
+// in one go, use DecodeAll(). A decoder which is abandoned before the whole
+// document is parsed can be closed with Close(), to release its buffers.
+//
+// Documents compressed with gzip, bzip2 or zlib are decompressed
+// transparently; the compression is detected from their first bytes.
 //
 // The decoder can be instructed with numerous options. Note that not all options
 // are supported by all formats. Consult the following table:
 //
-//  Option      Description        Value      (default)       Format support
+//  Option        Description        Value      (default)      Format support
 //  ------------------------------------------------------------------------------
-//  Base        Base IRI           IRI        (empty IRI)     Turtle, RDF/XML
-//  Strict      Strict mode        true/false (true)          TODO
-//  ErrOut      Error output       io.Writer  (nil)           TODO
+//  Base          Base IRI           IRI        (empty IRI)    Turtle, RDF/XML
+//  BlankNodes    Blank node labels  BlankAllocator (nil)      All
+//  Intern        Intern IRIs        *Dictionary (nil)         All
+//  Strict        Strict mode        true/false (true)         All
+//  ErrOut        Error output       io.Writer or func(error) (nil) All
+//  Ordered       Keep input order   true/false (true)         ParallelDecoder
+//  MaxLiteralLen Literal length     int        (0: no limit)  All
+//  MaxIRILen     IRI length         int        (0: no limit)  All
+//  MaxLineLen    Line length        int        (0: no limit)  All
+//  MaxNesting    Nesting depth      int        (0: no limit)  Turtle
+//  MaxXMLDepth   Element depth      int        (0: no limit)  RDF/XML
+//  MaxTriples    Triple count       int        (0: no limit)  All
 type TripleDecoder interface {
 	// Decode parses a RDF document and return the next valid triple.
-	// It returns io.EOF when the whole document is parsed.
+	// It returns io.EOF when the whole document is parsed, and a
+	// *ParseError when the document is malformed.
 	Decode() (Triple, error)
 
 	// DecodeAll parses the entire RDF document and return all valid
 	// triples, or an error.
 	DecodeAll() ([]Triple, error)
 
+	// All returns an iterator over the remaining triples of the document.
+	// It stops at the end of the document, or after yielding an error with
+	// a zero Triple:
+	//
+	//  for t, err := range dec.All() {
+	//  	if err != nil {
+	//  		return err
+	//  	}
+	//  	// do something with t ..
+	//  }
+	//
+	// Breaking out of the loop leaves the decoder usable.
+	All() iter.Seq2[Triple, error]
+
 	// SetOption sets a parsing option to the given value. Not all options
 	// are supported by all serialization formats.
 	SetOption(ParseOption, interface{}) error
+
+	// Prefixes returns the namespace prefixes declared in the document so
+	// far, mapped to their namespace IRIs. The map is a copy, and can be
+	// modified by the caller. Formats without prefixes return nil.
+	//
+	// The prefixes can be passed on to a TripleEncoder, to keep them when
+	// converting a document:
+	//
+	//  for prefix, ns := range dec.Prefixes() {
+	//  	enc.Namespaces[ns] = prefix
+	//  }
+	Prefixes() map[string]string
+
+	// Base returns the base IRI of the document; the last one declared
+	// so far, or the one given by the Base option. It returns the empty
+	// IRI for formats without a base IRI.
+	Base() IRI
+
+	// Close stops the decoder, and releases its resources. Decode returns
+	// ErrDecoderClosed after Close. It does not close the io.Reader.
+	Close() error
 }
 
 // NewTripleDecoder returns a new TripleDecoder capable of parsing triples
 // from the given io.Reader in the given serialization format.
 func NewTripleDecoder(r io.Reader, f Format) TripleDecoder {
+	return NewTripleDecoderContext(context.Background(), r, f)
+}
+
+// NewTripleDecoderContext is like NewTripleDecoder, but the decoder stops
+// when the given context is done; Decode then returns the context's error,
+// and the decoder need not be closed.
+//
+// A Decode blocked on a read from the io.Reader returns as soon as the
+// context is done. The read goes on in the background until the io.Reader
+// returns, and what it read is discarded.
+func NewTripleDecoderContext(ctx context.Context, r io.Reader, f Format) TripleDecoder {
+	r = decompress(r)
 	switch f {
 	case NTriples:
-		return newNTDecoder(r)
+		return newNTDecoder(ctx, r)
 	case RDFXML:
-		return newRDFXMLDecoder(r)
+		return newRDFXMLDecoder(ctx, r)
 	case Turtle:
-		return newTTLDecoder(r)
+		return newTTLDecoder(ctx, r)
 	default:
 		panic(fmt.Errorf("Decoder for serialization format %v not implemented", f))
 	}
@@ -70,29 +200,92 @@ func NewTripleDecoder(r io.Reader, f Format) TripleDecoder {
 // N-Quads.
 //
 // For streaming parsing, use the Decode() method to decode a single Quad
-// at a time. Or, if you want to read the whole source in one go, DecodeAll().
+// at a time, or range over All(). Or, if you want to read the whole source in
+// one go, DecodeAll().
+// A decoder which is abandoned before the whole source is parsed can be
+// closed with Close(), to release its buffers. Like the TripleDecoder, it
+// decompresses sources compressed with gzip, bzip2 or zlib.
 type QuadDecoder struct {
 	l      *lexer
 	format Format
+	bnodes BlankAllocator // blank node label allocator, if any
+	dict   *Dictionary    // dictionary to intern IRIs in, if any
+	errs   errSink        // handling of malformed input
 
 	DefaultGraph Context  // default graph
 	tokens       [3]token // 3 token lookahead
 	peekCount    int      // number of tokens peeked at (position in tokens lookahead array)
+	last         token    // the token consumed last
 }
 
 // NewQuadDecoder returns a new QuadDecoder capable of parsing quads
 // from the given io.Reader in the given serialization format.
 func NewQuadDecoder(r io.Reader, f Format) *QuadDecoder {
-	return &QuadDecoder{
-		l:            newLineLexer(r),
+	return NewQuadDecoderContext(context.Background(), r, f)
+}
+
+// NewQuadDecoderContext is like NewQuadDecoder, but the decoder stops when
+// the given context is done; Decode then returns the context's error, and
+// the decoder need not be closed.
+func NewQuadDecoderContext(ctx context.Context, r io.Reader, f Format) *QuadDecoder {
+	return newQuadDecoder(ctx, decompress(r), f)
+}
+
+// newQuadDecoder returns a new QuadDecoder of the uncompressed document
+// read from r.
+func newQuadDecoder(ctx context.Context, r io.Reader, f Format) *QuadDecoder {
+	d := &QuadDecoder{
+		l:            newLineLexer(ctx, r),
 		format:       f,
 		DefaultGraph: Blank{id: "_:defaultGraph"},
 	}
+	d.l.lim.format = f
+	return d
+}
+
+// SetOption sets a ParseOption to the give value
+func (d *QuadDecoder) SetOption(o ParseOption, v interface{}) error {
+	switch o {
+	case BlankNodes:
+		a, ok := v.(BlankAllocator)
+		if !ok {
+			return fmt.Errorf("ParseOption \"BlankNodes\" must be a BlankAllocator.")
+		}
+		d.bnodes = a
+	case Intern:
+		dict, ok := v.(*Dictionary)
+		if !ok {
+			return fmt.Errorf("ParseOption \"Intern\" must be a *Dictionary.")
+		}
+		d.dict = dict
+	case Strict, ErrOut:
+		return d.errs.setOption(o, v)
+	case MaxLiteralLen, MaxIRILen, MaxLineLen, MaxTriples:
+		return d.l.lim.setOption(o, v)
+	default:
+		return fmt.Errorf("N-Quads decoder doesn't support option: %v", o)
+	}
+	return nil
 }
 
 // Decode returns the next valid Quad, or an error
 func (d *QuadDecoder) Decode() (Quad, error) {
-	return d.parseNQ()
+	for {
+		if err := d.l.err(); err != nil {
+			return Quad{}, err
+		}
+		q, err := d.parseNQ()
+		if err == nil {
+			if err = d.l.lim.countTriple(); err != nil {
+				return Quad{}, err
+			}
+			return q, nil
+		}
+		if !d.errs.skip(err) {
+			return q, err
+		}
+		d.skipLine()
+	}
 }
 
 // DecodeAll decodes and returns all Quads from source, or an error
@@ -107,6 +300,61 @@ func (d *QuadDecoder) DecodeAll() ([]Quad, error) {
 	return qs, nil
 }
 
+// All returns an iterator over the remaining Quads from source. It stops
+// at the end of the source, or after yielding an error.
+func (d *QuadDecoder) All() iter.Seq2[Quad, error] {
+	return decodeSeq(d.Decode)
+}
+
+// decodeSeq returns an iterator over the values returned by decode, until
+// it returns io.EOF or another error; the error is yielded, io.EOF is not.
+func decodeSeq[T any](decode func() (T, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			v, err := decode()
+			if err == io.EOF {
+				return
+			}
+			if !yield(v, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Close stops the decoder, and releases its resources. Decode returns
+// ErrDecoderClosed after Close. It does not close the io.Reader.
+func (d *QuadDecoder) Close() error {
+	d.l.stop()
+	return nil
+}
+
+// blank returns the blank node with the given label (including the '_:' prefix),
+// scoped to the document if a BlankAllocator is set.
+func (d *QuadDecoder) blank(label string) Blank {
+	if d.bnodes == nil {
+		return Blank{id: label}
+	}
+	return Blank{id: "_:" + d.bnodes.Scope(label[2:])}
+}
+
+// langTag returns the language tag of the token in canonical case.
+func (d *QuadDecoder) langTag(tok token) string {
+	lang, err := parseLangTag(string(tok.text))
+	if err != nil {
+		e := parseErrorAt(d.format, tok, InvalidTerm, "bad literal: %v", err)
+		e.Err = err
+		panic(e)
+	}
+	return lang
+}
+
+// skipLine skips the remaining tokens on the line of the malformed quad.
+func (d *QuadDecoder) skipLine() {
+	for tok := d.last; !endsLine(tok); tok = d.next() {
+	}
+}
+
 // next returns the next token.
 func (d *QuadDecoder) next() token {
 	if d.peekCount > 0 {
@@ -114,8 +362,8 @@ func (d *QuadDecoder) next() token {
 	} else {
 		d.tokens[0] = d.l.nextToken()
 	}
-
-	return d.tokens[d.peekCount]
+	d.last = d.tokens[d.peekCount]
+	return d.last
 }
 
 // peek returns but does not consume the next token.
@@ -148,7 +396,7 @@ func (d *QuadDecoder) expect1As(context string, expected tokenType) token {
 	t := d.next()
 	if t.typ != expected {
 		if t.typ == tokenError {
-			d.errorf("%d:%d: syntax error: %s", t.line, t.col, t.text)
+			d.errorf(t, SyntaxError, "syntax error: %s", string(t.text))
 		} else {
 			d.unexpected(t, context)
 		}
@@ -165,20 +413,123 @@ func (d *QuadDecoder) expectAs(context string, expected ...tokenType) token {
 		}
 	}
 	if t.typ == tokenError {
-		d.errorf("%d:%d: syntax error: %v", t.line, t.col, t.text)
+		d.errorf(t, SyntaxError, "syntax error: %s", string(t.text))
 	} else {
 		d.unexpected(t, context)
 	}
 	return t
 }
 
-// errorf formats the error and terminates parsing.
-func (d *QuadDecoder) errorf(format string, args ...interface{}) {
-	format = fmt.Sprintf("%s", format)
-	panic(fmt.Errorf(format, args...))
+// errorf formats the error, located at the given token, and terminates parsing.
+func (d *QuadDecoder) errorf(tok token, kind ErrorKind, format string, args ...interface{}) {
+	panic(parseErrorAt(d.format, tok, kind, format, args...))
 }
 
 // unexpected complains about the given token and terminates parsing.
 func (d *QuadDecoder) unexpected(t token, context string) {
-	d.errorf("%d:%d unexpected %v as %s", t.line, t.col, t.typ, context)
+	d.errorf(t, UnexpectedToken, "unexpected %v as %s", t.typ, context)
+}
+
+// errSink implements the Strict and ErrOut options, which are common to
+// all decoders.
+type errSink struct {
+	lenient bool        // true when not in strict mode
+	report  func(error) // receives the errors skipped in lenient mode, if set
+}
+
+// setOption sets the Strict or ErrOut option to the given value.
+func (s *errSink) setOption(o ParseOption, v interface{}) error {
+	switch o {
+	case Strict:
+		strict, ok := v.(bool)
+		if !ok {
+			return fmt.Errorf("ParseOption \"Strict\" must be a bool.")
+		}
+		s.lenient = !strict
+	case ErrOut:
+		switch out := v.(type) {
+		case func(error):
+			s.report = out
+		case io.Writer:
+			s.report = func(err error) { fmt.Fprintln(out, err) }
+		case nil:
+			s.report = nil
+		default:
+			return fmt.Errorf("ParseOption \"ErrOut\" must be an io.Writer or a func(error).")
+		}
+	}
+	return nil
+}
+
+// skip reports whether the decoder should skip the malformed input causing
+// err, and continue decoding. If so, the error is reported to ErrOut. Only
+// parse errors are skipped; not io.EOF, the decoder being stopped, or the
+// document exceeding a limit.
+func (s *errSink) skip(err error) bool {
+	perr, ok := err.(*ParseError)
+	if !ok || perr.Kind == LimitExceeded || !s.lenient {
+		return false
+	}
+	if s.report != nil {
+		s.report(err)
+	}
+	return true
+}
+
+// endsLine reports whether the token ends a line in the line-based formats.
+// The lexer skips the rest of the line after an error token.
+func endsLine(tok token) bool {
+	switch tok.typ {
+	case tokenEOL, tokenError, tokenEOF:
+		return true
+	}
+	return false
+}
+
+// ctxReader reads from r until ctx is done. A read blocked when ctx is done
+// returns the cause at once; the read goes on in its goroutine until r
+// returns, and its result is discarded.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+	buf []byte // the buffer reads from r are made into
+	err error  // the cause of ctx being done, once a read was abandoned
+}
+
+// readResult is the result of a read from a ctxReader's io.Reader.
+type readResult struct {
+	n   int
+	err error
+}
+
+// cancelable returns a reader of r which stops when ctx is done, or r itself
+// if ctx is never done.
+func cancelable(ctx context.Context, r io.Reader) io.Reader {
+	if ctx.Done() == nil {
+		return r
+	}
+	return &ctxReader{ctx: ctx, r: r}
+}
+
+func (r *ctxReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	if cap(r.buf) < len(p) {
+		r.buf = make([]byte, len(p))
+	}
+	buf := r.buf[:len(p)]
+	done := make(chan readResult, 1)
+	go func() {
+		n, err := r.r.Read(buf)
+		done <- readResult{n, err}
+	}()
+	select {
+	case res := <-done:
+		return copy(p, buf[:res.n]), res.err
+	case <-r.ctx.Done():
+		// buf is not reused, since the read may still write to it.
+		r.buf, r.err = nil, context.Cause(r.ctx)
+		return 0, r.err
+	}
 }
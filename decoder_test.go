@@ -0,0 +1,418 @@
+package rdf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeLenient(t *testing.T) {
+	tests := []struct {
+		format Format
+		input  string
+		want   []string // triples or quads as N-Triples/N-Quads
+		errs   int
+	}{
+		{
+			NTriples,
+			"<http://a> <http://b> <http://c> .\n" +
+				"<http://a> <http://b> \"x\\q\" .\n" +
+				"<http://a> <http://b> .\n" +
+				"<http://a> <http://b> <http://c> <http://d> .\n" +
+				"<http://a> <http://b> \"y\"@1-x .\n" +
+				"<http://a> <http://b> <http://e> .\n",
+			[]string{
+				"<http://a> <http://b> <http://c> .",
+				"<http://a> <http://b> <http://e> .",
+			},
+			4,
+		},
+		{
+			NQuads,
+			"<http://a> <http://b> <http://c> <http://g> .\n" +
+				"<http://a> <http://b> <http://c> \"g\" .\n" +
+				"<http://a> <http://b> <http://c> <http://g> <http://h> .\n" +
+				"_:a <http://b> _:c .\n",
+			[]string{
+				"<http://a> <http://b> <http://c> <http://g> .",
+				"_:a <http://b> _:c _:defaultGraph .",
+			},
+			2,
+		},
+		{
+			Turtle,
+			"@prefix ex: <http://ex/> .\n" +
+				"ex:a ex:b ex:c ; ex:d nope:x ; ex:e ex:f .\n" +
+				"ex:a ex:b \"bad\\q\" ;\n" +
+				"  ex:d ex:e .\n" +
+				"ex:g ex:h ex:i\n" +
+				"ex:j ex:k ex:l ex:m .\n" +
+				"ex:n ex:o [ ex:p ex:q ] .\n",
+			[]string{
+				"<http://ex/a> <http://ex/b> <http://ex/c> .",
+				"<http://ex/n> <http://ex/o> _:b1 .",
+				"_:b1 <http://ex/p> <http://ex/q> .",
+			},
+			3,
+		},
+		{
+			Turtle,
+			"@prefix ex: <http://ex/> .\n" +
+				"ex:s ex:p <bad iri> ;\n" +
+				"  ex:q ex:o .\n" +
+				"ex:s ex:p \"bad\\q\" .\n" +
+				"ex:s ex:p ex:o .\n",
+			[]string{
+				"<http://ex/s> <http://ex/p> <http://ex/o> .",
+			},
+			2,
+		},
+		{
+			Turtle,
+			"<http://ex/a> <http://ex/b> bad . <http://ex/c> <http://ex/d> <http://ex/e> .\n" +
+				"<http://ex/a> <http://ex/b> \"bad\\q. x\" . <http://ex/f> <http://ex/g> \"y.\" .\n" +
+				"<http://ex/h> <http://ex/i> <http://ex/j> .\n",
+			[]string{
+				"<http://ex/c> <http://ex/d> <http://ex/e> .",
+				"<http://ex/f> <http://ex/g> \"y.\" .",
+				"<http://ex/h> <http://ex/i> <http://ex/j> .",
+			},
+			2,
+		},
+		{
+			RDFXML,
+			`<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns:ex="http://ex/">
+  <rdf:Description rdf:about="http://ex/a">
+    <ex:b rdf:resource="http://ex/c"/>
+  </rdf:Description>
+  <rdf:Description rdf:about="http://ex/d">
+    <ex:e rdf:resource="http://ex/f"/>
+    <rdf:Description rdf:about="http://ex/g"/>
+    <ex:e rdf:resource="http://ex/h"/>
+  </rdf:Description>
+  <rdf:li/>
+  <rdf:Description rdf:about="http://ex/i">
+    <ex:j rdf:resource="http://ex/k"/>
+  </rdf:Description>
+</rdf:RDF>`,
+			[]string{
+				"<http://ex/a> <http://ex/b> <http://ex/c> .",
+				"<http://ex/d> <http://ex/e> <http://ex/f> .",
+				"<http://ex/i> <http://ex/j> <http://ex/k> .",
+			},
+			2,
+		},
+	}
+
+	for _, tt := range tests {
+		var errs []error
+		var got []string
+		if tt.format == NQuads {
+			dec := NewQuadDecoder(strings.NewReader(tt.input), NQuads)
+			dec.SetOption(Strict, false)
+			dec.SetOption(ErrOut, func(err error) { errs = append(errs, err) })
+			qs, err := dec.DecodeAll()
+			if err != nil {
+				t.Errorf("N-Quads: lenient decoding failed with %v", err)
+				continue
+			}
+			for _, q := range qs {
+				got = append(got, strings.TrimSpace(q.Serialize(NQuads)))
+			}
+		} else {
+			dec := NewTripleDecoder(strings.NewReader(tt.input), tt.format)
+			dec.SetOption(Strict, false)
+			dec.SetOption(ErrOut, func(err error) { errs = append(errs, err) })
+			ts, err := dec.DecodeAll()
+			if err != nil {
+				t.Errorf("%v: lenient decoding failed with %v", tt.format, err)
+				continue
+			}
+			for _, tr := range ts {
+				got = append(got, strings.TrimSpace(tr.Serialize(NTriples)))
+			}
+		}
+		if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("%v: lenient decoding =>\n%s\nwant:\n%s", tt.format, strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+		}
+		if len(errs) != tt.errs {
+			t.Errorf("%v: lenient decoding reported %d errors %v; want %d", tt.format, len(errs), errs, tt.errs)
+		}
+	}
+}
+
+func TestDecodeErrOut(t *testing.T) {
+	var buf bytes.Buffer
+	dec := NewTripleDecoder(strings.NewReader("<a> <b> <c> .\n<http://a> <http://b> <http://c> .\n"), NTriples)
+	if err := dec.SetOption(Strict, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := dec.SetOption(ErrOut, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dec.Decode(); err != nil {
+		t.Fatalf("Decode() failed with %v; want no error", err)
+	}
+	if _, err := dec.Decode(); err != io.EOF {
+		t.Fatalf("Decode() => %v; want io.EOF", err)
+	}
+	if want := "1:1: unexpected IRI (relative) as subject\n"; buf.String() != want {
+		t.Errorf("ErrOut got %q; want %q", buf.String(), want)
+	}
+
+	// Strict is the default.
+	dec = NewTripleDecoder(strings.NewReader("<a> <b> <c> .\n"), NTriples)
+	if _, err := dec.DecodeAll(); err == nil {
+		t.Error("strict decoding of malformed input => no error; want error")
+	}
+
+	if err := dec.SetOption(Strict, "no"); err == nil {
+		t.Error("SetOption(Strict, \"no\") => no error; want error")
+	}
+	if err := dec.SetOption(ErrOut, 1); err == nil {
+		t.Error("SetOption(ErrOut, 1) => no error; want error")
+	}
+}
+
+func TestDecoderPrefixes(t *testing.T) {
+	tests := []struct {
+		format   Format
+		input    string
+		prefixes map[string]string
+		base     IRI
+	}{
+		{
+			NTriples,
+			"<http://a> <http://b> <http://c> .\n",
+			nil,
+			IRI{},
+		},
+		{
+			Turtle,
+			"@base <http://ex/> .\n" +
+				"@prefix ex: <http://ex/> .\n" +
+				"PREFIX dc: <http://purl.org/dc/terms/>\n" +
+				"@prefix : <rel/> .\n" +
+				"@prefix ex: <http://ex2/> .\n" +
+				"<a> dc:title \"a\" .\n",
+			map[string]string{"ex": "http://ex2/", "dc": "http://purl.org/dc/terms/", "": "http://ex/rel/"},
+			IRI{str: "http://ex/"},
+		},
+		{
+			RDFXML,
+			`<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://ex/" xml:base="http://ex/base">
+  <rdf:Description rdf:about="a" xmlns:dc="http://purl.org/dc/terms/">
+    <dc:title>a</dc:title>
+  </rdf:Description>
+</rdf:RDF>`,
+			map[string]string{"rdf": "http://www.w3.org/1999/02/22-rdf-syntax-ns#", "": "http://ex/", "dc": "http://purl.org/dc/terms/"},
+			IRI{str: "http://ex/base"},
+		},
+	}
+
+	for _, tt := range tests {
+		dec := NewTripleDecoder(strings.NewReader(tt.input), tt.format)
+		if _, err := dec.DecodeAll(); err != nil {
+			t.Errorf("%v: decoding failed with %v", tt.format, err)
+			continue
+		}
+		if got := dec.Prefixes(); !reflect.DeepEqual(got, tt.prefixes) {
+			t.Errorf("%v: Prefixes() => %v; want %v", tt.format, got, tt.prefixes)
+		}
+		if got := dec.Base(); got != tt.base {
+			t.Errorf("%v: Base() => %v; want %v", tt.format, got, tt.base)
+		}
+	}
+
+	// The Base option is returned when the document doesn't declare one.
+	dec := NewTripleDecoder(strings.NewReader("<a> <b> <c> ."), Turtle)
+	dec.SetOption(Base, IRI{str: "http://ex/"})
+	if got, want := dec.Base(), (IRI{str: "http://ex/"}); got != want {
+		t.Errorf("Base() => %v; want %v", got, want)
+	}
+}
+
+func TestConvertKeepsPrefixes(t *testing.T) {
+	input := `@prefix dc: <http://purl.org/dc/terms/> .
+@prefix ex: <http://example.org/> .
+ex:book dc:title "Title" .
+`
+	dec := NewTripleDecoder(strings.NewReader(input), Turtle)
+	var buf bytes.Buffer
+	enc := NewTripleEncoder(&buf, Turtle)
+	for tr, err := dec.Decode(); err != io.EOF; tr, err = dec.Decode() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		for prefix, ns := range dec.Prefixes() {
+			enc.Namespaces[ns] = prefix
+		}
+		if err := enc.Encode(tr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	want := "@prefix dc:\t<http://purl.org/dc/terms/> .\n@prefix ex:\t<http://example.org/> .\nex:book\tdc:title\t\"Title\" ."
+	if buf.String() != want {
+		t.Errorf("Turtle conversion =>\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestDecoderClose(t *testing.T) {
+	var nq, ttl, xml bytes.Buffer
+	xml.WriteString(`<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns:ex="http://ex/">` + "\n")
+	for i := 0; i < 100; i++ {
+		fmt.Fprintf(&nq, "<http://ex/s%d> <http://ex/p> <http://ex/o> <http://ex/g> .\n", i)
+		fmt.Fprintf(&ttl, "<http://ex/s%d> <http://ex/p> <http://ex/o> .\n", i)
+		fmt.Fprintf(&xml, "<rdf:Description rdf:about=\"http://ex/s%d\"><ex:p rdf:resource=\"http://ex/o\"/></rdf:Description>\n", i)
+	}
+	xml.WriteString("</rdf:RDF>\n")
+
+	before := runtime.NumGoroutine()
+	for _, tt := range []struct {
+		format Format
+		input  string
+	}{
+		{NTriples, ttl.String()},
+		{Turtle, ttl.String()},
+		{RDFXML, xml.String()},
+	} {
+		dec := NewTripleDecoder(strings.NewReader(tt.input), tt.format)
+		if _, err := dec.Decode(); err != nil {
+			t.Fatalf("%v: Decode() failed with %v", tt.format, err)
+		}
+		if err := dec.Close(); err != nil {
+			t.Fatalf("%v: Close() failed with %v", tt.format, err)
+		}
+		if _, err := dec.Decode(); err != ErrDecoderClosed {
+			t.Errorf("%v: Decode() after Close() => %v; want ErrDecoderClosed", tt.format, err)
+		}
+		if err := dec.Close(); err != nil {
+			t.Errorf("%v: second Close() failed with %v", tt.format, err)
+		}
+	}
+	qdec := NewQuadDecoder(strings.NewReader(nq.String()), NQuads)
+	if _, err := qdec.Decode(); err != nil {
+		t.Fatalf("N-Quads: Decode() failed with %v", err)
+	}
+	qdec.Close()
+	if _, err := qdec.Decode(); err != ErrDecoderClosed {
+		t.Errorf("N-Quads: Decode() after Close() => %v; want ErrDecoderClosed", err)
+	}
+
+	waitGoroutines(t, before)
+}
+
+func TestDecoderContext(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	// Cancel while the decoder waits for input.
+	pr, pw := io.Pipe()
+	go pw.Write([]byte("<http://a> <http://b> <http://c> .\n"))
+	ctx, cancel := context.WithCancel(context.Background())
+	dec := NewTripleDecoderContext(ctx, pr, NTriples)
+	if _, err := dec.Decode(); err != nil {
+		t.Fatalf("Decode() failed with %v", err)
+	}
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if _, err := dec.Decode(); err != context.Canceled {
+		t.Errorf("Decode() after cancel => %v; want context.Canceled", err)
+	}
+	pw.Close()
+
+	// The other decoders stop as well while waiting for input.
+	for _, f := range []Format{Turtle, RDFXML} {
+		pr, pw := io.Pipe()
+		ctx, cancel := context.WithCancel(context.Background())
+		dec := NewTripleDecoderContext(ctx, pr, f)
+		time.AfterFunc(10*time.Millisecond, cancel)
+		if _, err := dec.Decode(); err != context.Canceled {
+			t.Errorf("%v: Decode() after cancel => %v; want context.Canceled", f, err)
+		}
+		pw.Close()
+	}
+
+	// A done context stops all the decoders.
+	ctx, cancel = context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	input := "<http://a> <http://b> <http://c> .\n"
+	for _, f := range []Format{NTriples, Turtle, RDFXML} {
+		dec := NewTripleDecoderContext(ctx, strings.NewReader(input), f)
+		if _, err := dec.Decode(); err != context.DeadlineExceeded {
+			t.Errorf("%v: Decode() with done context => %v; want context.DeadlineExceeded", f, err)
+		}
+	}
+	qdec := NewQuadDecoderContext(ctx, strings.NewReader(input), NQuads)
+	if _, err := qdec.Decode(); err != context.DeadlineExceeded {
+		t.Errorf("N-Quads: Decode() with done context => %v; want context.DeadlineExceeded", err)
+	}
+
+	waitGoroutines(t, before)
+}
+
+// waitGoroutines fails the test if the number of goroutines does not drop
+// to n within a second.
+func waitGoroutines(t *testing.T, n int) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		if runtime.NumGoroutine() <= n {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("%d goroutines running; want %d", runtime.NumGoroutine(), n)
+}
+
+func TestDecoderAll(t *testing.T) {
+	input := "<http://ex/a> <http://ex/p> <http://ex/b> .\n<http://ex/b> <http://ex/p> <http://ex/c> .\n"
+	for _, f := range []Format{NTriples, Turtle} {
+		var got []Triple
+		for tr, err := range NewTripleDecoder(strings.NewReader(input), f).All() {
+			if err != nil {
+				t.Fatalf("%v: All() yielded error %v", f, err)
+			}
+			got = append(got, tr)
+		}
+		if len(got) != 2 || got[1].Obj != (IRI{str: "http://ex/c"}) {
+			t.Errorf("%v: All() => %v; want 2 triples", f, got)
+		}
+	}
+
+	// An error is yielded once, and ends the iteration.
+	dec := NewTripleDecoder(strings.NewReader("<http://ex/a> <http://ex/p> <http://ex/b> .\n<http://ex/b> <http://ex/p> .\n<http://ex/c> <http://ex/p> <http://ex/d> .\n"), NTriples)
+	var n int
+	var errs []error
+	for _, err := range dec.All() {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		n++
+	}
+	var perr *ParseError
+	if n != 1 || len(errs) != 1 || !errors.As(errs[0], &perr) || perr.Line != 2 {
+		t.Errorf("All() on malformed input => %d triples, errors %v; want 1 triple, error on line 2", n, errs)
+	}
+
+	// Breaking out of the loop leaves the decoder usable.
+	qdec := NewQuadDecoder(strings.NewReader("<http://ex/a> <http://ex/p> <http://ex/b> <http://ex/g> .\n<http://ex/b> <http://ex/p> <http://ex/c> .\n"), NQuads)
+	for q, err := range qdec.All() {
+		if err != nil || q.Ctx != (IRI{str: "http://ex/g"}) {
+			t.Fatalf("All() => %v, %v", q, err)
+		}
+		break
+	}
+	if q, err := qdec.Decode(); err != nil || q.Subj != (IRI{str: "http://ex/b"}) {
+		t.Errorf("Decode() after break => %v, %v; want second quad", q, err)
+	}
+}
@@ -0,0 +1,193 @@
+package rdf
+
+import (
+	"strings"
+	"sync"
+)
+
+// Dictionary maps Terms to compact integer IDs, and back. IDs are assigned
+// consecutively from 1, in the order terms are first seen; the ID 0 is never
+// assigned, and denotes no term. Terms equal according to TermsEqual have
+// the same ID.
+//
+// Storing a term once in a Dictionary, and referring to it by ID, uses much
+// less memory than keeping a copy of the term in every triple. Graph stores
+// its triples as IDs in a Dictionary, and decoders can use a Dictionary to
+// intern IRIs with the Intern ParseOption.
+//
+// A Dictionary is safe for concurrent use by multiple goroutines.
+type Dictionary struct {
+	mu    sync.RWMutex
+	ids   map[termKey]uint64
+	terms []Term // terms[id-1] is the term with the given id
+}
+
+// NewDictionary returns a new, empty Dictionary. The zero value is an empty
+// Dictionary as well.
+func NewDictionary() *Dictionary {
+	return &Dictionary{ids: make(map[termKey]uint64)}
+}
+
+// ID returns the ID of the term, assigning a new ID if the term is not in the
+// dictionary. The ID of a nil Term is 0.
+func (d *Dictionary) ID(t Term) uint64 {
+	id, _ := d.intern(t)
+	return id
+}
+
+// Lookup returns the ID of the term, or false if the term is not in the dictionary.
+func (d *Dictionary) Lookup(t Term) (uint64, bool) {
+	if t == nil {
+		return 0, false
+	}
+	d.mu.RLock()
+	id, ok := d.ids[keyOf(t)]
+	d.mu.RUnlock()
+	return id, ok
+}
+
+// lookupOK is like Lookup, but returns false for a nil Dictionary.
+func (d *Dictionary) lookupOK(t Term) (uint64, bool) {
+	if d == nil {
+		return 0, false
+	}
+	return d.Lookup(t)
+}
+
+// Term returns the term with the given ID, or false if no term has the ID.
+func (d *Dictionary) Term(id uint64) (Term, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if id == 0 || id > uint64(len(d.terms)) {
+		return nil, false
+	}
+	return d.terms[id-1], true
+}
+
+// Len returns the number of terms in the dictionary.
+func (d *Dictionary) Len() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return len(d.terms)
+}
+
+// Intern returns the term stored in the dictionary equal to t, adding t to the
+// dictionary if it is not there. Interning terms as they are decoded lets
+// equal terms share the memory of their strings.
+func (d *Dictionary) Intern(t Term) Term {
+	_, t = d.intern(t)
+	return t
+}
+
+// intern returns the ID of the term and the term as stored in the dictionary,
+// adding the term if it is not there.
+func (d *Dictionary) intern(t Term) (uint64, Term) {
+	if t == nil {
+		return 0, nil
+	}
+	k := keyOf(t)
+	d.mu.RLock()
+	id, ok := d.ids[k]
+	if ok {
+		t = d.terms[id-1]
+	}
+	d.mu.RUnlock()
+	if ok {
+		return id, t
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if id, ok := d.ids[k]; ok {
+		return id, d.terms[id-1]
+	}
+	if d.ids == nil {
+		d.ids = make(map[termKey]uint64)
+	}
+	d.terms = append(d.terms, t)
+	id = uint64(len(d.terms))
+	d.ids[k] = id
+	return id, t
+}
+
+// termKey is a comparable representation of a Term, used to index terms in maps.
+type termKey struct {
+	typ  TermType
+	str  string
+	lang string // lower case
+	dt   string
+}
+
+// keyOf returns the map key of the term t.
+func keyOf(t Term) termKey {
+	switch t := t.(type) {
+	case IRI:
+		return termKey{typ: TermIRI, str: t.str}
+	case Blank:
+		return termKey{typ: TermBlank, str: t.id}
+	case Literal:
+		return termKey{typ: TermLiteral, str: t.str, lang: strings.ToLower(t.lang), dt: t.DataType.str}
+	case QuotedTriple:
+		return termKey{typ: TermQuotedTriple, str: t.Serialize(NTriples)}
+	}
+	return termKey{}
+}
+
+// TripleID is a Triple represented by the dictionary IDs of its terms.
+type TripleID struct {
+	Subj, Pred, Obj uint64
+}
+
+// TripleID returns the triple represented by IDs, assigning IDs to terms not
+// in the dictionary.
+func (d *Dictionary) TripleID(t Triple) TripleID {
+	return TripleID{Subj: d.ID(t.Subj), Pred: d.ID(t.Pred), Obj: d.ID(t.Obj)}
+}
+
+// Triple returns the triple with the terms of the given IDs, or false if any
+// of the IDs are not in the dictionary.
+func (d *Dictionary) Triple(id TripleID) (Triple, bool) {
+	s, ok1 := d.Term(id.Subj)
+	p, ok2 := d.Term(id.Pred)
+	o, ok3 := d.Term(id.Obj)
+	if !ok1 || !ok2 || !ok3 {
+		return Triple{}, false
+	}
+	return Triple{Subj: s.(Subject), Pred: p.(Predicate), Obj: o.(Object)}, true
+}
+
+// internIRIs returns the triple with its IRIs, and the datatypes of its
+// literals, interned in the dictionary. A nil Dictionary returns the triple
+// as it is.
+func (d *Dictionary) internIRIs(t Triple) Triple {
+	if d == nil {
+		return t
+	}
+	t.Subj = d.internSubject(t.Subj)
+	t.Pred = d.Intern(t.Pred).(IRI)
+	t.Obj = d.internObject(t.Obj)
+	return t
+}
+
+func (d *Dictionary) internSubject(s Subject) Subject {
+	switch s := s.(type) {
+	case IRI:
+		return d.Intern(s).(IRI)
+	case QuotedTriple:
+		return QuotedTriple{d.internIRIs(s.Triple)}
+	}
+	return s
+}
+
+func (d *Dictionary) internObject(o Object) Object {
+	switch o := o.(type) {
+	case IRI:
+		return d.Intern(o).(IRI)
+	case Literal:
+		o.DataType = d.Intern(o.DataType).(IRI)
+		return o
+	case QuotedTriple:
+		return QuotedTriple{d.internIRIs(o.Triple)}
+	}
+	return o
+}
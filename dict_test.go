@@ -0,0 +1,147 @@
+package rdf
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+func TestDictionary(t *testing.T) {
+	var d Dictionary
+	terms := []Term{
+		IRI{str: "http://ex/a"},
+		Blank{id: "_:a"},
+		NewTypedLiteral("http://ex/a", xsdString),
+		Literal{str: "a", lang: "en-GB", DataType: rdfLangString},
+		QuotedTriple{Triple{Subj: IRI{str: "http://ex/a"}, Pred: IRI{str: "http://ex/p"}, Obj: Blank{id: "_:a"}}},
+	}
+	for i, term := range terms {
+		if id := d.ID(term); id != uint64(i+1) {
+			t.Errorf("ID(%v) => %d; want %d", term, id, i+1)
+		}
+	}
+	for i, term := range terms {
+		if id := d.ID(term); id != uint64(i+1) {
+			t.Errorf("ID(%v) again => %d; want %d", term, id, i+1)
+		}
+		if got, ok := d.Term(uint64(i + 1)); !ok || !TermsEqual(got, term) {
+			t.Errorf("Term(%d) => %v, %v; want %v", i+1, got, ok, term)
+		}
+	}
+	if d.Len() != len(terms) {
+		t.Errorf("Len() => %d; want %d", d.Len(), len(terms))
+	}
+	if id, ok := d.Lookup(Literal{str: "a", lang: "en-gb", DataType: rdfLangString}); !ok || id != 4 {
+		t.Errorf("Lookup of language tag in other case => %d, %v; want 4, true", id, ok)
+	}
+	if _, ok := d.Lookup(IRI{str: "http://ex/b"}); ok {
+		t.Error("Lookup of unknown term => true; want false")
+	}
+	for _, id := range []uint64{0, uint64(len(terms) + 1)} {
+		if _, ok := d.Term(id); ok {
+			t.Errorf("Term(%d) => true; want false", id)
+		}
+	}
+	if id := d.ID(nil); id != 0 {
+		t.Errorf("ID(nil) => %d; want 0", id)
+	}
+
+	tr := Triple{Subj: IRI{str: "http://ex/a"}, Pred: IRI{str: "http://ex/p"}, Obj: NewTypedLiteral("x", xsdString)}
+	id := d.TripleID(tr)
+	if id.Subj != 1 || id.Pred != 6 || id.Obj != 7 {
+		t.Errorf("TripleID(%v) => %v; want {1 6 7}", tr, id)
+	}
+	if got, ok := d.Triple(id); !ok || !TriplesEqual(got, tr) {
+		t.Errorf("Triple(%v) => %v, %v; want %v", id, got, ok, tr)
+	}
+	if _, ok := d.Triple(TripleID{Subj: 1, Pred: 6, Obj: 99}); ok {
+		t.Error("Triple with unknown ID => true; want false")
+	}
+}
+
+func TestDictionaryConcurrent(t *testing.T) {
+	d := NewDictionary()
+	var wg sync.WaitGroup
+	ids := make([][]uint64, 8)
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				ids[i] = append(ids[i], d.ID(IRI{str: "http://ex/" + strings.Repeat("a", j)}))
+			}
+		}(i)
+	}
+	wg.Wait()
+	if d.Len() != 100 {
+		t.Errorf("Len() => %d; want 100", d.Len())
+	}
+	for i := range ids {
+		for j := range ids[i] {
+			if ids[i][j] != ids[0][j] {
+				t.Fatalf("goroutines got different IDs for the same term: %d != %d", ids[i][j], ids[0][j])
+			}
+		}
+	}
+}
+
+func TestDecodeIntern(t *testing.T) {
+	tests := []struct {
+		input  string
+		format Format
+	}{
+		{"<http://ex/s> <http://ex/p> \"1\"^^<http://ex/dt> .\n<http://ex/s> <http://ex/p> \"2\"^^<http://ex/dt> .\n", NTriples},
+		{"@prefix ex: <http://ex/> .\nex:s ex:p \"1\"^^ex:dt, \"2\"^^ex:dt .", Turtle},
+		{`<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns:ex="http://ex/">
+  <rdf:Description rdf:about="http://ex/s">
+    <ex:p rdf:datatype="http://ex/dt">1</ex:p>
+    <ex:p rdf:datatype="http://ex/dt">2</ex:p>
+  </rdf:Description>
+</rdf:RDF>`, RDFXML},
+	}
+	for _, tt := range tests {
+		d := NewDictionary()
+		dec := NewTripleDecoder(strings.NewReader(tt.input), tt.format)
+		if err := dec.SetOption(Intern, d); err != nil {
+			t.Fatal(err)
+		}
+		triples, err := dec.DecodeAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(triples) != 2 {
+			t.Fatalf("decoding %v => %d triples; want 2", tt.format, len(triples))
+		}
+		a, b := triples[0], triples[1]
+		if !sameString(a.Subj.(IRI).str, b.Subj.(IRI).str) ||
+			!sameString(a.Pred.(IRI).str, b.Pred.(IRI).str) ||
+			!sameString(a.Obj.(Literal).DataType.str, b.Obj.(Literal).DataType.str) {
+			t.Errorf("decoding %v with Intern option => IRIs not interned", tt.format)
+		}
+		if d.Len() != 3 {
+			t.Errorf("decoding %v with Intern option => %d terms in dictionary; want 3", tt.format, d.Len())
+		}
+	}
+
+	dec := NewQuadDecoder(strings.NewReader("<http://ex/s> <http://ex/p> <http://ex/o> <http://ex/g> .\n<http://ex/g> <http://ex/p> <http://ex/o> <http://ex/g> .\n"), NQuads)
+	if err := dec.SetOption(Intern, NewDictionary()); err != nil {
+		t.Fatal(err)
+	}
+	quads, err := dec.DecodeAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameString(quads[0].Ctx.(IRI).str, quads[1].Subj.(IRI).str) {
+		t.Error("decoding N-Quads with Intern option => IRIs not interned")
+	}
+
+	if err := NewTripleDecoder(strings.NewReader(""), Turtle).SetOption(Intern, Dictionary{}); err == nil {
+		t.Error("SetOption(Intern, Dictionary{}) => no error; want error")
+	}
+}
+
+// sameString reports whether a and b share the same memory.
+func sameString(a, b string) bool {
+	return a == b && unsafe.StringData(a) == unsafe.StringData(b)
+}
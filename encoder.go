@@ -3,6 +3,7 @@ package rdf
 import (
 	"bufio"
 	"bytes"
+	"compress/gzip"
 	"errors"
 	"fmt"
 	"io"
@@ -19,6 +20,9 @@ var ErrEncoderClosed = errors.New("Encoder is closed and cannot encode anymore")
 // at a time. Or, if you want to encode multiple triples in one batch, use EncodeAll().
 // In either case; when done serializing, Close() must be called, to ensure
 // that all writes are persisted, since the Encoder uses buffered IO.
+//
+// Set Gzip to true before the first call to Encode, to compress the output
+// with gzip.
 type TripleEncoder struct {
 	format             Format            // Serialization format.
 	w                  *errWriter        // Buffered writer. Set to nil when Encoder is closed.
@@ -29,6 +33,7 @@ type TripleEncoder struct {
 	curPred            Predicate         // Keep track of current subject, to enable encoding of object list.
 	OpenStatement      bool              // True when triple statement hasn't been closed (i.e. in a predicate/object list)
 	GenerateNamespaces bool              // True to auto generate namespaces, false if you give it some custom namespaces and do not want generated ones
+	Gzip               bool              // True to compress the output with gzip
 }
 
 // NewTripleEncoder returns a new TripleEncoder capable of serializing into the
@@ -36,7 +41,7 @@ type TripleEncoder struct {
 func NewTripleEncoder(w io.Writer, f Format) *TripleEncoder {
 	return &TripleEncoder{
 		format:             f,
-		w:                  &errWriter{w: bufio.NewWriter(w)},
+		w:                  newErrWriter(w),
 		Namespaces:         make(map[string]string),
 		ns:                 make(map[string]string),
 		GenerateNamespaces: true,
@@ -48,6 +53,7 @@ func (e *TripleEncoder) Encode(t Triple) error {
 	if e.w == nil {
 		return ErrEncoderClosed
 	}
+	e.w.start(e.Gzip)
 	switch e.format {
 	case NTriples:
 		_, err := e.w.w.Write([]byte(t.Serialize(e.format)))
@@ -129,6 +135,7 @@ func (e *TripleEncoder) EncodeAll(ts []Triple) error {
 	if e.w == nil {
 		return ErrEncoderClosed
 	}
+	e.w.start(e.Gzip)
 	switch e.format {
 	case NTriples:
 		for _, t := range ts {
@@ -221,20 +228,21 @@ func (e *TripleEncoder) EncodeAll(ts []Triple) error {
 //
 // The encoder cannot encode anymore when Close() has been called.
 func (e *TripleEncoder) Close() error {
+	e.w.start(e.Gzip)
 	if e.OpenStatement {
 		e.w.write([]byte(" .")) // Close final statement
 		if e.w.err != nil {
 			return e.w.err
 		}
 	}
-	err := e.w.w.Flush()
+	err := e.w.close()
 	e.w = nil
 	return err
 }
 
 func (e *TripleEncoder) prefixify(t Term) string {
 	if t.Type() == TermIRI {
-		if t.(IRI).str == "http://www.w3.org/1999/02/22-rdf-syntax-ns#type" {
+		if t.(IRI) == rdfType {
 			return "a"
 		}
 		first, rest := t.(IRI).Split()
@@ -337,24 +345,46 @@ func (t bySubjectThenPred) Swap(i, j int) {
 }
 
 func (t bySubjectThenPred) Less(i, j int) bool {
-	// todo implement custom comparestring function wich returns -1 0 1 for less, equal, greater
-	// https://groups.google.com/forum/#!topic/golang-nuts/5mMdKvkxWxo
-	// see also bytes.Compare
-	p, q := t[i].Subj.Serialize(NTriples), t[j].Subj.Serialize(NTriples)
-	switch {
-	case p < q:
-		return true
-	case q < p:
-		return false
-	default:
-		// subjects are equal, continue by comparing predicates
-		return t[i].Pred.Serialize(NTriples) < t[j].Pred.Serialize(NTriples)
+	if c := CompareTerms(t[i].Subj, t[j].Subj); c != 0 {
+		return c < 0
 	}
+	return CompareTerms(t[i].Pred, t[j].Pred) < 0
 }
 
 type errWriter struct {
-	w   *bufio.Writer
-	err error
+	w       *bufio.Writer
+	err     error
+	out     io.Writer    // the output
+	gz      *gzip.Writer // compressing writer between w and out, if any
+	started bool
+}
+
+func newErrWriter(w io.Writer) *errWriter {
+	return &errWriter{w: bufio.NewWriter(w), out: w}
+}
+
+// start prepares the writer for the first write, compressing the output
+// with gzip if gz is true.
+func (ew *errWriter) start(gz bool) {
+	if ew.started {
+		return
+	}
+	ew.started = true
+	if gz {
+		ew.gz = gzip.NewWriter(ew.out)
+		ew.w.Reset(ew.gz)
+	}
+}
+
+// close flushes the buffered output, and ends the gzip stream if any.
+func (ew *errWriter) close() error {
+	err := ew.w.Flush()
+	if ew.gz != nil {
+		if gzErr := ew.gz.Close(); err == nil {
+			err = gzErr
+		}
+	}
+	return err
 }
 
 func (ew *errWriter) write(buf []byte) {
@@ -365,8 +395,13 @@ func (ew *errWriter) write(buf []byte) {
 }
 
 // QuadEncoder serializes RDF Quads. Currently only supports N-Quads.
+//
+// Set Gzip to true before the first call to Encode, to compress the output
+// with gzip.
 type QuadEncoder struct {
 	w *errWriter
+
+	Gzip bool // True to compress the output with gzip
 }
 
 // NewQuadEncoder returns a new QuadEncoder on the given writer. The only supported
@@ -376,12 +411,13 @@ func NewQuadEncoder(w io.Writer, f Format) *QuadEncoder {
 		panic("NewQuadEncoder: only N-Quads format supported ATM")
 	}
 	return &QuadEncoder{
-		w: &errWriter{w: bufio.NewWriter(w)},
+		w: newErrWriter(w),
 	}
 }
 
 // Encode encodes a Quad.
 func (e *QuadEncoder) Encode(q Quad) error {
+	e.w.start(e.Gzip)
 	_, err := e.w.w.Write([]byte(q.Serialize(NQuads)))
 	if err != nil {
 		return err
@@ -394,6 +430,7 @@ func (e *QuadEncoder) EncodeAll(qs []Quad) error {
 	if e.w == nil {
 		return ErrEncoderClosed
 	}
+	e.w.start(e.Gzip)
 	for _, q := range qs {
 		_, err := e.w.w.Write([]byte(q.Serialize(NQuads)))
 		if err != nil {
@@ -405,7 +442,8 @@ func (e *QuadEncoder) EncodeAll(qs []Quad) error {
 
 // Close closes the encoder and flushes the underlying buffering writer.
 func (e *QuadEncoder) Close() error {
-	err := e.w.w.Flush()
+	e.w.start(e.Gzip)
+	err := e.w.close()
 	e.w = nil
 	return err
 }
@@ -0,0 +1,599 @@
+package rdf
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The terms, triples and quads implement the following interfaces from the
+// standard library, so that they can be stored without custom wrappers:
+//
+//  encoding.TextMarshaler, encoding.TextUnmarshaler  N-Triples (or N-Quads) syntax
+//  gob.GobEncoder, gob.GobDecoder                    N-Triples (or N-Quads) syntax
+//  json.Marshaler, json.Unmarshaler                  SPARQL 1.1 Query Results JSON terms
+//  driver.Valuer, sql.Scanner                        N-Triples (or N-Quads) syntax
+//
+// The term types are registered with gob, so that they can be sent as Term,
+// Subject, Object (and so on) interface values.
+func init() {
+	gob.Register(IRI{})
+	gob.Register(Blank{})
+	gob.Register(Literal{})
+	gob.Register(QuotedTriple{})
+}
+
+// parseNTTerm parses a single IRI, blank node, literal or quoted triple in
+// N-Triples syntax.
+func parseNTTerm(text []byte) (t Term, err error) {
+	if strings.ContainsAny(string(text), "\r\n") {
+		return nil, errors.New("unexpected line break in term")
+	}
+	d := newNTDecoder(context.Background(), strings.NewReader(string(text)+"\n"))
+	d.l.star = true
+	defer d.Close()
+	defer d.recover(&err)
+	t = d.parseObject()
+	d.expect1As("end of term", tokenEOL)
+	return t, nil
+}
+
+// parseNTStatement parses a single triple or quad in N-Triples or N-Quads
+// syntax. Subjects and objects can be quoted triples, in RDF-star syntax.
+func parseNTStatement(text []byte, f Format) (q Quad, err error) {
+	if strings.ContainsAny(string(text), "\r\n") {
+		return q, errors.New("unexpected line break in statement")
+	}
+	r := strings.NewReader(string(text) + "\n")
+	if f == NTriples {
+		d := newNTDecoder(context.Background(), r)
+		d.l.star = true
+		defer d.Close()
+		q.Triple, err = d.Decode()
+	} else {
+		d := newQuadDecoder(context.Background(), r, NQuads)
+		d.DefaultGraph = nil
+		d.l.star = true
+		defer d.Close()
+		q, err = d.Decode()
+	}
+	if err == io.EOF {
+		err = errors.New("empty statement")
+	}
+	return q, err
+}
+
+// scanText returns the text of a value read from a database.
+func scanText(src interface{}) ([]byte, error) {
+	switch src := src.(type) {
+	case string:
+		return []byte(src), nil
+	case []byte:
+		return src, nil
+	}
+	return nil, fmt.Errorf("cannot scan %T; must be a string or []byte", src)
+}
+
+// MarshalText implements encoding.TextMarshaler, with the IRI in N-Triples syntax.
+func (u IRI) MarshalText() ([]byte, error) {
+	return []byte(u.Serialize(NTriples)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing an IRI in N-Triples syntax.
+func (u *IRI) UnmarshalText(text []byte) error {
+	t, err := parseNTTerm(text)
+	if err != nil {
+		return err
+	}
+	iri, ok := t.(IRI)
+	if !ok {
+		return fmt.Errorf("cannot unmarshal %s into IRI", text)
+	}
+	*u = iri
+	return nil
+}
+
+// Value implements driver.Valuer, storing the IRI in N-Triples syntax.
+func (u IRI) Value() (driver.Value, error) {
+	return u.Serialize(NTriples), nil
+}
+
+// Scan implements sql.Scanner, reading an IRI in N-Triples syntax.
+func (u *IRI) Scan(src interface{}) error {
+	text, err := scanText(src)
+	if err != nil {
+		return err
+	}
+	return u.UnmarshalText(text)
+}
+
+// GobEncode implements gob.GobEncoder, using MarshalText.
+func (u IRI) GobEncode() ([]byte, error) {
+	return u.MarshalText()
+}
+
+// GobDecode implements gob.GobDecoder, using UnmarshalText.
+func (u *IRI) GobDecode(data []byte) error {
+	return u.UnmarshalText(data)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (u IRI) MarshalJSON() ([]byte, error) {
+	return json.Marshal(termToJSON(u))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (u *IRI) UnmarshalJSON(data []byte) error {
+	t, err := unmarshalJSONTerm(data)
+	if err != nil {
+		return err
+	}
+	iri, ok := t.(IRI)
+	if !ok {
+		return fmt.Errorf("cannot unmarshal %s into IRI", data)
+	}
+	*u = iri
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler, with the blank node in N-Triples syntax.
+func (b Blank) MarshalText() ([]byte, error) {
+	return []byte(b.Serialize(NTriples)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing a blank node in N-Triples syntax.
+func (b *Blank) UnmarshalText(text []byte) error {
+	t, err := parseNTTerm(text)
+	if err != nil {
+		return err
+	}
+	bnode, ok := t.(Blank)
+	if !ok {
+		return fmt.Errorf("cannot unmarshal %s into Blank", text)
+	}
+	*b = bnode
+	return nil
+}
+
+// Value implements driver.Valuer, storing the blank node in N-Triples syntax.
+func (b Blank) Value() (driver.Value, error) {
+	return b.Serialize(NTriples), nil
+}
+
+// Scan implements sql.Scanner, reading a blank node in N-Triples syntax.
+func (b *Blank) Scan(src interface{}) error {
+	text, err := scanText(src)
+	if err != nil {
+		return err
+	}
+	return b.UnmarshalText(text)
+}
+
+// GobEncode implements gob.GobEncoder, using MarshalText.
+func (b Blank) GobEncode() ([]byte, error) {
+	return b.MarshalText()
+}
+
+// GobDecode implements gob.GobDecoder, using UnmarshalText.
+func (b *Blank) GobDecode(data []byte) error {
+	return b.UnmarshalText(data)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b Blank) MarshalJSON() ([]byte, error) {
+	return json.Marshal(termToJSON(b))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *Blank) UnmarshalJSON(data []byte) error {
+	t, err := unmarshalJSONTerm(data)
+	if err != nil {
+		return err
+	}
+	bnode, ok := t.(Blank)
+	if !ok {
+		return fmt.Errorf("cannot unmarshal %s into Blank", data)
+	}
+	*b = bnode
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler, with the literal in N-Triples syntax.
+func (l Literal) MarshalText() ([]byte, error) {
+	return []byte(l.Serialize(NTriples)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing a literal in N-Triples syntax.
+func (l *Literal) UnmarshalText(text []byte) error {
+	t, err := parseNTTerm(text)
+	if err != nil {
+		return err
+	}
+	lit, ok := t.(Literal)
+	if !ok {
+		return fmt.Errorf("cannot unmarshal %s into Literal", text)
+	}
+	*l = lit
+	return nil
+}
+
+// Value implements driver.Valuer, storing the literal in N-Triples syntax.
+func (l Literal) Value() (driver.Value, error) {
+	return l.Serialize(NTriples), nil
+}
+
+// Scan implements sql.Scanner, reading a literal in N-Triples syntax.
+func (l *Literal) Scan(src interface{}) error {
+	text, err := scanText(src)
+	if err != nil {
+		return err
+	}
+	return l.UnmarshalText(text)
+}
+
+// GobEncode implements gob.GobEncoder, using MarshalText.
+func (l Literal) GobEncode() ([]byte, error) {
+	return l.MarshalText()
+}
+
+// GobDecode implements gob.GobDecoder, using UnmarshalText.
+func (l *Literal) GobDecode(data []byte) error {
+	return l.UnmarshalText(data)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (l Literal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(termToJSON(l))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (l *Literal) UnmarshalJSON(data []byte) error {
+	t, err := unmarshalJSONTerm(data)
+	if err != nil {
+		return err
+	}
+	lit, ok := t.(Literal)
+	if !ok {
+		return fmt.Errorf("cannot unmarshal %s into Literal", data)
+	}
+	*l = lit
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler, with the quoted triple in
+// the RDF-star syntax of N-Triples.
+func (q QuotedTriple) MarshalText() ([]byte, error) {
+	return []byte(q.Serialize(NTriples)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing a quoted triple
+// in the RDF-star syntax of N-Triples.
+func (q *QuotedTriple) UnmarshalText(text []byte) error {
+	t, err := parseNTTerm(text)
+	if err != nil {
+		return err
+	}
+	qt, ok := t.(QuotedTriple)
+	if !ok {
+		return fmt.Errorf("cannot unmarshal %s into QuotedTriple", text)
+	}
+	*q = qt
+	return nil
+}
+
+// Value implements driver.Valuer, storing the quoted triple in the RDF-star
+// syntax of N-Triples.
+func (q QuotedTriple) Value() (driver.Value, error) {
+	return q.Serialize(NTriples), nil
+}
+
+// Scan implements sql.Scanner, reading a quoted triple in the RDF-star
+// syntax of N-Triples.
+func (q *QuotedTriple) Scan(src interface{}) error {
+	text, err := scanText(src)
+	if err != nil {
+		return err
+	}
+	return q.UnmarshalText(text)
+}
+
+// GobEncode implements gob.GobEncoder, using MarshalText.
+func (q QuotedTriple) GobEncode() ([]byte, error) {
+	return q.MarshalText()
+}
+
+// GobDecode implements gob.GobDecoder, using UnmarshalText.
+func (q *QuotedTriple) GobDecode(data []byte) error {
+	return q.UnmarshalText(data)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (q QuotedTriple) MarshalJSON() ([]byte, error) {
+	return json.Marshal(termToJSON(q))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (q *QuotedTriple) UnmarshalJSON(data []byte) error {
+	t, err := unmarshalJSONTerm(data)
+	if err != nil {
+		return err
+	}
+	qt, ok := t.(QuotedTriple)
+	if !ok {
+		return fmt.Errorf("cannot unmarshal %s into QuotedTriple", data)
+	}
+	*q = qt
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler, with the triple as a
+// N-Triples statement, without the final line break.
+func (t Triple) MarshalText() ([]byte, error) {
+	if t.Subj == nil || t.Pred == nil || t.Obj == nil {
+		return nil, errors.New("cannot marshal incomplete triple")
+	}
+	return []byte(strings.TrimSuffix(t.Serialize(NTriples), "\n")), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing a N-Triples statement.
+func (t *Triple) UnmarshalText(text []byte) error {
+	q, err := parseNTStatement(text, NTriples)
+	if err != nil {
+		return err
+	}
+	*t = q.Triple
+	return nil
+}
+
+// Value implements driver.Valuer, storing the triple as a N-Triples statement.
+func (t Triple) Value() (driver.Value, error) {
+	text, err := t.MarshalText()
+	return string(text), err
+}
+
+// Scan implements sql.Scanner, reading a N-Triples statement.
+func (t *Triple) Scan(src interface{}) error {
+	text, err := scanText(src)
+	if err != nil {
+		return err
+	}
+	return t.UnmarshalText(text)
+}
+
+// jsonTriple is the JSON representation of a Triple or Quad.
+type jsonTriple struct {
+	Subject   *jsonTerm `json:"subject"`
+	Predicate *jsonTerm `json:"predicate"`
+	Object    *jsonTerm `json:"object"`
+	Graph     *jsonTerm `json:"graph,omitempty"`
+}
+
+// GobEncode implements gob.GobEncoder, using MarshalText.
+func (t Triple) GobEncode() ([]byte, error) {
+	return t.MarshalText()
+}
+
+// GobDecode implements gob.GobDecoder, using UnmarshalText.
+func (t *Triple) GobDecode(data []byte) error {
+	return t.UnmarshalText(data)
+}
+
+// MarshalJSON implements json.Marshaler, with the triple as an object with
+// the members "subject", "predicate" and "object".
+func (t Triple) MarshalJSON() ([]byte, error) {
+	return json.Marshal(tripleToJSON(t))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *Triple) UnmarshalJSON(data []byte) error {
+	var jt jsonTriple
+	if err := json.Unmarshal(data, &jt); err != nil {
+		return err
+	}
+	tr, err := jt.triple()
+	if err != nil {
+		return err
+	}
+	*t = tr
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler, with the quad as a N-Quads
+// statement, without the final line break. A quad with a nil Ctx is
+// written without graph label.
+func (q Quad) MarshalText() ([]byte, error) {
+	text, err := q.Triple.MarshalText()
+	if err != nil || q.Ctx == nil {
+		return text, err
+	}
+	text = append(text[:len(text)-1], q.Ctx.Serialize(NQuads)...)
+	return append(text, " ."...), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing a N-Quads
+// statement. The Ctx of a quad without graph label is nil.
+func (q *Quad) UnmarshalText(text []byte) error {
+	stmt, err := parseNTStatement(text, NQuads)
+	if err != nil {
+		return err
+	}
+	*q = stmt
+	return nil
+}
+
+// Value implements driver.Valuer, storing the quad as a N-Quads statement.
+func (q Quad) Value() (driver.Value, error) {
+	text, err := q.MarshalText()
+	return string(text), err
+}
+
+// Scan implements sql.Scanner, reading a N-Quads statement.
+func (q *Quad) Scan(src interface{}) error {
+	text, err := scanText(src)
+	if err != nil {
+		return err
+	}
+	return q.UnmarshalText(text)
+}
+
+// GobEncode implements gob.GobEncoder, using MarshalText.
+func (q Quad) GobEncode() ([]byte, error) {
+	return q.MarshalText()
+}
+
+// GobDecode implements gob.GobDecoder, using UnmarshalText.
+func (q *Quad) GobDecode(data []byte) error {
+	return q.UnmarshalText(data)
+}
+
+// MarshalJSON implements json.Marshaler, with the quad as an object with the
+// members "subject", "predicate", "object" and "graph"; the graph is omitted
+// if Ctx is nil.
+func (q Quad) MarshalJSON() ([]byte, error) {
+	jt := tripleToJSON(q.Triple)
+	if q.Ctx != nil {
+		jt.Graph = termToJSON(q.Ctx)
+	}
+	return json.Marshal(jt)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (q *Quad) UnmarshalJSON(data []byte) error {
+	var jt jsonTriple
+	if err := json.Unmarshal(data, &jt); err != nil {
+		return err
+	}
+	t, err := jt.triple()
+	if err != nil {
+		return err
+	}
+	var ctx Context
+	if jt.Graph != nil {
+		g, err := jt.Graph.term()
+		if err != nil {
+			return err
+		}
+		if ctx, _ = g.(Context); ctx == nil {
+			return fmt.Errorf("cannot use %v as graph", g)
+		}
+	}
+	*q = Quad{Triple: t, Ctx: ctx}
+	return nil
+}
+
+// jsonTerm is the JSON representation of a Term, as in the SPARQL 1.1 Query
+// Results JSON Format (http://www.w3.org/TR/sparql11-results-json/#select-encode-terms),
+// with quoted triples as in SPARQL-star.
+type jsonTerm struct {
+	Type     string          `json:"type"`
+	Value    json.RawMessage `json:"value"`
+	Lang     string          `json:"xml:lang,omitempty"`
+	DataType string          `json:"datatype,omitempty"`
+}
+
+func termToJSON(t Term) *jsonTerm {
+	str := func(s string) json.RawMessage {
+		b, _ := json.Marshal(s)
+		return b
+	}
+	switch t := t.(type) {
+	case IRI:
+		return &jsonTerm{Type: "uri", Value: str(t.str)}
+	case Blank:
+		return &jsonTerm{Type: "bnode", Value: str(t.String())}
+	case Literal:
+		jt := &jsonTerm{Type: "literal", Value: str(t.str), Lang: t.lang}
+		if t.lang == "" && t.DataType != xsdString {
+			jt.DataType = t.DataType.str
+		}
+		return jt
+	case QuotedTriple:
+		b, _ := json.Marshal(tripleToJSON(t.Triple))
+		return &jsonTerm{Type: "triple", Value: b}
+	}
+	return nil
+}
+
+func tripleToJSON(t Triple) *jsonTriple {
+	return &jsonTriple{
+		Subject:   termToJSON(t.Subj),
+		Predicate: termToJSON(t.Pred),
+		Object:    termToJSON(t.Obj),
+	}
+}
+
+func unmarshalJSONTerm(data []byte) (Term, error) {
+	var jt jsonTerm
+	if err := json.Unmarshal(data, &jt); err != nil {
+		return nil, err
+	}
+	return jt.term()
+}
+
+// term returns the Term represented by jt.
+func (jt *jsonTerm) term() (Term, error) {
+	if jt.Type == "triple" {
+		var t jsonTriple
+		if err := json.Unmarshal(jt.Value, &t); err != nil {
+			return nil, err
+		}
+		tr, err := t.triple()
+		return QuotedTriple{tr}, err
+	}
+	var value string
+	if err := json.Unmarshal(jt.Value, &value); err != nil {
+		return nil, fmt.Errorf("invalid %s term value: %v", jt.Type, err)
+	}
+	switch jt.Type {
+	case "uri":
+		return NewIRI(value)
+	case "bnode":
+		return NewBlank(value)
+	case "literal", "typed-literal":
+		switch {
+		case jt.Lang != "":
+			return NewLangLiteral(value, jt.Lang)
+		case jt.DataType != "":
+			dt, err := NewIRI(jt.DataType)
+			if err != nil {
+				return nil, err
+			}
+			return NewTypedLiteral(value, dt), nil
+		}
+		return NewTypedLiteral(value, xsdString), nil
+	}
+	return nil, fmt.Errorf("unknown term type: %q", jt.Type)
+}
+
+// triple returns the Triple represented by jt.
+func (jt *jsonTriple) triple() (Triple, error) {
+	if jt.Subject == nil || jt.Predicate == nil || jt.Object == nil {
+		return Triple{}, errors.New("incomplete triple")
+	}
+	s, err := jt.Subject.term()
+	if err != nil {
+		return Triple{}, err
+	}
+	p, err := jt.Predicate.term()
+	if err != nil {
+		return Triple{}, err
+	}
+	o, err := jt.Object.term()
+	if err != nil {
+		return Triple{}, err
+	}
+	subj, ok := s.(Subject)
+	if !ok {
+		return Triple{}, fmt.Errorf("cannot use %v as subject", s)
+	}
+	pred, ok := p.(Predicate)
+	if !ok {
+		return Triple{}, fmt.Errorf("cannot use %v as predicate", p)
+	}
+	return Triple{Subj: subj, Pred: pred, Obj: o.(Object)}, nil
+}
@@ -0,0 +1,351 @@
+package rdf
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+var (
+	_ encoding.TextMarshaler   = IRI{}
+	_ encoding.TextUnmarshaler = (*IRI)(nil)
+	_ json.Marshaler           = Literal{}
+	_ json.Unmarshaler         = (*Literal)(nil)
+	_ gob.GobEncoder           = Quad{}
+	_ gob.GobDecoder           = (*QuotedTriple)(nil)
+	_ driver.Valuer            = Blank{}
+	_ sql.Scanner              = (*Blank)(nil)
+	_ driver.Valuer            = Triple{}
+	_ sql.Scanner              = (*Quad)(nil)
+)
+
+var (
+	testEncA    = IRI{str: "http://ex/a"}
+	testEncP    = IRI{str: "http://ex/p"}
+	testEncTerm = []Term{
+		testEncA,
+		Blank{id: "_:b1"},
+		NewTypedLiteral("line\nbreak \"quoted\"", xsdString),
+		NewTypedLiteral("1", xsdInteger),
+		Literal{str: "hei", lang: "nb-NO", DataType: rdfLangString},
+		QuotedTriple{Triple{Subj: testEncA, Pred: testEncP, Obj: Blank{id: "_:b1"}}},
+	}
+)
+
+func TestTermsText(t *testing.T) {
+	want := []string{
+		`<http://ex/a>`,
+		`_:b1`,
+		`"line\nbreak \"quoted\""`,
+		`"1"^^<http://www.w3.org/2001/XMLSchema#integer>`,
+		`"hei"@nb-NO`,
+		`<< <http://ex/a> <http://ex/p> _:b1 >>`,
+	}
+	for i, term := range testEncTerm {
+		text, err := term.(encoding.TextMarshaler).MarshalText()
+		if err != nil || string(text) != want[i] {
+			t.Errorf("%v.MarshalText() => %s, %v; want %s", term, text, err, want[i])
+		}
+		got := reflect.New(reflect.TypeOf(term))
+		if err := got.Interface().(encoding.TextUnmarshaler).UnmarshalText(text); err != nil {
+			t.Errorf("UnmarshalText(%s) => %v", text, err)
+			continue
+		}
+		if !TermsEqual(got.Elem().Interface().(Term), term) {
+			t.Errorf("UnmarshalText(%s) => %v; want %v", text, got.Elem().Interface(), term)
+		}
+	}
+
+	errors := []struct {
+		text string
+		into encoding.TextUnmarshaler
+	}{
+		{`_:b1`, new(IRI)},
+		{`<http://ex/a>`, new(Literal)},
+		{`"a"`, new(Blank)},
+		{`"a" "b"`, new(Literal)},
+		{"\"a\nb\"", new(Literal)},
+		{`<relative>`, new(IRI)},
+		{`<http://ex/a> <http://ex/p>`, new(Triple)},
+		{``, new(Quad)},
+		{`<http://ex/a> <http://ex/p> <http://ex/a>`, new(QuotedTriple)},
+	}
+	for _, tt := range errors {
+		if err := tt.into.UnmarshalText([]byte(tt.text)); err == nil {
+			t.Errorf("UnmarshalText(%q) into %T => no error", tt.text, tt.into)
+		}
+	}
+}
+
+func TestStatementsText(t *testing.T) {
+	tr := Triple{Subj: testEncA, Pred: testEncP, Obj: Literal{str: "hei", lang: "nb", DataType: rdfLangString}}
+	tests := []struct {
+		v    interface{}
+		want string
+	}{
+		{tr, `<http://ex/a> <http://ex/p> "hei"@nb .`},
+		{Quad{Triple: tr, Ctx: IRI{str: "http://ex/g"}}, `<http://ex/a> <http://ex/p> "hei"@nb <http://ex/g> .`},
+		{Quad{Triple: tr}, `<http://ex/a> <http://ex/p> "hei"@nb .`},
+	}
+	for _, tt := range tests {
+		text, err := tt.v.(encoding.TextMarshaler).MarshalText()
+		if err != nil || string(text) != tt.want {
+			t.Errorf("%v.MarshalText() => %s, %v; want %s", tt.v, text, err, tt.want)
+		}
+		got := reflect.New(reflect.TypeOf(tt.v))
+		if err := got.Interface().(encoding.TextUnmarshaler).UnmarshalText(text); err != nil {
+			t.Errorf("UnmarshalText(%s) => %v", text, err)
+			continue
+		}
+		if !reflect.DeepEqual(got.Elem().Interface(), tt.v) {
+			t.Errorf("UnmarshalText(%s) => %v; want %v", text, got.Elem().Interface(), tt.v)
+		}
+	}
+	if _, err := (Triple{Subj: testEncA}).MarshalText(); err == nil {
+		t.Error("MarshalText of incomplete triple => no error")
+	}
+}
+
+func TestTermsJSON(t *testing.T) {
+	want := []string{
+		`{"type":"uri","value":"http://ex/a"}`,
+		`{"type":"bnode","value":"b1"}`,
+		`{"type":"literal","value":"line\nbreak \"quoted\""}`,
+		`{"type":"literal","value":"1","datatype":"http://www.w3.org/2001/XMLSchema#integer"}`,
+		`{"type":"literal","value":"hei","xml:lang":"nb-NO"}`,
+		`{"type":"triple","value":{"subject":{"type":"uri","value":"http://ex/a"},"predicate":{"type":"uri","value":"http://ex/p"},"object":{"type":"bnode","value":"b1"}}}`,
+	}
+	for i, term := range testEncTerm {
+		data, err := json.Marshal(term)
+		if err != nil || string(data) != want[i] {
+			t.Errorf("json.Marshal(%v) => %s, %v; want %s", term, data, err, want[i])
+		}
+		got := reflect.New(reflect.TypeOf(term))
+		if err := json.Unmarshal(data, got.Interface()); err != nil {
+			t.Errorf("json.Unmarshal(%s) => %v", data, err)
+			continue
+		}
+		if !TermsEqual(got.Elem().Interface().(Term), term) {
+			t.Errorf("json.Unmarshal(%s) => %v; want %v", data, got.Elem().Interface(), term)
+		}
+	}
+
+	q := Quad{Triple: Triple{Subj: testEncA, Pred: testEncP, Obj: NewTypedLiteral("1", xsdInteger)}, Ctx: Blank{id: "_:g"}}
+	data, err := json.Marshal(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Quad
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !QuadsEqual(got, q) {
+		t.Errorf("json.Unmarshal(%s) => %v; want %v", data, got, q)
+	}
+	var tr Triple
+	if err := json.Unmarshal(data, &tr); err != nil || !TriplesEqual(tr, q.Triple) {
+		t.Errorf("json.Unmarshal(%s) into Triple => %v, %v; want %v", data, tr, err, q.Triple)
+	}
+
+	errors := []struct {
+		data string
+		into interface{}
+	}{
+		{`{"type":"bnode","value":"b1"}`, new(IRI)},
+		{`{"type":"url","value":"http://ex/a"}`, new(IRI)},
+		{`{"type":"literal","value":"a","xml:lang":"-"}`, new(Literal)},
+		{`{"subject":{"type":"literal","value":"a"},"predicate":{"type":"uri","value":"http://ex/p"},"object":{"type":"uri","value":"http://ex/a"}}`, new(Triple)},
+		{`{"subject":{"type":"uri","value":"http://ex/a"},"predicate":{"type":"uri","value":"http://ex/p"}}`, new(Triple)},
+	}
+	for _, tt := range errors {
+		if err := json.Unmarshal([]byte(tt.data), tt.into); err == nil {
+			t.Errorf("json.Unmarshal(%s) into %T => no error", tt.data, tt.into)
+		}
+	}
+}
+
+func TestTermsGob(t *testing.T) {
+	type record struct {
+		Term    Term
+		Obj     Object
+		Literal Literal
+		Triple  Triple
+		Quads   []Quad
+	}
+	tr := Triple{Subj: testEncA, Pred: testEncP, Obj: Literal{str: "hei", lang: "nb", DataType: rdfLangString}}
+	in := record{
+		Term:    Blank{id: "_:b1"},
+		Obj:     NewTypedLiteral("1", xsdInteger),
+		Literal: NewTypedLiteral("x", xsdString),
+		Triple:  tr,
+		Quads:   []Quad{{Triple: tr, Ctx: IRI{str: "http://ex/g"}}},
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatal(err)
+	}
+	var out record
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !TermsEqual(out.Term, in.Term) || !TermsEqual(out.Obj, in.Obj) || !TermsEqual(out.Literal, in.Literal) ||
+		!TriplesEqual(out.Triple, in.Triple) || len(out.Quads) != 1 || !QuadsEqual(out.Quads[0], in.Quads[0]) {
+		t.Errorf("gob round trip => %+v; want %+v", out, in)
+	}
+}
+
+func TestTermsSQL(t *testing.T) {
+	for _, term := range testEncTerm {
+		v, err := term.(driver.Valuer).Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, ok := v.(string)
+		if !ok {
+			t.Fatalf("%v.Value() => %T; want string", term, v)
+		}
+		for _, src := range []interface{}{s, []byte(s)} {
+			got := reflect.New(reflect.TypeOf(term))
+			if err := got.Interface().(sql.Scanner).Scan(src); err != nil {
+				t.Errorf("Scan(%q) => %v", src, err)
+				continue
+			}
+			if !TermsEqual(got.Elem().Interface().(Term), term) {
+				t.Errorf("Scan(%q) => %v; want %v", src, got.Elem().Interface(), term)
+			}
+		}
+	}
+
+	q := Quad{Triple: Triple{Subj: testEncA, Pred: testEncP, Obj: testEncA}, Ctx: Blank{id: "_:g"}}
+	v, err := q.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Quad
+	if err := got.Scan(v); err != nil || !QuadsEqual(got, q) {
+		t.Errorf("Scan(%v) => %v, %v; want %v", v, got, err, q)
+	}
+	var u IRI
+	if err := u.Scan(42); err == nil {
+		t.Error("Scan(42) => no error")
+	}
+}
+
+func TestQuotedStatementsRoundTrip(t *testing.T) {
+	quoted := QuotedTriple{Triple{Subj: testEncA, Pred: testEncP, Obj: NewTypedLiteral("x", xsdString)}}
+	nested := QuotedTriple{Triple{Subj: quoted, Pred: testEncP, Obj: quoted}}
+	triples := []Triple{
+		{Subj: quoted, Pred: testEncP, Obj: NewTypedLiteral("x", xsdString)},
+		{Subj: testEncA, Pred: testEncP, Obj: quoted},
+		{Subj: nested, Pred: testEncP, Obj: nested},
+	}
+	decoders := []struct {
+		name   string
+		triple func(Triple) (Triple, error)
+		quad   func(Quad) (Quad, error)
+	}{
+		{
+			"text",
+			func(in Triple) (out Triple, err error) {
+				text, err := in.MarshalText()
+				if err == nil {
+					err = out.UnmarshalText(text)
+				}
+				return out, err
+			},
+			func(in Quad) (out Quad, err error) {
+				text, err := in.MarshalText()
+				if err == nil {
+					err = out.UnmarshalText(text)
+				}
+				return out, err
+			},
+		},
+		{
+			"JSON",
+			func(in Triple) (out Triple, err error) {
+				data, err := json.Marshal(in)
+				if err == nil {
+					err = json.Unmarshal(data, &out)
+				}
+				return out, err
+			},
+			func(in Quad) (out Quad, err error) {
+				data, err := json.Marshal(in)
+				if err == nil {
+					err = json.Unmarshal(data, &out)
+				}
+				return out, err
+			},
+		},
+		{
+			"gob",
+			func(in Triple) (out Triple, err error) {
+				var buf bytes.Buffer
+				if err = gob.NewEncoder(&buf).Encode(in); err == nil {
+					err = gob.NewDecoder(&buf).Decode(&out)
+				}
+				return out, err
+			},
+			func(in Quad) (out Quad, err error) {
+				var buf bytes.Buffer
+				if err = gob.NewEncoder(&buf).Encode(in); err == nil {
+					err = gob.NewDecoder(&buf).Decode(&out)
+				}
+				return out, err
+			},
+		},
+		{
+			"SQL",
+			func(in Triple) (out Triple, err error) {
+				v, err := in.Value()
+				if err == nil {
+					err = out.Scan(v)
+				}
+				return out, err
+			},
+			func(in Quad) (out Quad, err error) {
+				v, err := in.Value()
+				if err == nil {
+					err = out.Scan(v)
+				}
+				return out, err
+			},
+		},
+	}
+	for _, dec := range decoders {
+		for _, tr := range triples {
+			if got, err := dec.triple(tr); err != nil || !TriplesEqual(got, tr) {
+				t.Errorf("%s round trip of %v => %v, %v", dec.name, tr, got, err)
+			}
+			q := Quad{Triple: tr, Ctx: IRI{str: "http://ex/g"}}
+			if got, err := dec.quad(q); err != nil || !QuadsEqual(got, q) {
+				t.Errorf("%s round trip of %v => %v, %v", dec.name, q, got, err)
+			}
+		}
+	}
+
+	var q QuotedTriple
+	if err := q.UnmarshalText([]byte(nested.Serialize(NTriples))); err != nil || !TermsEqual(q, nested) {
+		t.Errorf("UnmarshalText(%s) => %v, %v; want %v", nested.Serialize(NTriples), q, err, nested)
+	}
+	for _, text := range []string{
+		`<< <http://ex/a> <http://ex/p> "x" `,
+		`<< <http://ex/a> <http://ex/p> >>`,
+		`<< <http://ex/a> <http://ex/p> "x" >> >>`,
+		`<http://ex/a> >> <http://ex/p> "x" .`,
+	} {
+		var tr Triple
+		if err := tr.UnmarshalText([]byte(text)); err == nil {
+			t.Errorf("UnmarshalText(%s) into Triple => no error", text)
+		}
+	}
+	if _, err := NewTripleDecoder(bytes.NewBufferString(`<< <http://ex/a> <http://ex/p> "x" >> <http://ex/p> "x" .`+"\n"), NTriples).Decode(); err == nil {
+		t.Error("N-Triples decoder accepted a quoted triple")
+	}
+}
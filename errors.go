@@ -0,0 +1,76 @@
+package rdf
+
+import "fmt"
+
+// ErrorKind classifies the errors found when parsing a document.
+type ErrorKind int
+
+// Kinds of parse errors.
+const (
+	SyntaxError     ErrorKind = iota // malformed token, like a literal without closing quote
+	UnexpectedToken                  // well-formed token where it is not allowed
+	UndefinedPrefix                  // prefixed name with an undeclared prefix
+	InvalidTerm                      // term with an invalid value, like a malformed language tag
+	InvalidXML                       // malformed XML in a RDF/XML document
+	InvalidRDFXML                    // XML not following the RDF/XML grammar
+	LimitExceeded                    // document exceeding a limit set with a Max... option
+)
+
+var errorKindNames = [...]string{
+	SyntaxError:     "syntax error",
+	UnexpectedToken: "unexpected token",
+	UndefinedPrefix: "undefined prefix",
+	InvalidTerm:     "invalid term",
+	InvalidXML:      "invalid XML",
+	InvalidRDFXML:   "invalid RDF/XML",
+	LimitExceeded:   "limit exceeded",
+}
+
+func (k ErrorKind) String() string {
+	if k < 0 || int(k) >= len(errorKindNames) {
+		return fmt.Sprintf("ErrorKind(%d)", int(k))
+	}
+	return errorKindNames[k]
+}
+
+// ParseError is the error returned by decoders for malformed documents. It
+// locates the error in the document, so that it can be reported like:
+//
+//  var perr *rdf.ParseError
+//  if errors.As(err, &perr) {
+//  	fmt.Printf("%s:%d:%d: %s\n", filename, perr.Line, perr.Column, perr.Msg)
+//  	fmt.Printf("\t%s\n", perr.Token)
+//  }
+type ParseError struct {
+	Format Format    // serialization format of the document
+	Kind   ErrorKind // kind of error
+	Line   int       // line number, starting at 1
+	Column int       // column number, in runes, starting at 1
+	Offset int64     // byte offset from the start of the document
+	Token  string    // the offending token, or a snippet of the input
+	Msg    string    // description of the error
+	Err    error     // the underlying error, if any
+}
+
+// Error returns the error message, prefixed by the line and column.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Msg)
+}
+
+// Unwrap returns the underlying error, if any.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// parseErrorAt returns a ParseError located at the token.
+func parseErrorAt(f Format, tok token, kind ErrorKind, format string, args ...interface{}) *ParseError {
+	return &ParseError{
+		Format: f,
+		Kind:   kind,
+		Line:   tok.line,
+		Column: tok.col,
+		Offset: tok.off,
+		Token:  tok.lexeme(),
+		Msg:    fmt.Sprintf(format, args...),
+	}
+}
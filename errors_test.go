@@ -0,0 +1,86 @@
+package rdf
+
+import (
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		format Format
+		input  string
+		want   ParseError
+	}{
+		{
+			NTriples,
+			"<http://a> <http://b> <http://c> .\n<http://æ> <http://ø> \"å\"@nb-no2 .\n",
+			ParseError{Kind: SyntaxError, Line: 2, Column: 27, Offset: 64, Token: "nb-no2", Msg: "syntax error: bad literal: invalid language tag"},
+		},
+		{
+			NTriples,
+			"<http://æ> <http://b> \"a\\zb\" .\n",
+			ParseError{Kind: SyntaxError, Line: 1, Column: 24, Offset: 24, Token: `a\z`, Msg: "syntax error: bad literal: disallowed escape character 'z'"},
+		},
+		{
+			NQuads,
+			"<http://a> <http://b> <http://c> ; .\n",
+			ParseError{Kind: UnexpectedToken, Line: 1, Column: 34, Offset: 33, Token: ";", Msg: "unexpected Semicolon as graph"},
+		},
+		{
+			Turtle,
+			"@prefix ex: <http://ex/> .\n\n# «comment»\nex:a ex:b nope:c .\n",
+			ParseError{Kind: UndefinedPrefix, Line: 4, Column: 11, Offset: 52, Token: "nope:", Msg: "missing namespace for prefix: 'nope'"},
+		},
+		{
+			Turtle,
+			"<http://a> <http://b> \"\"\"ø\nø\"\"\" <http://c> .\n",
+			ParseError{Kind: UnexpectedToken, Line: 2, Column: 6, Offset: 34, Token: "<http://c>", Msg: "expected triple termination, got IRI (absolute)"},
+		},
+		{
+			RDFXML,
+			"<rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\">\n  <!-- ø -->  <rdf:li/>\n</rdf:RDF>",
+			ParseError{Kind: InvalidRDFXML, Line: 2, Column: 15, Offset: 81, Token: "<li>", Msg: "disallowed as node element name: rdf:li"},
+		},
+		{
+			RDFXML,
+			"<rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\">\n <rdf:Description></rdf:RDF>",
+			ParseError{Kind: InvalidXML, Line: 2, Column: 29, Offset: 94, Msg: "XML syntax error: element <Description> closed by </RDF>"},
+		},
+	}
+
+	for _, tt := range tests {
+		var err error
+		if tt.format == NQuads {
+			_, err = NewQuadDecoder(strings.NewReader(tt.input), NQuads).DecodeAll()
+		} else {
+			_, err = NewTripleDecoder(strings.NewReader(tt.input), tt.format).DecodeAll()
+		}
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf("decoding %q => %v; want a *ParseError", tt.input, err)
+			continue
+		}
+		tt.want.Format = tt.format
+		perr.Err = nil
+		if *perr != tt.want {
+			t.Errorf("decoding %q =>\n%#v\nwant:\n%#v", tt.input, *perr, tt.want)
+		}
+	}
+}
+
+func TestParseErrorUnwrap(t *testing.T) {
+	_, err := NewTripleDecoder(strings.NewReader("<rdf:RDF>\n</rdf:RDF"), RDFXML).DecodeAll()
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("DecodeAll() => %v; want a *ParseError", err)
+	}
+	var serr *xml.SyntaxError
+	if !errors.As(err, &serr) {
+		t.Errorf("ParseError %v doesn't wrap the *xml.SyntaxError", err)
+	}
+	if want := "2:10: XML syntax error: unexpected EOF"; err.Error() != want {
+		t.Errorf("Error() => %q; want %q", err.Error(), want)
+	}
+}
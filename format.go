@@ -0,0 +1,218 @@
+package rdf
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// formatInfo describes a serialization format.
+type formatInfo struct {
+	name       string
+	mediaType  string   // canonical media type
+	aliases    []string // other media types in use
+	extensions []string // file extensions, the preferred one first
+}
+
+var formats = [...]formatInfo{
+	NTriples: {"N-Triples", "application/n-triples", nil, []string{".nt"}},
+	Turtle:   {"Turtle", "text/turtle", []string{"application/x-turtle"}, []string{".ttl"}},
+	RDFXML:   {"RDF/XML", "application/rdf+xml", nil, []string{".rdf", ".owl"}},
+	NQuads:   {"N-Quads", "application/n-quads", []string{"text/x-nquads"}, []string{".nq"}},
+	JSONLD:   {"JSON-LD", "application/ld+json", nil, []string{".jsonld"}},
+}
+
+// info returns the description of the format, or false for unknown formats.
+func (f Format) info() (formatInfo, bool) {
+	if f < 0 || int(f) >= len(formats) {
+		return formatInfo{}, false
+	}
+	return formats[f], true
+}
+
+// String returns the name of the format, like "N-Triples".
+func (f Format) String() string {
+	if info, ok := f.info(); ok {
+		return info.name
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// MediaType returns the registered media type of the format, like
+// "application/n-triples", or the empty string for unknown formats.
+func (f Format) MediaType() string {
+	info, _ := f.info()
+	return info.mediaType
+}
+
+// Extensions returns the file extensions of the format, including the
+// leading dot, the preferred one first.
+func (f Format) Extensions() []string {
+	info, _ := f.info()
+	return append([]string(nil), info.extensions...)
+}
+
+// FormatFromMediaType returns the format with the given media type, like
+// "text/turtle". Parameters, like "; charset=utf-8", are ignored.
+func FormatFromMediaType(mediaType string) (Format, error) {
+	mt, _, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		return 0, fmt.Errorf("unsupported media type %q: %v", mediaType, err)
+	}
+	for f, info := range formats {
+		if mt == info.mediaType {
+			return Format(f), nil
+		}
+		for _, alias := range info.aliases {
+			if mt == alias {
+				return Format(f), nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unsupported media type %q", mediaType)
+}
+
+// FormatFromExtension returns the format with the given file extension,
+// like ".nt". The leading dot is optional, and case is ignored.
+func FormatFromExtension(ext string) (Format, error) {
+	e := strings.ToLower(ext)
+	if !strings.HasPrefix(e, ".") {
+		e = "." + e
+	}
+	for f, info := range formats {
+		for _, x := range info.extensions {
+			if e == x {
+				return Format(f), nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unsupported file extension %q", ext)
+}
+
+// sniffLen is the number of bytes DetectFormat looks at.
+const sniffLen = 8192
+
+// DetectFormat guesses the serialization format of the document read from
+// r, by looking at its first bytes. It returns the format, and a reader
+// which replays the bytes looked at before the rest of the document; r
+// should not be read from directly anymore. A document compressed with
+// gzip, bzip2 or zlib is decompressed, and the reader returns the
+// decompressed document.
+//
+// XML documents are taken as RDF/XML, and JSON documents as JSON-LD. Other
+// documents are N-Triples or N-Quads if their first lines are valid
+// statements in one of these formats, and Turtle otherwise. Errors reading
+// r are returned when reading from the returned reader.
+func DetectFormat(r io.Reader) (Format, io.Reader) {
+	br := bufio.NewReaderSize(decompress(r), sniffLen)
+	data, err := br.Peek(sniffLen)
+	return sniff(data, err != nil), br
+}
+
+// sniff returns the format of the document starting with data. The document
+// ends after data if eof is true.
+func sniff(data []byte, eof bool) Format {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	text := bytes.TrimLeft(data, " \t\r\n")
+	switch {
+	case len(text) == 0:
+		return Turtle
+	case text[0] == '{':
+		return JSONLD
+	case text[0] == '[':
+		// A JSON array of objects, or a Turtle blank node.
+		rest := bytes.TrimLeft(text[1:], " \t\r\n")
+		if len(rest) > 0 && rest[0] == '{' {
+			return JSONLD
+		}
+	case bytes.HasPrefix(text, []byte("<?xml")), bytes.HasPrefix(text, []byte("<!")):
+		return RDFXML
+	case text[0] == '<':
+		// An XML element, or an IRI. IRIs cannot contain white space, and
+		// are followed by white space in N-Triples and N-Quads.
+		end := bytes.IndexByte(text, '>')
+		if end > 0 && (bytes.ContainsAny(text[:end], " \t\r\n=") ||
+			end+1 < len(text) && text[end+1] != ' ' && text[end+1] != '\t') {
+			return RDFXML
+		}
+	}
+
+	// Check that the complete lines are N-Triples or N-Quads statements.
+	if !eof {
+		i := bytes.LastIndexByte(data, '\n')
+		if i < 0 {
+			return Turtle
+		}
+		data = data[:i+1]
+	}
+	dec := newQuadDecoder(context.Background(), bytes.NewReader(data), NQuads)
+	dec.DefaultGraph = nil
+	f := Turtle
+	for q, err := range dec.All() {
+		switch {
+		case err != nil:
+			return Turtle
+		case q.Ctx != nil:
+			f = NQuads
+		case f == Turtle:
+			f = NTriples
+		}
+	}
+	return f
+}
+
+// File is an RDF document opened with Open.
+type File struct {
+	Format Format // serialization format of the document
+
+	f *os.File
+	r io.Reader
+}
+
+// Open opens the named file for decoding. Its format is given by its file
+// extension, or else detected from its content with DetectFormat. Files
+// compressed with gzip, bzip2 or zlib are decompressed, and the extension
+// of the compression format, like in "dump.nt.gz", is ignored.
+func Open(name string) (*File, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	f := &File{f: file, r: file}
+	if f.Format, err = FormatFromExtension(filepath.Ext(trimCompressionExt(name))); err != nil {
+		f.Format, f.r = DetectFormat(file)
+	}
+	switch f.Format {
+	case NTriples, Turtle, RDFXML, NQuads:
+	default:
+		file.Close()
+		return nil, fmt.Errorf("%s: decoding %v is not supported", name, f.Format)
+	}
+	return f, nil
+}
+
+// TripleDecoder returns a decoder of the triples in the file. It panics if
+// the file is in a quad serialization format.
+func (f *File) TripleDecoder() TripleDecoder {
+	return NewTripleDecoder(f.r, f.Format)
+}
+
+// QuadDecoder returns a decoder of the quads in the file. It panics if the
+// file is not in a quad serialization format.
+func (f *File) QuadDecoder() *QuadDecoder {
+	if f.Format != NQuads {
+		panic(fmt.Errorf("Quad decoder for serialization format %v not implemented", f.Format))
+	}
+	return NewQuadDecoder(f.r, f.Format)
+}
+
+// Close closes the file.
+func (f *File) Close() error {
+	return f.f.Close()
+}
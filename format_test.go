@@ -0,0 +1,128 @@
+package rdf
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormatNames(t *testing.T) {
+	for _, tt := range []struct {
+		f         Format
+		name      string
+		mediaType string
+		ext       string
+	}{
+		{NTriples, "N-Triples", "application/n-triples", ".nt"},
+		{Turtle, "Turtle", "text/turtle", ".ttl"},
+		{RDFXML, "RDF/XML", "application/rdf+xml", ".rdf"},
+		{NQuads, "N-Quads", "application/n-quads", ".nq"},
+		{JSONLD, "JSON-LD", "application/ld+json", ".jsonld"},
+	} {
+		if tt.f.String() != tt.name {
+			t.Errorf("Format(%d).String() => %q; want %q", int(tt.f), tt.f.String(), tt.name)
+		}
+		if tt.f.MediaType() != tt.mediaType {
+			t.Errorf("%v.MediaType() => %q; want %q", tt.f, tt.f.MediaType(), tt.mediaType)
+		}
+		if exts := tt.f.Extensions(); len(exts) == 0 || exts[0] != tt.ext {
+			t.Errorf("%v.Extensions() => %v; want %q first", tt.f, exts, tt.ext)
+		}
+		if f, err := FormatFromMediaType(tt.mediaType); err != nil || f != tt.f {
+			t.Errorf("FormatFromMediaType(%q) => %v, %v; want %v", tt.mediaType, f, err, tt.f)
+		}
+		if f, err := FormatFromExtension(tt.ext); err != nil || f != tt.f {
+			t.Errorf("FormatFromExtension(%q) => %v, %v; want %v", tt.ext, f, err, tt.f)
+		}
+	}
+	if s := formatInternal.String(); s != "Format(5)" {
+		t.Errorf("formatInternal.String() => %q; want Format(5)", s)
+	}
+
+	if f, err := FormatFromMediaType("Text/Turtle; charset=utf-8"); err != nil || f != Turtle {
+		t.Errorf("FormatFromMediaType with parameters => %v, %v; want Turtle", f, err)
+	}
+	if f, err := FormatFromExtension("OWL"); err != nil || f != RDFXML {
+		t.Errorf("FormatFromExtension(\"OWL\") => %v, %v; want RDF/XML", f, err)
+	}
+	if _, err := FormatFromMediaType("text/html"); err == nil {
+		t.Error("FormatFromMediaType(\"text/html\") => no error")
+	}
+	if _, err := FormatFromExtension(".txt"); err == nil {
+		t.Error("FormatFromExtension(\".txt\") => no error")
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	long := strings.Repeat("<http://ex/s> <http://ex/p> \"a long literal\" .\n", 400)
+	for _, tt := range []struct {
+		input string
+		want  Format
+	}{
+		{"", Turtle},
+		{"<http://ex/s> <http://ex/p> <http://ex/o> .\n", NTriples},
+		{"\xef\xbb\xbf# comment\n\n_:b <http://ex/p> \"x\"@en .\r\n", NTriples},
+		{"<http://ex/s> <http://ex/p> <http://ex/o> .\n<http://ex/s> <http://ex/p> <http://ex/o> <http://ex/g> .\n", NQuads},
+		{"<http://ex/s>\t<http://ex/p> \"x\" _:g .", NQuads},
+		{long, NTriples},
+		{long + "<http://ex/s> <http://ex/p> \"unterminated", NTriples},
+		{"@prefix ex: <http://ex/> .\nex:s ex:p ex:o .\n", Turtle},
+		{"PREFIX ex: <http://ex/>\n", Turtle},
+		{"<http://ex/s> <http://ex/p> <http://ex/o>, <http://ex/o2> .\n", Turtle},
+		{"<s> <p> <o> .\n", Turtle},
+		{"[] <http://ex/p> <http://ex/o> .\n", Turtle},
+		{"<?xml version=\"1.0\"?>\n<rdf:RDF/>", RDFXML},
+		{"<!-- comment -->\n<rdf:RDF/>", RDFXML},
+		{"<rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\">\n</rdf:RDF>", RDFXML},
+		{"<rdf:RDF>\n</rdf:RDF>", RDFXML},
+		{"{\"@id\": \"http://ex/s\"}", JSONLD},
+		{" [\n  {\"@id\": \"http://ex/s\"}\n]", JSONLD},
+	} {
+		f, r := DetectFormat(strings.NewReader(tt.input))
+		if f != tt.want {
+			t.Errorf("DetectFormat(%.40q) => %v; want %v", tt.input, f, tt.want)
+		}
+		if b, err := io.ReadAll(r); err != nil || string(b) != tt.input {
+			t.Errorf("DetectFormat(%.40q) reader does not replay the input: %v", tt.input, err)
+		}
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	f, err := Open(write("doc.ttl", "@prefix ex: <http://ex/> .\nex:s ex:p ex:o .\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts, err := f.TripleDecoder().DecodeAll(); err != nil || len(ts) != 1 || f.Format != Turtle {
+		t.Errorf("Open(doc.ttl) => %v %v, %v", f.Format, ts, err)
+	}
+	f.Close()
+
+	// Without a known extension, the format is detected.
+	f, err = Open(write("doc.data", "<http://ex/s> <http://ex/p> <http://ex/o> <http://ex/g> .\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if qs, err := f.QuadDecoder().DecodeAll(); err != nil || len(qs) != 1 || f.Format != NQuads {
+		t.Errorf("Open(doc.data) => %v %v, %v", f.Format, qs, err)
+	}
+	f.Close()
+
+	if _, err := Open(write("doc.jsonld", "{}")); err == nil {
+		t.Error("Open(doc.jsonld) => no error; want unsupported format")
+	}
+	if _, err := Open(filepath.Join(dir, "missing.nt")); !os.IsNotExist(err) {
+		t.Errorf("Open(missing.nt) => %v; want not exist error", err)
+	}
+}
@@ -0,0 +1,224 @@
+package rdf
+
+import (
+	"iter"
+	"sort"
+)
+
+// Graph is an in-memory set of triples, indexed by subject and by object.
+// Triples equal according to TriplesEqual are only stored once.
+//
+// The triples are stored as TripleIDs in a Dictionary, so that each term is
+// only kept in memory once. Several graphs can share a Dictionary, and their
+// TripleIDs are then comparable across the graphs.
+//
+// The zero value is an empty graph ready to use. A Graph is not safe for
+// concurrent use if any of the goroutines modifies it.
+type Graph struct {
+	dict    *Dictionary
+	triples map[TripleID]struct{}
+	bySubj  map[uint64][]TripleID
+	byObj   map[uint64][]TripleID
+}
+
+// NewGraph returns a new Graph with the given triples.
+func NewGraph(triples ...Triple) *Graph {
+	return NewGraphWithDictionary(nil, triples...)
+}
+
+// NewGraphWithDictionary returns a new Graph with the given triples, storing
+// its terms in the Dictionary d. If d is nil, the graph gets a new Dictionary.
+func NewGraphWithDictionary(d *Dictionary, triples ...Triple) *Graph {
+	if d == nil {
+		d = NewDictionary()
+	}
+	g := &Graph{dict: d}
+	g.Add(triples...)
+	return g
+}
+
+// Dictionary returns the Dictionary of the terms in the graph.
+func (g *Graph) Dictionary() *Dictionary {
+	g.init()
+	return g.dict
+}
+
+func (g *Graph) init() {
+	if g.dict == nil {
+		g.dict = NewDictionary()
+	}
+	if g.triples == nil {
+		g.triples = make(map[TripleID]struct{})
+		g.bySubj = make(map[uint64][]TripleID)
+		g.byObj = make(map[uint64][]TripleID)
+	}
+}
+
+// Add adds the triples to the graph. Triples already in the graph are ignored.
+func (g *Graph) Add(triples ...Triple) {
+	g.init()
+	for _, t := range triples {
+		g.addID(g.dict.TripleID(t))
+	}
+}
+
+// AddIDs adds the triples, given by IDs in the graph's Dictionary, to the graph.
+// Triples already in the graph are ignored.
+func (g *Graph) AddIDs(ids ...TripleID) {
+	g.init()
+	for _, id := range ids {
+		g.addID(id)
+	}
+}
+
+func (g *Graph) addID(id TripleID) {
+	if _, ok := g.triples[id]; ok {
+		return
+	}
+	g.triples[id] = struct{}{}
+	g.bySubj[id.Subj] = append(g.bySubj[id.Subj], id)
+	g.byObj[id.Obj] = append(g.byObj[id.Obj], id)
+}
+
+// Remove removes the triples from the graph. Triples not in the graph are ignored.
+func (g *Graph) Remove(triples ...Triple) {
+	for _, t := range triples {
+		id, ok := g.lookup(t)
+		if !ok {
+			continue
+		}
+		if _, ok := g.triples[id]; !ok {
+			continue
+		}
+		delete(g.triples, id)
+		removeIndexed(g.bySubj, id.Subj, id)
+		removeIndexed(g.byObj, id.Obj, id)
+	}
+}
+
+// removeIndexed removes the triple id from the index entry for the term.
+func removeIndexed(index map[uint64][]TripleID, term uint64, id TripleID) {
+	ids := index[term]
+	for i := range ids {
+		if ids[i] == id {
+			ids = append(ids[:i:i], ids[i+1:]...)
+			break
+		}
+	}
+	if len(ids) == 0 {
+		delete(index, term)
+		return
+	}
+	index[term] = ids
+}
+
+// lookup returns the TripleID of the triple, or false if any of its terms
+// are not in the graph's Dictionary.
+func (g *Graph) lookup(t Triple) (TripleID, bool) {
+	s, ok1 := g.dict.lookupOK(t.Subj)
+	p, ok2 := g.dict.lookupOK(t.Pred)
+	o, ok3 := g.dict.lookupOK(t.Obj)
+	return TripleID{Subj: s, Pred: p, Obj: o}, ok1 && ok2 && ok3
+}
+
+// Has returns true if the triple is in the graph.
+func (g *Graph) Has(t Triple) bool {
+	id, ok := g.lookup(t)
+	if !ok {
+		return false
+	}
+	_, ok = g.triples[id]
+	return ok
+}
+
+// Len returns the number of triples in the graph.
+func (g *Graph) Len() int {
+	return len(g.triples)
+}
+
+// Triples returns the triples in the graph, sorted by CompareTriples.
+func (g *Graph) Triples() []Triple {
+	ts := make([]Triple, 0, len(g.triples))
+	for id := range g.triples {
+		ts = append(ts, g.triple(id))
+	}
+	sort.Slice(ts, func(i, j int) bool { return CompareTriples(ts[i], ts[j]) < 0 })
+	return ts
+}
+
+// All returns an iterator over the triples in the graph, in no particular
+// order. Unlike Triples, it neither sorts the triples nor collects them in a
+// slice. The graph may be modified during the iteration; triples removed
+// before they are reached are not yielded, and added triples may or may not
+// be yielded.
+func (g *Graph) All() iter.Seq[Triple] {
+	return func(yield func(Triple) bool) {
+		for id := range g.triples {
+			if !yield(g.triple(id)) {
+				return
+			}
+		}
+	}
+}
+
+// TripleIDs returns the triples in the graph as IDs in the graph's
+// Dictionary, sorted by subject, predicate and object ID.
+func (g *Graph) TripleIDs() []TripleID {
+	ids := make([]TripleID, 0, len(g.triples))
+	for id := range g.triples {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := ids[i], ids[j]
+		if a.Subj != b.Subj {
+			return a.Subj < b.Subj
+		}
+		if a.Pred != b.Pred {
+			return a.Pred < b.Pred
+		}
+		return a.Obj < b.Obj
+	})
+	return ids
+}
+
+// Subjects returns the distinct subjects of the triples in the graph, sorted
+// by CompareTerms.
+func (g *Graph) Subjects() []Subject {
+	subjs := make([]Subject, 0, len(g.bySubj))
+	for id := range g.bySubj {
+		subjs = append(subjs, g.term(id).(Subject))
+	}
+	sort.Slice(subjs, func(i, j int) bool { return CompareTerms(subjs[i], subjs[j]) < 0 })
+	return subjs
+}
+
+// Resource returns the resource identified by the subject in the graph.
+func (g *Graph) Resource(subj Subject) Resource {
+	return Resource{Subject: subj, Graph: g}
+}
+
+// term returns the term with the given ID, which must be in the graph's Dictionary.
+func (g *Graph) term(id uint64) Term {
+	t, _ := g.dict.Term(id)
+	return t
+}
+
+// triple returns the triple with the given ID, which must be in the graph's Dictionary.
+func (g *Graph) triple(id TripleID) Triple {
+	t, _ := g.dict.Triple(id)
+	return t
+}
+
+// withSubject returns the IDs of the triples in the graph with the given
+// subject, in the order they were added.
+func (g *Graph) withSubject(subj Subject) []TripleID {
+	id, _ := g.dict.lookupOK(subj)
+	return g.bySubj[id]
+}
+
+// withObject returns the IDs of the triples in the graph with the given
+// object, in the order they were added.
+func (g *Graph) withObject(obj Object) []TripleID {
+	id, _ := g.dict.lookupOK(obj)
+	return g.byObj[id]
+}
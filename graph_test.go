@@ -0,0 +1,77 @@
+package rdf
+
+import "testing"
+
+func TestGraph(t *testing.T) {
+	a, b, p := IRI{str: "http://ex/a"}, IRI{str: "http://ex/b"}, IRI{str: "http://ex/p"}
+	t1 := Triple{Subj: b, Pred: p, Obj: Literal{str: "x", lang: "en-GB", DataType: rdfLangString}}
+	t2 := Triple{Subj: a, Pred: p, Obj: b}
+	t3 := Triple{Subj: a, Pred: p, Obj: NewTypedLiteral("1", xsdInteger)}
+
+	var g Graph
+	g.Add(t1, t2, t3, t2)
+	if g.Len() != 3 {
+		t.Fatalf("Graph.Len() => %d; want 3", g.Len())
+	}
+	if !g.Has(Triple{Subj: b, Pred: p, Obj: Literal{str: "x", lang: "en-gb", DataType: rdfLangString}}) {
+		t.Error("Graph.Has with language tag in other case => false; want true")
+	}
+	if g.Has(Triple{Subj: a, Pred: p, Obj: NewTypedLiteral("1", xsdInt)}) {
+		t.Error("Graph.Has with other datatype => true; want false")
+	}
+	want := []Triple{t2, t3, t1}
+	got := g.Triples()
+	for i := range want {
+		if !TriplesEqual(got[i], want[i]) {
+			t.Errorf("Graph.Triples()[%d] => %v; want %v", i, got[i], want[i])
+		}
+	}
+	n := 0
+	for tr := range g.All() {
+		if !g.Has(tr) {
+			t.Errorf("Graph.All() yielded %v, which is not in the graph", tr)
+		}
+		n++
+	}
+	if n != 3 {
+		t.Errorf("Graph.All() yielded %d triples; want 3", n)
+	}
+	if subjs := g.Subjects(); len(subjs) != 2 || subjs[0] != a || subjs[1] != b {
+		t.Errorf("Graph.Subjects() => %v; want [%v %v]", subjs, a, b)
+	}
+
+	g.Remove(t2, Triple{Subj: b, Pred: p, Obj: a})
+	if g.Len() != 2 || g.Has(t2) {
+		t.Errorf("Graph.Remove(%v) => %v", t2, g.Triples())
+	}
+	if objs := g.Resource(a).Objects(p); len(objs) != 1 || !TermsEqual(objs[0], t3.Obj) {
+		t.Errorf("Objects after Remove => %v; want [%v]", objs, t3.Obj)
+	}
+	if res := g.Resource(b).Inverse(p); len(res) != 0 {
+		t.Errorf("Inverse after Remove => %v; want none", res)
+	}
+}
+
+func TestGraphSharedDictionary(t *testing.T) {
+	a, b, p := IRI{str: "http://ex/a"}, IRI{str: "http://ex/b"}, IRI{str: "http://ex/p"}
+	d := NewDictionary()
+	g1 := NewGraphWithDictionary(d, Triple{Subj: a, Pred: p, Obj: b})
+	g2 := NewGraphWithDictionary(d, Triple{Subj: b, Pred: p, Obj: a})
+	if g1.Dictionary() != d || g2.Dictionary() != d || d.Len() != 3 {
+		t.Fatalf("graphs do not share dictionary: %d terms", d.Len())
+	}
+
+	g2.AddIDs(g1.TripleIDs()...)
+	if g2.Len() != 2 || !g2.Has(Triple{Subj: a, Pred: p, Obj: b}) {
+		t.Errorf("AddIDs => %v", g2.Triples())
+	}
+	want := []TripleID{{1, 2, 3}, {3, 2, 1}}
+	if ids := g2.TripleIDs(); len(ids) != 2 || ids[0] != want[0] || ids[1] != want[1] {
+		t.Errorf("TripleIDs() => %v; want %v", ids, want)
+	}
+
+	var zero Graph
+	if zero.Has(Triple{Subj: a, Pred: p, Obj: b}) || len(zero.Triples()) != 0 || len(zero.Resource(a).Objects(p)) != 0 {
+		t.Error("zero Graph is not empty")
+	}
+}
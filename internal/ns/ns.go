@@ -0,0 +1,18 @@
+// Package ns holds the namespace IRIs of the vocabularies in the vocab
+// packages, so that they can be shared with the core rdf package.
+package ns
+
+// Namespace IRIs.
+const (
+	RDF     = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	RDFS    = "http://www.w3.org/2000/01/rdf-schema#"
+	XSD     = "http://www.w3.org/2001/XMLSchema#"
+	OWL     = "http://www.w3.org/2002/07/owl#"
+	SKOS    = "http://www.w3.org/2004/02/skos/core#"
+	DCTerms = "http://purl.org/dc/terms/"
+	FOAF    = "http://xmlns.com/foaf/0.1/"
+	Schema  = "http://schema.org/"
+	PROV    = "http://www.w3.org/ns/prov#"
+	SH      = "http://www.w3.org/ns/shacl#"
+	XML     = "http://www.w3.org/XML/1998/namespace"
+)
@@ -0,0 +1,431 @@
+package rdf
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// IRIRef represents a parsed IRI reference, as defined by RFC 3987; either an
+// absolute IRI, or a relative reference which must be resolved against a base
+// IRI to become absolute.
+//
+// An IRI reference has five components:
+//
+//    foo://user@example.com:8042/over/there?name=ferret#nose
+//    \_/   \___________________/\_________/ \_________/ \__/
+//     |              |               |           |       |
+//   scheme       authority          path       query  fragment
+//
+// The path is always present, but may be empty. The other components are
+// optional; use the Has* methods to distinguish an empty component from an
+// absent one.
+type IRIRef struct {
+	scheme    string
+	authority string
+	path      string
+	query     string
+	fragment  string
+
+	hasScheme    bool
+	hasAuthority bool
+	hasQuery     bool
+	hasFragment  bool
+}
+
+// ParseIRIRef parses an IRI reference, or returns an error if it is not
+// syntactically valid.
+func ParseIRIRef(s string) (IRIRef, error) {
+	for _, r := range s {
+		if r >= '\x00' && r <= '\x20' {
+			return IRIRef{}, fmt.Errorf("disallowed character: %q", r)
+		}
+		switch r {
+		case '<', '>', '"', '{', '}', '|', '^', '`', '\\':
+			return IRIRef{}, fmt.Errorf("disallowed character: %q", r)
+		}
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' && (i+2 >= len(s) || !isHex(s[i+1]) || !isHex(s[i+2])) {
+			return IRIRef{}, errors.New("invalid percent-encoding")
+		}
+	}
+	r := splitIRIRef(s)
+	if r.hasScheme && !isScheme(r.scheme) {
+		return IRIRef{}, fmt.Errorf("invalid scheme: %q", r.scheme)
+	}
+	if r.hasAuthority {
+		for _, c := range r.Port() {
+			if !isDigit(c) {
+				return IRIRef{}, fmt.Errorf("invalid port: %q", r.Port())
+			}
+		}
+	} else if strings.HasPrefix(r.path, "//") {
+		return IRIRef{}, errors.New("path cannot start with \"//\" when there is no authority")
+	}
+	return r, nil
+}
+
+// splitIRIRef splits a string into the components of an IRI reference, without
+// checking that it is valid. It follows the regular expression in RFC 3986
+// appendix B, which matches any string:
+//
+//  ^(([^:/?#]+):)?(//([^/?#]*))?([^?#]*)(\?([^#]*))?(#(.*))?
+func splitIRIRef(s string) (r IRIRef) {
+	if i := strings.IndexByte(s, '#'); i >= 0 {
+		r.fragment, r.hasFragment = s[i+1:], true
+		s = s[:i]
+	}
+	if i := strings.IndexByte(s, '?'); i >= 0 {
+		r.query, r.hasQuery = s[i+1:], true
+		s = s[:i]
+	}
+	if i := strings.IndexAny(s, ":/"); i > 0 && s[i] == ':' {
+		r.scheme, r.hasScheme = s[:i], true
+		s = s[i+1:]
+	}
+	if strings.HasPrefix(s, "//") {
+		s = s[2:]
+		i := strings.IndexByte(s, '/')
+		if i < 0 {
+			i = len(s)
+		}
+		r.authority, r.hasAuthority = s[:i], true
+		s = s[i:]
+	}
+	r.path = s
+	return r
+}
+
+// Scheme returns the scheme component, e.g. "http".
+func (r IRIRef) Scheme() string { return r.scheme }
+
+// Authority returns the authority component, e.g. "user@example.com:8042".
+func (r IRIRef) Authority() string { return r.authority }
+
+// UserInfo returns the user information of the authority component, if any.
+func (r IRIRef) UserInfo() string {
+	if i := strings.LastIndexByte(r.authority, '@'); i >= 0 {
+		return r.authority[:i]
+	}
+	return ""
+}
+
+// Host returns the host of the authority component. IPv6 addresses are
+// returned with their enclosing brackets.
+func (r IRIRef) Host() string {
+	host := r.authority[strings.LastIndexByte(r.authority, '@')+1:]
+	if i := strings.LastIndexByte(host, ':'); i >= 0 && !strings.Contains(host[i:], "]") {
+		return host[:i]
+	}
+	return host
+}
+
+// Port returns the port of the authority component, if any.
+func (r IRIRef) Port() string {
+	host := r.authority[strings.LastIndexByte(r.authority, '@')+1:]
+	if i := strings.LastIndexByte(host, ':'); i >= 0 && !strings.Contains(host[i:], "]") {
+		return host[i+1:]
+	}
+	return ""
+}
+
+// Path returns the path component, which may be empty.
+func (r IRIRef) Path() string { return r.path }
+
+// Query returns the query component, without the leading '?'.
+func (r IRIRef) Query() string { return r.query }
+
+// Fragment returns the fragment component, without the leading '#'.
+func (r IRIRef) Fragment() string { return r.fragment }
+
+// HasAuthority reports whether the reference has an authority component.
+func (r IRIRef) HasAuthority() bool { return r.hasAuthority }
+
+// HasQuery reports whether the reference has a query component.
+func (r IRIRef) HasQuery() bool { return r.hasQuery }
+
+// HasFragment reports whether the reference has a fragment component.
+func (r IRIRef) HasFragment() bool { return r.hasFragment }
+
+// IsAbsolute reports whether the reference is an absolute IRI, i.e. if it has a scheme.
+func (r IRIRef) IsAbsolute() bool { return r.hasScheme }
+
+// String recomposes the components into an IRI reference string (RFC 3986 §5.3).
+func (r IRIRef) String() string {
+	var b strings.Builder
+	b.Grow(len(r.scheme) + len(r.authority) + len(r.path) + len(r.query) + len(r.fragment) + 5)
+	if r.hasScheme {
+		b.WriteString(r.scheme)
+		b.WriteByte(':')
+	}
+	if r.hasAuthority {
+		b.WriteString("//")
+		b.WriteString(r.authority)
+	}
+	b.WriteString(r.path)
+	if r.hasQuery {
+		b.WriteByte('?')
+		b.WriteString(r.query)
+	}
+	if r.hasFragment {
+		b.WriteByte('#')
+		b.WriteString(r.fragment)
+	}
+	return b.String()
+}
+
+// IRI returns the reference as an IRI term, or an error if it is not a valid IRI.
+func (r IRIRef) IRI() (IRI, error) {
+	return NewIRI(r.String())
+}
+
+// Resolve resolves the reference ref against r, following the algorithm in
+// RFC 3986 §5.2. The receiver r should be an absolute IRI; if it is not,
+// the result may not be an absolute IRI either.
+func (r IRIRef) Resolve(ref IRIRef) IRIRef {
+	var t IRIRef
+	switch {
+	case ref.hasScheme:
+		t = ref
+		t.path = removeDotSegments(ref.path)
+	case ref.hasAuthority:
+		t = ref
+		t.path = removeDotSegments(ref.path)
+		t.scheme, t.hasScheme = r.scheme, r.hasScheme
+	default:
+		t.authority, t.hasAuthority = r.authority, r.hasAuthority
+		t.scheme, t.hasScheme = r.scheme, r.hasScheme
+		switch {
+		case ref.path == "":
+			t.path = r.path
+			if ref.hasQuery {
+				t.query, t.hasQuery = ref.query, true
+			} else {
+				t.query, t.hasQuery = r.query, r.hasQuery
+			}
+		case ref.path[0] == '/':
+			t.path = removeDotSegments(ref.path)
+			t.query, t.hasQuery = ref.query, ref.hasQuery
+		default:
+			t.path = removeDotSegments(r.merge(ref.path))
+			t.query, t.hasQuery = ref.query, ref.hasQuery
+		}
+	}
+	t.fragment, t.hasFragment = ref.fragment, ref.hasFragment
+	return t
+}
+
+// merge merges a relative-path reference with the path of r (RFC 3986 §5.2.3).
+func (r IRIRef) merge(path string) string {
+	if r.hasAuthority && r.path == "" {
+		return "/" + path
+	}
+	return r.path[:strings.LastIndexByte(r.path, '/')+1] + path
+}
+
+// removeDotSegments removes the special "." and ".." complete path segments
+// from a path (RFC 3986 §5.2.4).
+func removeDotSegments(path string) string {
+	if !strings.Contains(path, ".") {
+		return path
+	}
+	in := path
+	out := make([]string, 0, strings.Count(path, "/")+1)
+	for len(in) > 0 {
+		switch {
+		case strings.HasPrefix(in, "../"):
+			in = in[3:]
+		case strings.HasPrefix(in, "./"):
+			in = in[2:]
+		case strings.HasPrefix(in, "/./"):
+			in = in[2:]
+		case in == "/.":
+			in = "/"
+		case strings.HasPrefix(in, "/../"):
+			in = in[3:]
+			if len(out) > 0 {
+				out = out[:len(out)-1]
+			}
+		case in == "/..":
+			in = "/"
+			if len(out) > 0 {
+				out = out[:len(out)-1]
+			}
+		case in == "." || in == "..":
+			in = ""
+		default:
+			// Move the first path segment, including its initial "/"
+			// (if any), to the output.
+			i := strings.IndexByte(in[1:], '/') + 1
+			if i == 0 {
+				i = len(in)
+			}
+			out = append(out, in[:i])
+			in = in[i:]
+		}
+	}
+	return strings.Join(out, "")
+}
+
+// Normalize returns the reference in syntax-based normal form (RFC 3986 §6.2.2);
+// the scheme and host are lowercased, percent-encodings are uppercased, and
+// percent-encoded unreserved characters are decoded. Dot segments are removed
+// from the path, unless it is a relative-path reference.
+func (r IRIRef) Normalize() IRIRef {
+	r.scheme = strings.ToLower(r.scheme)
+	if r.hasAuthority {
+		host := r.Host()
+		i := strings.LastIndex(r.authority, host)
+		r.authority = normalizePercentEncoding(r.authority[:i]) +
+			strings.ToLower(normalizePercentEncoding(host)) + r.authority[i+len(host):]
+	}
+	r.path = normalizePercentEncoding(r.path)
+	if r.hasScheme || r.hasAuthority || strings.HasPrefix(r.path, "/") {
+		r.path = removeDotSegments(r.path)
+	}
+	r.query = normalizePercentEncoding(r.query)
+	r.fragment = normalizePercentEncoding(r.fragment)
+	return r
+}
+
+// normalizePercentEncoding uppercases the hex digits of percent-encodings,
+// and decodes percent-encoded unreserved characters.
+func normalizePercentEncoding(s string) string {
+	if !strings.Contains(s, "%") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' || i+2 >= len(s) || !isHex(s[i+1]) || !isHex(s[i+2]) {
+			b.WriteByte(s[i])
+			continue
+		}
+		c := unhex(s[i+1])<<4 | unhex(s[i+2])
+		if isUnreserved(c) {
+			b.WriteByte(c)
+		} else {
+			b.WriteByte('%')
+			b.WriteString(strings.ToUpper(s[i+1 : i+3]))
+		}
+		i += 2
+	}
+	return b.String()
+}
+
+// Relativize returns a reference relative to r, which resolves to target. If
+// target cannot be expressed relative to r (e.g. if they have different
+// schemes), target is returned unchanged.
+func (r IRIRef) Relativize(target IRIRef) IRIRef {
+	if !r.hasScheme || r.scheme != target.scheme || r.hasAuthority != target.hasAuthority ||
+		r.authority != target.authority {
+		return target
+	}
+	rel := IRIRef{
+		query: target.query, hasQuery: target.hasQuery,
+		fragment: target.fragment, hasFragment: target.hasFragment,
+	}
+	switch {
+	case r.path == target.path && !target.hasQuery && r.hasQuery:
+		rel.path = lastSegment(target.path)
+	case r.path == target.path:
+		if !target.hasQuery || (r.hasQuery && r.query == target.query) {
+			rel.hasQuery = false
+		}
+	default:
+		dir := r.path[:strings.LastIndexByte(r.path, '/')+1]
+		if strings.HasPrefix(target.path, dir) {
+			rel.path = target.path[len(dir):]
+		} else {
+			// Walk up the directory tree until we find a common ancestor.
+			rel.path = target.path
+			for up := "../"; strings.Count(dir, "/") > 1; up += "../" {
+				dir = dir[:strings.LastIndexByte(dir[:len(dir)-1], '/')+1]
+				if strings.HasPrefix(target.path, dir) {
+					rel.path = up + target.path[len(dir):]
+					break
+				}
+			}
+		}
+		if rel.path == "" {
+			rel.path = "./"
+		}
+	}
+	// A first segment containing ':' would be mistaken for a scheme.
+	if i := strings.IndexAny(rel.path, ":/"); i >= 0 && rel.path[i] == ':' {
+		rel.path = "./" + rel.path
+	}
+	if r.Resolve(rel).String() != target.String() {
+		// Dot segments in target, or other edge cases which
+		// cannot be round-tripped; give up.
+		return target
+	}
+	return rel
+}
+
+// lastSegment returns the last segment of a path, or "./" if it is empty.
+func lastSegment(path string) string {
+	if s := path[strings.LastIndexByte(path, '/')+1:]; s != "" {
+		return s
+	}
+	return "./"
+}
+
+// Resolve resolves a relative IRI reference against the IRI, returning an
+// absolute IRI, or an error if ref is not a valid IRI reference.
+func (u IRI) Resolve(ref string) (IRI, error) {
+	r, err := ParseIRIRef(ref)
+	if err != nil {
+		return IRI{}, err
+	}
+	return splitIRIRef(u.str).Resolve(r).IRI()
+}
+
+// Relativize returns the shortest reference relative to the IRI, which resolves
+// to the target IRI. If no such reference exists, the target IRI string is returned.
+func (u IRI) Relativize(target IRI) string {
+	return splitIRIRef(u.str).Relativize(splitIRIRef(target.str)).String()
+}
+
+// resolveIRI resolves ref against the base IRI, for use in the decoders. If
+// base is not an absolute IRI, ref is returned unchanged.
+func resolveIRI(base, ref string) string {
+	b := splitIRIRef(base)
+	if !b.hasScheme {
+		return ref
+	}
+	return b.Resolve(splitIRIRef(ref)).String()
+}
+
+// isScheme checks if s is a valid scheme: ALPHA *( ALPHA / DIGIT / "+" / "-" / "." )
+func isScheme(s string) bool {
+	if s == "" || !isAlpha(rune(s[0])) {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		if !isAlphaOrDigit(rune(s[i])) && s[i] != '+' && s[i] != '-' && s[i] != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+// isUnreserved checks if c is an unreserved character: ALPHA / DIGIT / "-" / "." / "_" / "~"
+func isUnreserved(c byte) bool {
+	return isAlphaOrDigit(rune(c)) || c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+func isHex(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func unhex(c byte) byte {
+	switch {
+	case c >= 'a':
+		return c - 'a' + 10
+	case c >= 'A':
+		return c - 'A' + 10
+	default:
+		return c - '0'
+	}
+}
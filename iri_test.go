@@ -0,0 +1,215 @@
+package rdf
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestParseIRIRef(t *testing.T) {
+	tests := []struct {
+		input                                       string
+		scheme, userinfo, host, port, path, q, frag string
+		err                                         string
+	}{
+		{"foo://user@example.com:8042/over/there?name=ferret#nose",
+			"foo", "user", "example.com", "8042", "/over/there", "name=ferret", "nose", ""},
+		{"http://[::1]:80/", "http", "", "[::1]", "80", "/", "", "", ""},
+		{"http://[::1]/", "http", "", "[::1]", "", "/", "", "", ""},
+		{"urn:isbn:0451450523", "urn", "", "", "", "isbn:0451450523", "", "", ""},
+		{"../a/b?x#", "", "", "", "", "../a/b", "x", "", ""},
+		{"http://ex/伝言", "http", "", "ex", "", "/伝言", "", "", ""},
+		{"http://ex/a b", "", "", "", "", "", "", "", "disallowed character: ' '"},
+		{"http://ex/%zz", "", "", "", "", "", "", "", "invalid percent-encoding"},
+		{"1http://ex/", "", "", "", "", "", "", "", `invalid scheme: "1http"`},
+		{"http://ex:8o/", "", "", "", "", "", "", "", `invalid port: "8o"`},
+	}
+
+	for _, tt := range tests {
+		r, err := ParseIRIRef(tt.input)
+		if err != nil {
+			if err.Error() != tt.err {
+				t.Errorf("ParseIRIRef(%q) => %v; want %v", tt.input, err, tt.err)
+			}
+			continue
+		}
+		if tt.err != "" {
+			t.Errorf("ParseIRIRef(%q) => <no error>; want %v", tt.input, tt.err)
+			continue
+		}
+		got := []string{r.Scheme(), r.UserInfo(), r.Host(), r.Port(), r.Path(), r.Query(), r.Fragment()}
+		want := []string{tt.scheme, tt.userinfo, tt.host, tt.port, tt.path, tt.q, tt.frag}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ParseIRIRef(%q) => %q; want %q", tt.input, got, want)
+		}
+		if r.String() != tt.input {
+			t.Errorf("ParseIRIRef(%q).String() => %q", tt.input, r.String())
+		}
+	}
+}
+
+func TestIRIRefResolve(t *testing.T) {
+	// Examples from RFC 3986 §5.4
+	base, _ := ParseIRIRef("http://a/b/c/d;p?q")
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		// Normal examples
+		{"g:h", "g:h"},
+		{"g", "http://a/b/c/g"},
+		{"./g", "http://a/b/c/g"},
+		{"g/", "http://a/b/c/g/"},
+		{"/g", "http://a/g"},
+		{"//g", "http://g"},
+		{"?y", "http://a/b/c/d;p?y"},
+		{"g?y", "http://a/b/c/g?y"},
+		{"#s", "http://a/b/c/d;p?q#s"},
+		{"g#s", "http://a/b/c/g#s"},
+		{"g?y#s", "http://a/b/c/g?y#s"},
+		{";x", "http://a/b/c/;x"},
+		{"g;x", "http://a/b/c/g;x"},
+		{"g;x?y#s", "http://a/b/c/g;x?y#s"},
+		{"", "http://a/b/c/d;p?q"},
+		{".", "http://a/b/c/"},
+		{"./", "http://a/b/c/"},
+		{"..", "http://a/b/"},
+		{"../", "http://a/b/"},
+		{"../g", "http://a/b/g"},
+		{"../..", "http://a/"},
+		{"../../", "http://a/"},
+		{"../../g", "http://a/g"},
+
+		// Abnormal examples
+		{"../../../g", "http://a/g"},
+		{"../../../../g", "http://a/g"},
+		{"/./g", "http://a/g"},
+		{"/../g", "http://a/g"},
+		{"g.", "http://a/b/c/g."},
+		{".g", "http://a/b/c/.g"},
+		{"g..", "http://a/b/c/g.."},
+		{"..g", "http://a/b/c/..g"},
+		{"./../g", "http://a/b/g"},
+		{"./g/.", "http://a/b/c/g/"},
+		{"g/./h", "http://a/b/c/g/h"},
+		{"g/../h", "http://a/b/c/h"},
+		{"g;x=1/./y", "http://a/b/c/g;x=1/y"},
+		{"g;x=1/../y", "http://a/b/c/y"},
+		{"g?y/./x", "http://a/b/c/g?y/./x"},
+		{"g?y/../x", "http://a/b/c/g?y/../x"},
+		{"g#s/./x", "http://a/b/c/g#s/./x"},
+		{"g#s/../x", "http://a/b/c/g#s/../x"},
+		{"http:g", "http:g"},
+	}
+
+	for _, tt := range tests {
+		ref, err := ParseIRIRef(tt.ref)
+		if err != nil {
+			t.Fatalf("ParseIRIRef(%q) => %v", tt.ref, err)
+		}
+		if got := base.Resolve(ref).String(); got != tt.want {
+			t.Errorf("resolving %q => %q; want %q", tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestIRIRefNormalize(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"HTTP://User@Example.COM/a/./b/../c", "http://User@example.com/a/c"},
+		{"http://ex/%7euser/%3a%41", "http://ex/~user/%3AA"},
+		{"http://ex:80/?%61#%62", "http://ex:80/?a#b"},
+		{"../a/./b", "../a/./b"},
+		{"/a/../b", "/b"},
+	}
+
+	for _, tt := range tests {
+		r, err := ParseIRIRef(tt.input)
+		if err != nil {
+			t.Fatalf("ParseIRIRef(%q) => %v", tt.input, err)
+		}
+		if got := r.Normalize().String(); got != tt.want {
+			t.Errorf("ParseIRIRef(%q).Normalize() => %q; want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestIRIRelativize(t *testing.T) {
+	tests := []struct {
+		base   string
+		target string
+		want   string
+	}{
+		{"http://a/b/c/d", "http://a/b/c/e", "e"},
+		{"http://a/b/c/d", "http://a/b/c/d", ""},
+		{"http://a/b/c/d", "http://a/b/c/d#x", "#x"},
+		{"http://a/b/c/d", "http://a/b/c/", "./"},
+		{"http://a/b/c/d", "http://a/b/c/e/f?q", "e/f?q"},
+		{"http://a/b/c/d", "http://a/b/e", "../e"},
+		{"http://a/b/c/d", "http://a/e", "../../e"},
+		{"http://a/b/c/d?q", "http://a/b/c/d", "d"},
+		{"http://a/b/c/d", "http://a/b/c/d?q", "?q"},
+		{"http://a/b/c/d", "http://a/b/c/x:y", "./x:y"},
+		{"http://a/b/c/d", "http://b/c", "http://b/c"},
+		{"http://a/b/c/d", "https://a/b/c/d", "https://a/b/c/d"},
+		{"http://a/b/c/d", "http://a/b/../c", "http://a/b/../c"},
+	}
+
+	for _, tt := range tests {
+		base, target := IRI{str: tt.base}, IRI{str: tt.target}
+		got := base.Relativize(target)
+		if got != tt.want {
+			t.Errorf("IRI(%q).Relativize(%q) => %q; want %q", tt.base, tt.target, got, tt.want)
+			continue
+		}
+		if got == tt.target {
+			continue
+		}
+		if back, err := base.Resolve(got); err != nil || back != target {
+			t.Errorf("IRI(%q).Resolve(%q) => %v, %v; want %v", tt.base, got, back, err, target)
+		}
+	}
+}
+
+func TestDecodeRelativeIRIs(t *testing.T) {
+	tests := []struct {
+		input  string
+		format Format
+		want   []Triple
+	}{
+		{`@base <http://ex/a/b/> .
+BASE <../c/>
+PREFIX x: <./x/>
+<../s> x:p <#o> .`, Turtle, []Triple{
+			Triple{
+				Subj: IRI{str: "http://ex/a/s"},
+				Pred: IRI{str: "http://ex/a/c/x/p"},
+				Obj:  IRI{str: "http://ex/a/c/#o"},
+			},
+		}},
+		{`<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns:ex="http://ex/" xml:base="http://ex/a/b">
+  <rdf:Description rdf:about="../s" xml:base="c/">
+    <ex:p rdf:resource="./o"/>
+  </rdf:Description>
+</rdf:RDF>`, RDFXML, []Triple{
+			Triple{
+				Subj: IRI{str: "http://ex/a/s"},
+				Pred: IRI{str: "http://ex/p"},
+				Obj:  IRI{str: "http://ex/a/c/o"},
+			},
+		}},
+	}
+
+	for _, test := range tests {
+		dec := NewTripleDecoder(bytes.NewBufferString(test.input), test.format)
+		triples, err := dec.DecodeAll()
+		if err != nil {
+			t.Fatalf("decoding %s => %v", test.input, err)
+		}
+		if !reflect.DeepEqual(triples, test.want) {
+			t.Errorf("decoding %s => %v; want %v", test.input, triples, test.want)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package rdf
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// grandfathered maps the lowercased grandfathered language tags of BCP 47
+// to their canonical case. These tags predate the current syntax, and some
+// of them are not well-formed according to it.
+var grandfathered = map[string]string{
+	// irregular
+	"en-gb-oed":  "en-GB-oed",
+	"i-ami":      "i-ami",
+	"i-bnn":      "i-bnn",
+	"i-default":  "i-default",
+	"i-enochian": "i-enochian",
+	"i-hak":      "i-hak",
+	"i-klingon":  "i-klingon",
+	"i-lux":      "i-lux",
+	"i-mingo":    "i-mingo",
+	"i-navajo":   "i-navajo",
+	"i-pwn":      "i-pwn",
+	"i-tao":      "i-tao",
+	"i-tay":      "i-tay",
+	"i-tsu":      "i-tsu",
+	"sgn-be-fr":  "sgn-BE-FR",
+	"sgn-be-nl":  "sgn-BE-NL",
+	"sgn-ch-de":  "sgn-CH-DE",
+
+	// regular
+	"art-lojban":  "art-lojban",
+	"cel-gaulish": "cel-gaulish",
+	"no-bok":      "no-bok",
+	"no-nyn":      "no-nyn",
+	"zh-guoyu":    "zh-guoyu",
+	"zh-hakka":    "zh-hakka",
+	"zh-min":      "zh-min",
+	"zh-min-nan":  "zh-min-nan",
+	"zh-xiang":    "zh-xiang",
+}
+
+// parseLangTag checks that a language tag is well-formed according to
+// BCP 47 (http://tools.ietf.org/html/bcp47 [section 2.1 and 2.2.9]), and
+// returns it in canonical case; the language and other subtags in lowercase,
+// the script subtag in titlecase and the region subtag in uppercase, e.g.:
+//
+//  EN-us                    -> en-US
+//  ZH-hant-tw               -> zh-Hant-TW
+//  sl-ROZAJ-biske-1994      -> sl-rozaj-biske-1994
+//  de-CH-x-PHONEBK          -> de-CH-x-phonebk
+func parseLangTag(tag string) (string, error) {
+	if tag == "" {
+		return "", errors.New("invalid language tag: empty")
+	}
+	for _, r := range tag {
+		if !isAlphaOrDigit(r) && r != '-' {
+			return "", fmt.Errorf("invalid language tag: unexpected character: %q", r)
+		}
+	}
+	if !isAlpha(rune(tag[0])) {
+		return "", errors.New("invalid language tag: must start with a letter")
+	}
+	if tag[len(tag)-1] == '-' {
+		return "", errors.New("invalid language tag: trailing '-' disallowed")
+	}
+
+	lower := strings.ToLower(tag)
+	if g, ok := grandfathered[lower]; ok {
+		return g, nil
+	}
+	subtags := strings.Split(lower, "-")
+	for _, s := range subtags {
+		if s == "" {
+			return "", errors.New("invalid language tag: empty subtag")
+		}
+		if len(s) > 8 {
+			return "", fmt.Errorf("invalid language tag: subtag too long: %q", s)
+		}
+	}
+
+	i := 0
+	if subtags[0] != "x" {
+		// language
+		lang := subtags[0]
+		if !isAlphaN(lang, 2, 8) {
+			return "", fmt.Errorf("invalid language tag: invalid primary language subtag: %q", lang)
+		}
+		i++
+
+		// extlang
+		for n := 0; n < 3 && len(lang) <= 3 && i < len(subtags) && isAlphaN(subtags[i], 3, 3); n++ {
+			i++
+		}
+
+		// script
+		if i < len(subtags) && isAlphaN(subtags[i], 4, 4) {
+			subtags[i] = strings.ToUpper(subtags[i][:1]) + subtags[i][1:]
+			i++
+		}
+
+		// region
+		if i < len(subtags) {
+			if isAlphaN(subtags[i], 2, 2) {
+				subtags[i] = strings.ToUpper(subtags[i])
+				i++
+			} else if isDigitN(subtags[i], 3) {
+				i++
+			}
+		}
+
+		// variants
+		seen := make(map[string]bool)
+		for ; i < len(subtags) && isVariant(subtags[i]); i++ {
+			if seen[subtags[i]] {
+				return "", fmt.Errorf("invalid language tag: duplicate variant: %q", subtags[i])
+			}
+			seen[subtags[i]] = true
+		}
+
+		// extensions
+		for i < len(subtags) && len(subtags[i]) == 1 && subtags[i] != "x" {
+			if seen[subtags[i]] {
+				return "", fmt.Errorf("invalid language tag: duplicate singleton: %q", subtags[i])
+			}
+			seen[subtags[i]] = true
+			i++
+			n := 0
+			for ; i < len(subtags) && len(subtags[i]) >= 2; i++ {
+				n++
+			}
+			if n == 0 {
+				return "", fmt.Errorf("invalid language tag: empty extension: %q", subtags[i-1])
+			}
+		}
+	}
+
+	// private use
+	if i < len(subtags) && subtags[i] == "x" {
+		if i == len(subtags)-1 {
+			return "", errors.New("invalid language tag: empty private use subtag")
+		}
+		i = len(subtags)
+	}
+
+	if i < len(subtags) {
+		return "", fmt.Errorf("invalid language tag: invalid subtag: %q", subtags[i])
+	}
+	return strings.Join(subtags, "-"), nil
+}
+
+// isAlphaN checks if s consists of between min and max letters.
+func isAlphaN(s string, min, max int) bool {
+	if len(s) < min || len(s) > max {
+		return false
+	}
+	for _, r := range s {
+		if !isAlpha(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isDigitN checks if s consists of exactly n digits.
+func isDigitN(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, r := range s {
+		if !isDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isVariant checks if s is a variant subtag: 5*8alphanum / (DIGIT 3alphanum)
+func isVariant(s string) bool {
+	return len(s) >= 5 || (len(s) == 4 && isDigit(rune(s[0])))
+}
@@ -3,9 +3,12 @@ package rdf
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 )
 
 type tokenType int
@@ -44,8 +47,54 @@ const (
 	tokenPropertyListEnd   // ']'
 	tokenCollectionStart   // '('
 	tokenCollectionEnd     // ')'
+	tokenQuotedStart       // '<<'
+	tokenQuotedEnd         // '>>'
 )
 
+// tokenName holds the names of the token types, used in error messages.
+var tokenName = map[tokenType]string{
+	tokenError:             "Error",
+	tokenEOL:               "EOL",
+	tokenEOF:               "EOF",
+	tokenIRIAbs:            "IRI (absolute)",
+	tokenIRIRel:            "IRI (relative)",
+	tokenLiteral:           "Literal",
+	tokenLiteral3:          "Literal (triple-quoted string)",
+	tokenLiteralInteger:    "Literal (integer shorthand syntax)",
+	tokenLiteralDouble:     "Literal (double shorthand syntax)",
+	tokenLiteralDecimal:    "Literal (decimal shorthand syntax)",
+	tokenLiteralBoolean:    "Literal (boolean shorthand syntax)",
+	tokenBNode:             "Blank node",
+	tokenLangMarker:        "Language tag marker",
+	tokenLang:              "Language tag",
+	tokenDataTypeMarker:    "Literal datatype marker",
+	tokenDot:               "Dot",
+	tokenSemicolon:         "Semicolon",
+	tokenComma:             "Comma",
+	tokenRDFType:           "rdf:type",
+	tokenPrefix:            "@prefix",
+	tokenPrefixLabel:       "Prefix label",
+	tokenIRISuffix:         "IRI suffix",
+	tokenBase:              "@base",
+	tokenSparqlBase:        "BASE",
+	tokenSparqlPrefix:      "PREFIX",
+	tokenAnonBNode:         "Anonymous blank node",
+	tokenPropertyListStart: "Property list start",
+	tokenPropertyListEnd:   "Property list end",
+	tokenCollectionStart:   "Collection start",
+	tokenCollectionEnd:     "Collection end",
+	tokenQuotedStart:       "Quoted triple start",
+	tokenQuotedEnd:         "Quoted triple end",
+}
+
+func (t tokenType) String() string {
+	s := tokenName[t]
+	if s == "" {
+		return fmt.Sprintf("token%d", int(t))
+	}
+	return s
+}
+
 const eof = -1
 
 func min(a, b int) int {
@@ -58,9 +107,51 @@ func min(a, b int) int {
 // token represents a token emitted by the lexer.
 type token struct {
 	typ  tokenType // type of token
-	line int       // line number
-	col  int       // column number (NB measured in bytes, not runes)
-	text string    // the value of the token
+	line int       // line number, starting at 1
+	col  int       // column number, in runes, starting at 1
+	off  int64     // byte offset of the token in the input
+	text []byte    // the value of the token, or the error message of an error token
+	src  string    // the malformed input of an error token
+}
+
+// lexeme returns the token as it appears in the input, as far as it can
+// be reconstructed from the token value.
+func (t token) lexeme() string {
+	switch t.typ {
+	case tokenError:
+		return t.src
+	case tokenIRIAbs, tokenIRIRel:
+		return "<" + string(t.text) + ">"
+	case tokenLiteral:
+		return `"` + string(t.text) + `"`
+	case tokenLiteral3:
+		return `"""` + string(t.text) + `"""`
+	case tokenPrefix, tokenBase:
+		return "@" + string(t.text)
+	case tokenPrefixLabel:
+		return string(t.text) + ":"
+	case tokenLangMarker:
+		return "@"
+	case tokenDataTypeMarker:
+		return "^^"
+	case tokenDot:
+		return "."
+	case tokenSemicolon:
+		return ";"
+	case tokenComma:
+		return ","
+	case tokenAnonBNode:
+		return "[]"
+	case tokenPropertyListStart:
+		return "["
+	case tokenPropertyListEnd:
+		return "]"
+	case tokenCollectionStart:
+		return "("
+	case tokenCollectionEnd:
+		return ")"
+	}
+	return string(t.text)
 }
 
 // stateFn represents the state of the lexer as a function that returns the next state.
@@ -70,40 +161,93 @@ type stateFn func(*lexer) stateFn
 //
 // Tokens for whitespace and comments are not not emitted.
 //
+// The lexer is pull-based: nextToken runs the state functions until they
+// have scanned a token. The text of the tokens are slices of the input
+// buffer, and are only copied by the decoders when they are turned into
+// terms. In line mode the buffer is reused for each line, since the
+// decoders are done with the tokens of a line before they ask for the
+// first token of the next line. The Turtle decoder may hold on to tokens
+// from earlier lines, so in Turtle mode lines are appended to the buffer,
+// and a new buffer is started when it is full.
+//
 // The design of the lexer and indeed much of the implementation is lifted from
 // the template lexer in Go's standard library, and is governed by a BSD licence
 // and Copyright 2011 The Go Authors.
 type lexer struct {
 	rdr *bufio.Reader
 
-	input    []byte     // the input being scanned (should not inlcude newlines)
-	lineMode bool       // true when lexing line-based formats (N-Triples & N-Quads)
-	unEsc    bool       // true when current token needs to be unescaped
-	state    stateFn    // the next lexing function to enter
-	line     int        // the current line number
-	pos      int        // the current position in input
-	width    int        // width of the last rune read from input
-	start    int        // start of current token
-	tokens   chan token // channel of scanned tokens
+	buf       []byte  // buffer the input lines are read into
+	input     []byte  // the input being scanned; the current line in buf
+	lineStart int     // index in buf of input
+	lineMode  bool    // true when lexing line-based formats (N-Triples & N-Quads)
+	star      bool    // true when lexing quoted triples ('<<' and '>>')
+	closing   string  // closing delimiter of the literal or IRI being lexed
+	unEsc     bool    // true when current token needs to be unescaped
+	state     stateFn // the next lexing function to enter
+	line      int     // the current line number
+	pos       int     // the current position in input
+	width     int     // width of the last rune read from input
+	start     int     // start of current token
+	tokens    []token // scanned tokens not yet returned by nextToken
+	head      int     // index in tokens of the next token to return
+	eof       bool    // true when the input is exhausted
+	readErr   error   // error reading the input, other than io.EOF
+	lim       limits  // resource limits
+
+	ctx    context.Context // the lexer stops when ctx is done
+	done   <-chan struct{} // ctx.Done()
+	closed bool            // true when stopped
+
+	// Position tracking; see position().
+	read      int64 // number of bytes read
+	inputOff  int64 // byte offset of input in the document
+	inputLine int   // line number of the start of input
+	posIdx    int   // index in input of the last position computed
+	posLine   int   // line number at posIdx
+	posCol    int   // rune column at posIdx
 }
 
-func newLexer(r io.Reader) *lexer {
-	l := lexer{
-		rdr:    bufio.NewReader(r),
-		tokens: make(chan token),
+// lexBufSize is the size of the buffers the Turtle lexer reads lines into.
+const lexBufSize = 32 << 10
+
+func newLexer(ctx context.Context, r io.Reader) *lexer {
+	return &lexer{
+		rdr:    bufio.NewReader(cancelable(ctx, r)),
+		buf:    make([]byte, 0, lexBufSize),
+		tokens: make([]token, 0, 4),
+		ctx:    ctx,
+		done:   ctx.Done(),
 	}
-	go l.run()
-	return &l
 }
 
-func newLineLexer(r io.Reader) *lexer {
-	l := lexer{
-		rdr:      bufio.NewReader(r),
-		tokens:   make(chan token),
+func newLineLexer(ctx context.Context, r io.Reader) *lexer {
+	return &lexer{
+		rdr:      bufio.NewReader(cancelable(ctx, r)),
+		tokens:   make([]token, 0, 4),
 		lineMode: true,
+		ctx:      ctx,
+		done:     ctx.Done(),
+	}
+}
+
+// stop stops the lexer, and releases its buffers.
+func (l *lexer) stop() {
+	l.closed = true
+	l.buf, l.input, l.tokens, l.head = nil, nil, nil, 0
+	l.state = nil
+	l.eof = true
+}
+
+// err returns ErrDecoderClosed if the lexer is stopped, the cause of the
+// lexer's context being done, or the error which ended reading the input.
+func (l *lexer) err() error {
+	if l.closed {
+		return ErrDecoderClosed
 	}
-	go l.run()
-	return &l
+	if l.ctx.Err() != nil {
+		return context.Cause(l.ctx)
+	}
+	return l.readErr
 }
 
 // next returns the next rune in the input.
@@ -130,7 +274,7 @@ func (l *lexer) backup() {
 	l.pos -= l.width
 }
 
-func (l *lexer) unescape(s string, t tokenType) string {
+func (l *lexer) unescape(s []byte, t tokenType) []byte {
 	if !l.unEsc {
 		return s
 	}
@@ -141,95 +285,158 @@ func (l *lexer) unescape(s string, t tokenType) string {
 	return unescapeNumericString(s)
 }
 
-func unescapeNumericString(s string) string {
-	r := []rune(s)
-	buf := bytes.NewBuffer(make([]byte, 0, len(r)))
+func unescapeNumericString(s []byte) []byte {
+	buf := make([]byte, 0, len(s))
 
-	for i := 0; i < len(r); {
-		switch r[i] {
-		case '\\':
-			i++
-			var c byte
-			switch r[i] {
-			case 't':
-				c = '\t'
-			case 'b':
-				c = '\b'
-			case 'n':
-				c = '\n'
-			case 'r':
-				c = '\r'
-			case 'f':
-				c = '\f'
-			case '"':
-				c = '"'
-			case '\'':
-				c = '\''
-			case '\\':
-				c = '\\'
-			case 'u':
-				rc, _ := strconv.ParseInt(string(r[i+1:i+5]), 16, 32)
-				// we can safely assume no error, because we allready veryfied
-				// the escape sequence in the lex state funcitons
-				buf.WriteRune(rune(rc))
-				i += 5
-				continue
-			case 'U':
-				rc, _ := strconv.ParseInt(string(r[i+1:i+9]), 16, 32)
-				// we can safely assume no error, because we allready veryfied
-				// the escape sequence in the lex state funcitons
-				buf.WriteRune(rune(rc))
-				i += 9
-				continue
-			}
-			buf.WriteByte(c)
-		default:
-			buf.WriteRune(r[i])
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			buf = append(buf, s[i])
+			continue
 		}
 		i++
+		switch s[i] {
+		case 't':
+			buf = append(buf, '\t')
+		case 'b':
+			buf = append(buf, '\b')
+		case 'n':
+			buf = append(buf, '\n')
+		case 'r':
+			buf = append(buf, '\r')
+		case 'f':
+			buf = append(buf, '\f')
+		case 'u':
+			// we can safely assume valid hex digits, because we allready
+			// veryfied the escape sequence in the lex state funcitons
+			buf = utf8.AppendRune(buf, hexRune(s[i+1:i+5]))
+			i += 4
+		case 'U':
+			buf = utf8.AppendRune(buf, hexRune(s[i+1:i+9]))
+			i += 8
+		default:
+			// '"', '\'' or '\\'
+			buf = append(buf, s[i])
+		}
 	}
-	return buf.String()
+	return buf
 }
 
-func unescapeReservedChars(s string) string {
-	r := []rune(s)
-	buf := bytes.NewBuffer(make([]byte, 0, len(r)))
+// hexRune returns the rune with the code point given by the hex digits.
+func hexRune(digits []byte) rune {
+	var r rune
+	for _, c := range digits {
+		switch {
+		case c >= 'a':
+			c -= 'a' - 10
+		case c >= 'A':
+			c -= 'A' - 10
+		default:
+			c -= '0'
+		}
+		r = r<<4 | rune(c)
+	}
+	return r
+}
 
-	for i := 0; i < len(r); {
-		switch r[i] {
-		case '\\':
+func unescapeReservedChars(s []byte) []byte {
+	buf := make([]byte, 0, len(s))
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			// the lexer only allows reserved characters to be escaped
 			i++
-			var c rune
-			switch r[i] {
-			case '_', '~', '.', '-', '!', '$', '&', '\'', '(', ')', '*', '+', ',', ';', '=', '/', '?', '#', '@', '%':
-				c = r[i]
-			}
-			buf.WriteRune(c)
-		default:
-			buf.WriteRune(r[i])
 		}
-		i++
+		buf = append(buf, s[i])
 	}
-	return buf.String()
+	return buf
 }
 
 // emit publishes a token back to the comsumer.
 func (l *lexer) emit(typ tokenType) {
+	l.checkLen(typ)
+	l.send(typ, l.start-delimWidth(typ), l.unescape(l.input[l.start:l.pos], typ))
+}
+
+// checkLen panics with a limit error if the pending input is longer than
+// allowed for a token of the given type.
+func (l *lexer) checkLen(typ tokenType) {
+	var err error
+	switch {
+	case typ >= tokenLiteral && typ <= tokenLiteralBoolean:
+		if l.lim.literalLen > 0 && l.pos-l.start > l.lim.literalLen {
+			err = ErrLiteralTooLong
+		}
+	case typ == tokenIRIAbs || typ == tokenIRIRel || typ == tokenIRISuffix:
+		if l.lim.iriLen > 0 && l.pos-l.start > l.lim.iriLen {
+			err = ErrIRITooLong
+		}
+	}
+	if err != nil {
+		begin := l.start - delimWidth(typ)
+		line, col, off := l.position(begin)
+		panic(l.lim.errorAt(err, line, col, off, l.input[begin:l.pos]))
+	}
+}
+
+// emitEmpty publishes a token without text, like punctuation, positioned at
+// the start of the pending input.
+func (l *lexer) emitEmpty(typ tokenType) {
+	l.send(typ, l.start, nil)
+}
+
+// send publishes a token with the given text, positioned at input[begin],
+// and skips over the pending input.
+func (l *lexer) send(typ tokenType, begin int, text []byte) {
 	if typ == tokenEOL && !l.lineMode {
 		// Don't emit EOL tokens in linemode
 		l.start = l.pos
 		return
 	}
-	l.tokens <- token{
+	line, col, off := l.position(begin)
+	l.tokens = append(l.tokens, token{
 		typ:  typ,
-		line: l.line,
-		col:  l.start,
-		text: l.unescape(string(l.input[l.start:l.pos]), typ),
-	}
+		line: line,
+		col:  col,
+		off:  off,
+		text: text,
+	})
 
 	l.start = l.pos
 }
 
+// delimWidth returns the width of the opening delimiter, which the lexer
+// leaves out of tokens of the given type, so that the tokens can be
+// positioned at the delimiter.
+func delimWidth(typ tokenType) int {
+	switch typ {
+	case tokenIRIAbs, tokenIRIRel, tokenLiteral, tokenPrefix, tokenBase:
+		return 1
+	case tokenLiteral3:
+		return 3
+	}
+	return 0
+}
+
+// position returns the line number, rune column and byte offset in the
+// document of input[i]. It counts from the last position computed, so the
+// cost of calling it for each token is linear in the length of the line.
+func (l *lexer) position(i int) (line, col int, off int64) {
+	if i < l.posIdx {
+		l.posIdx, l.posLine, l.posCol = 0, l.inputLine, 1
+	}
+	for l.posIdx < i && l.posIdx < len(l.input) {
+		r, w := decodeRune(l.input[l.posIdx:])
+		if r == '\n' {
+			l.posLine++
+			l.posCol = 1
+		} else {
+			l.posCol++
+		}
+		l.posIdx += w
+	}
+	return l.posLine, l.posCol, l.inputOff + int64(i)
+}
+
 // ignore skips over the pending input before this point.
 func (l *lexer) ignore() {
 	l.start = l.pos
@@ -289,67 +496,200 @@ func (l *lexer) acceptCaseInsensitive(s string) bool {
 	return true
 }
 
-// nextToken returns the next token from the input.
+// nextToken returns the next token from the input, running the state
+// functions until they have scanned one. The text of the token is only
+// valid until the lexer reads the next line, in line mode.
 func (l *lexer) nextToken() token {
-	tok := <-l.tokens
+	for l.head == len(l.tokens) {
+		l.tokens, l.head = l.tokens[:0], 0
+		if l.state == nil {
+			if !l.feed(false) {
+				if len(l.tokens) > 0 {
+					// EOL of a final comment line
+					break
+				}
+				return token{typ: tokenEOF}
+			}
+			l.state = lexAny
+		}
+		l.state = l.state(l)
+	}
+	tok := l.tokens[l.head]
+	l.head++
 	return tok
 }
 
+// feed reads the next line of input, and returns false at the end of the
+// input. When overwrite is true, the line is appended to the current input,
+// for literals spanning multiple lines. If the lexer's context is done, it
+// panics with the cause; the decoders recover it and return it as the error.
+// It panics likewise with errors reading the input, like a corrupt compressed
+// stream, dropping the incomplete line read before the error.
 func (l *lexer) feed(overwrite bool) bool {
 again:
-	line, err := l.rdr.ReadBytes('\n')
-	if err != nil && len(line) == 0 {
+	l.checkDone()
+	if l.eof {
 		return false
 	}
 
-	l.line++
-	if len(line) == 0 || line[0] == '#' {
-		// skip empty lines and lines starting with comment
-		l.emit(tokenEOL)
-		goto again
+	if !overwrite {
+		switch {
+		case l.lineMode:
+			l.buf = l.buf[:0]
+		case cap(l.buf)-len(l.buf) < lexBufSize/8:
+			// Don't overwrite the lines in buf; their tokens may still
+			// be in the decoder's lookahead.
+			l.buf = make([]byte, 0, lexBufSize)
+		}
+		l.lineStart = len(l.buf)
+	}
+	n := len(l.buf)
+	l.readLine()
+	line := l.buf[n:]
+	if l.readErr != nil || len(line) == 0 {
+		// The read may have blocked until the context was done.
+		l.checkDone()
+		l.eof = true
+		if l.readErr != nil {
+			panic(l.readErr)
+		}
+		return false
 	}
 
+	l.line++
+	off := l.read
+	l.read += int64(len(line))
+	l.input = l.buf[l.lineStart:]
 	if overwrite {
-		// multi-line literal, concat to current input
-		l.input = append(l.input, line...)
-	} else {
-		l.input = line
-		l.pos = 0
-		l.start = 0
+		// multi-line literal, concatenated to current input
+		return true
+	}
+
+	l.pos = 0
+	l.start = 0
+	l.inputOff = off
+	l.inputLine = l.line
+	l.posIdx, l.posLine, l.posCol = 0, l.line, 1
+
+	if line[0] == '#' {
+		// skip lines starting with comment
+		l.emitEmpty(tokenEOL)
+		l.buf = l.buf[:l.lineStart]
+		goto again
 	}
 
 	return true
 }
 
-// run runs the state machine for the lexer.
-func (l *lexer) run() {
+// checkDone panics with the cause of the lexer's context being done.
+func (l *lexer) checkDone() {
+	select {
+	case <-l.done:
+		panic(context.Cause(l.ctx))
+	default:
+	}
+}
+
+// readLine appends the next line of input to buf, including the newline.
+func (l *lexer) readLine() {
+	n := len(l.buf)
 	for {
-		if !l.feed(false) {
-			break
+		frag, err := l.rdr.ReadSlice('\n')
+		l.buf = append(l.buf, frag...)
+		if max := l.lim.lineLen; max > 0 && len(bytes.TrimSuffix(l.buf[n:], []byte("\n"))) > max {
+			// Stop reading the line, and fail without returning any of it.
+			l.readErr = l.lim.errorAt(ErrLineTooLong, l.line+1, 1, l.read, l.buf[n:])
+			return
 		}
-
-		for l.state = lexAny; l.state != nil; {
-			l.state = l.state(l)
+		if err != bufio.ErrBufferFull {
+			if err != nil && err != io.EOF {
+				l.readErr = err
+			}
+			return
 		}
 	}
-
-	// No more input to lex, emit final EOF token and terminate.
-	// The value of the closed tokens channel is tokenEOF.
-	close(l.tokens)
 }
 
 // state functions:
 
-// errorf returns an error token and terminates the scan by passing
-// back a nil pointer that will be the next state, terminating l.nextToken.
+// errorf returns an error token and terminates the scan of the line by
+// passing back a nil pointer that will be the next state. In Turtle, the
+// scan resumes after the '.' which terminates the malformed statement
+// instead; see lexSkip.
+//
+// The error token is positioned at the start of the malformed token, and
+// holds its input up to where the error was found.
 func (l *lexer) errorf(format string, args ...interface{}) stateFn {
-	l.tokens <- token{
-		tokenError,
-		l.line,
-		l.pos,
-		fmt.Sprintf(format, args...),
+	end := l.pos
+	if end <= l.start && l.start < len(l.input) {
+		// include the offending rune
+		_, w := decodeRune(l.input[l.start:])
+		end = l.start + w
+	}
+	line, col, off := l.position(l.start)
+	l.tokens = append(l.tokens, token{
+		typ:  tokenError,
+		line: line,
+		col:  col,
+		off:  off,
+		text: []byte(fmt.Sprintf(format, args...)),
+		src:  strings.TrimRight(string(l.input[l.start:end]), " \t\r\n"),
+	})
+	if l.lineMode {
+		l.closing = ""
+		return nil
+	}
+	return lexSkip
+}
+
+// lexSkip skips the rest of a malformed Turtle statement, up to the '.' which
+// terminates it, emits the '.' as a Dot token and resumes lexing after it, so
+// that the decoder can skip to it in lenient mode. Literals and IRIs, as well
+// as the rest of the one the error was found in, are skipped as a whole, so a
+// '.' in them is not taken for the end of the statement. The rest of the line
+// is dropped if there is no terminating '.' on it.
+func lexSkip(l *lexer) stateFn {
+	closing := l.closing
+	l.closing = ""
+	for {
+		if closing != "" {
+			for n := 0; n < len(closing); {
+				switch l.next() {
+				case eof:
+					return nil
+				case '\\':
+					l.next()
+					n = 0
+				case rune(closing[0]):
+					n++
+				default:
+					n = 0
+				}
+			}
+			closing = ""
+		}
+		switch r := l.next(); r {
+		case eof, '\n', '#':
+			return nil
+		case '"', '\'':
+			closing = string(r)
+			if bytes.HasPrefix(l.input[l.pos:], []byte{byte(r), byte(r)}) {
+				l.pos += 2
+				closing = strings.Repeat(closing, 3)
+			}
+		case '<':
+			closing = ">"
+		case '.':
+			// A '.' followed by a name character or digit is part of
+			// a prefixed name or a number.
+			if p := l.peek(); isPnCharsBase(p) || isDigit(p) || p == '_' || p == ':' || p == '-' {
+				continue
+			}
+			l.start = l.pos - 1
+			l.emitEmpty(tokenDot)
+			return lexAny
+		}
 	}
-	return nil
 }
 
 func lexAny(l *lexer) stateFn {
@@ -377,8 +717,20 @@ func lexAny(l *lexer) stateFn {
 		//l.ignore()
 		return lexBNode
 	case '<':
+		if l.star && l.peek() == '<' {
+			l.next()
+			l.emitEmpty(tokenQuotedStart)
+			return lexAny
+		}
 		l.ignore()
 		return lexIRI
+	case '>':
+		if l.star && l.peek() == '>' {
+			l.next()
+			l.emitEmpty(tokenQuotedEnd)
+			return lexAny
+		}
+		return l.errorf("unexpected character: %q", r)
 	case 'a':
 		p := l.peek()
 		for _, a := range okAfterRDFType {
@@ -419,47 +771,39 @@ func lexAny(l *lexer) stateFn {
 		for r = l.next(); r == ' ' || r == '\t'; r = l.next() {
 		}
 		if r == ']' {
-			l.ignore()
-			l.emit(tokenAnonBNode)
+			l.emitEmpty(tokenAnonBNode)
 			return lexAny
 		}
 		l.backup()
-		l.ignore()
-		l.emit(tokenPropertyListStart)
+		l.emitEmpty(tokenPropertyListStart)
 		return lexAny
 	case ']':
 		// This must be closing of a blank node property list,
 		// since the closing of anonymous blank node is lexed above.
-		l.ignore()
-		l.emit(tokenPropertyListEnd)
+		l.emitEmpty(tokenPropertyListEnd)
 		return lexAny
 	case '(':
-		l.ignore()
-		l.emit(tokenCollectionStart)
+		l.emitEmpty(tokenCollectionStart)
 		return lexAny
 	case ')':
-		l.ignore()
-		l.emit(tokenCollectionEnd)
+		l.emitEmpty(tokenCollectionEnd)
 		return lexAny
 	case '.':
 		if isDigit(l.peek()) {
 			l.pos -= 2 // can only backup once with l.backup()
 			return lexNumber
 		}
-		l.ignore()
-		l.emit(tokenDot)
+		l.emitEmpty(tokenDot)
 		return lexAny
 	case '\r':
 		if l.peek() == '\n' {
 			l.next()
 			return lexAny
 		}
-		l.ignore()
-		l.emit(tokenEOL)
+		l.emitEmpty(tokenEOL)
 		return lexAny
 	case '\n':
-		l.ignore()
-		l.emit(tokenEOL)
+		l.emitEmpty(tokenEOL)
 		return nil
 	case ';':
 		l.emit(tokenSemicolon)
@@ -469,8 +813,7 @@ func lexAny(l *lexer) stateFn {
 		return lexAny
 	case '#', eof:
 		// comment tokens are not emitted, so treated as eof
-		l.ignore()
-		l.emit(tokenEOL)
+		l.emitEmpty(tokenEOL)
 		return nil // This parks the lexer until it gets more input
 	case 'P', 'p':
 		if l.acceptCaseInsensitive("PREFIX") {
@@ -537,6 +880,7 @@ func hasValidScheme(l *lexer) bool {
 func _lexIRI(l *lexer) (stateFn, bool) {
 	hasScheme := false    // does it have a scheme? defines if IRI is absolute or relative
 	maybeAbsolute := true // false if we reach a non-valid scheme rune before ':'
+	l.closing = ">"
 	for {
 		r := l.next()
 		if r == eof {
@@ -600,6 +944,7 @@ func _lexIRI(l *lexer) (stateFn, bool) {
 		}
 	}
 	l.backup()
+	l.closing = ""
 	return nil, hasScheme
 }
 
@@ -646,6 +991,10 @@ func lexLiteral(l *lexer) stateFn {
 		l.pos = l.start
 		goto done
 	}
+	l.closing = `"""`[:min(quoteCount, 3)]
+	if quote == '\'' {
+		l.closing = `'''`[:min(quoteCount, 3)]
+	}
 outer:
 	for {
 		switch r {
@@ -654,6 +1003,7 @@ outer:
 				return l.errorf("bad literal: newline not allowed in single-quoted string")
 			}
 			// triple-quoted strings can contain newlines
+			l.checkLen(tokenLiteral3)
 			if !l.feed(true) {
 				return l.errorf("bad literal: no closing quote: %q", quote)
 			}
@@ -707,6 +1057,7 @@ outer:
 		r = l.next()
 	}
 done:
+	l.closing = ""
 	if quoteCount == 3 || quoteCount == 6 {
 		l.emit(tokenLiteral3)
 	} else {
@@ -849,30 +1200,13 @@ func lexBNode(l *lexer) stateFn {
 }
 
 func lexLang(l *lexer) stateFn {
-	// NOTE this is only a rough check of the language tag's well-formedness.
-	// TODO: Full conformance with the spec is quite complex:
-	// http://tools.ietf.org/html/bcp47 [section 2.1]
-	c := 0
-	var r rune
-	for r = l.next(); isAlpha(r); r = l.next() {
-		c++
+	for r := l.next(); isAlphaOrDigit(r) || r == '-'; r = l.next() {
 	}
+	l.backup()
 
-	if c == 0 {
+	if _, err := parseLangTag(string(l.input[l.start:l.pos])); err != nil {
 		return l.errorf("bad literal: invalid language tag")
 	}
-	if r == '-' {
-		c = 0
-		for r := l.next(); isAlphaOrDigit(r) || r == '-'; r = l.next() {
-			c++
-		}
-		l.backup()
-		if c == 0 {
-			return l.errorf("bad literal: invalid language tag")
-		}
-	} else {
-		l.backup()
-	}
 
 	l.emit(tokenLang)
 	return lexAny
@@ -1,53 +1,12 @@
 package rdf
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
 )
 
-// Make the token types prettyprint.
-var tokenName = map[tokenType]string{
-	tokenError:             "Error",
-	tokenEOL:               "EOL",
-	tokenEOF:               "EOF",
-	tokenIRIAbs:            "IRI (absolute)",
-	tokenIRIRel:            "IRI (relative)",
-	tokenLiteral:           "Literal",
-	tokenLiteral3:          "Literal (triple-quoted string)",
-	tokenLiteralInteger:    "Literal (integer shorthand syntax)",
-	tokenLiteralDouble:     "Literal (double shorthand syntax)",
-	tokenLiteralDecimal:    "Literal (decimal shorthand syntax)",
-	tokenLiteralBoolean:    "Literal (boolean shorthand syntax)",
-	tokenBNode:             "Blank node",
-	tokenLangMarker:        "Language tag marker",
-	tokenLang:              "Language tag",
-	tokenDataTypeMarker:    "Literal datatype marker",
-	tokenDot:               "Dot",
-	tokenSemicolon:         "Semicolon",
-	tokenComma:             "Comma",
-	tokenRDFType:           "rdf:type",
-	tokenPrefix:            "@prefix",
-	tokenPrefixLabel:       "Prefix label",
-	tokenIRISuffix:         "IRI suffix",
-	tokenBase:              "@base",
-	tokenSparqlBase:        "BASE",
-	tokenSparqlPrefix:      "PREFIX",
-	tokenAnonBNode:         "Anonymous blank node",
-	tokenPropertyListStart: "Property list start",
-	tokenPropertyListEnd:   "Property list end",
-	tokenCollectionStart:   "Collection start",
-	tokenCollectionEnd:     "Collection end",
-}
-
-func (t tokenType) String() string {
-	s := tokenName[t]
-	if s == "" {
-		return fmt.Sprintf("token%d", int(t))
-	}
-	return s
-}
-
 type testToken struct {
 	Typ  tokenType
 	Text string
@@ -57,7 +16,7 @@ func collect(l *lexer) []testToken {
 	tokens := []testToken{}
 	for {
 		tk := l.nextToken()
-		tokens = append(tokens, testToken{tk.typ, tk.text})
+		tokens = append(tokens, testToken{tk.typ, string(tk.text)})
 		if tk.typ == tokenEOF || tk.typ == tokenError {
 			break
 		}
@@ -214,6 +173,11 @@ two
 			{tokenLangMarker, "@"},
 			{tokenError, "bad literal: invalid language tag"}},
 		},
+		{`"hei"@nb-no2`, []testToken{
+			{tokenLiteral, "hei"},
+			{tokenLangMarker, "@"},
+			{tokenError, "bad literal: invalid language tag"}},
+		},
 		{`"Neishe"@zh-latn-pinyin-x-notone`, []testToken{
 			{tokenLiteral, "Neishe"},
 			{tokenLangMarker, "@"},
@@ -387,7 +351,7 @@ two
 	}
 
 	for _, tt := range lexTests {
-		lex := newLexer(strings.NewReader(tt.in))
+		lex := newLexer(context.Background(), strings.NewReader(tt.in))
 		res := []testToken{}
 		res = append(res, collect(lex)...)
 
@@ -396,3 +360,42 @@ two
 		}
 	}
 }
+
+func TestLexBuffers(t *testing.T) {
+	// Statements spanning lines, in a document larger than the lexer's
+	// buffer, must not be affected by the reuse of the buffer.
+	n := lexBufSize / 32
+	var ttl, nt strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&ttl, "<http://ex/s%d>\n  <http://ex/p> \"\"\"literal\n%d\"\"\"@en ,\n  _:b%d .\n", i, i, i)
+		fmt.Fprintf(&nt, "<http://ex/s%d> <http://ex/p> \"%s\" .\n", i, strings.Repeat("x", i%100))
+	}
+
+	ts, err := NewTripleDecoder(strings.NewReader(ttl.String()), Turtle).DecodeAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ts) != 2*n {
+		t.Fatalf("decoding Turtle => %d triples; want %d", len(ts), 2*n)
+	}
+	for i := 0; i < len(ts); i += 2 {
+		want := fmt.Sprintf("<http://ex/s%d> <http://ex/p> \"literal\\n%d\"@en .\n<http://ex/s%d> <http://ex/p> _:b%d .\n", i/2, i/2, i/2, i/2)
+		if got := ts[i].Serialize(NTriples) + ts[i+1].Serialize(NTriples); got != want {
+			t.Fatalf("decoding Turtle => %q; want %q", got, want)
+		}
+	}
+
+	ts, err = NewTripleDecoder(strings.NewReader(nt.String()), NTriples).DecodeAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ts) != n {
+		t.Fatalf("decoding N-Triples => %d triples; want %d", len(ts), n)
+	}
+	for i, tr := range ts {
+		want := fmt.Sprintf("<http://ex/s%d> <http://ex/p> \"%s\" .\n", i, strings.Repeat("x", i%100))
+		if got := tr.Serialize(NTriples); got != want {
+			t.Fatalf("decoding N-Triples => %q; want %q", got, want)
+		}
+	}
+}
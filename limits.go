@@ -0,0 +1,142 @@
+package rdf
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Errors for documents exceeding the limits set with the Max... options.
+// Except for ErrTooManyTriples, they are wrapped in a *ParseError of kind
+// LimitExceeded, which locates where the limit was exceeded:
+//
+//  if errors.Is(err, rdf.ErrLineTooLong) {
+//  	// reject the document
+//  }
+var (
+	ErrLiteralTooLong = errors.New("literal too long")
+	ErrIRITooLong     = errors.New("IRI too long")
+	ErrLineTooLong    = errors.New("line too long")
+	ErrNestingTooDeep = errors.New("collections and property lists nested too deep")
+	ErrXMLTooDeep     = errors.New("XML elements nested too deep")
+	ErrTooManyTriples = errors.New("too many triples")
+)
+
+// limits holds the resource limits of a decoder. Zero means no limit.
+type limits struct {
+	format     Format // format of the document, for the errors
+	literalLen int
+	iriLen     int
+	lineLen    int
+	nesting    int
+	xmlDepth   int
+	triples    int
+	count      int // number of triples decoded
+}
+
+// setOption sets one of the Max... options to the given value.
+func (lim *limits) setOption(o ParseOption, v interface{}) error {
+	var p *int
+	var name string
+	switch o {
+	case MaxLiteralLen:
+		p, name = &lim.literalLen, "MaxLiteralLen"
+	case MaxIRILen:
+		p, name = &lim.iriLen, "MaxIRILen"
+	case MaxLineLen:
+		p, name = &lim.lineLen, "MaxLineLen"
+	case MaxNesting:
+		p, name = &lim.nesting, "MaxNesting"
+	case MaxXMLDepth:
+		p, name = &lim.xmlDepth, "MaxXMLDepth"
+	case MaxTriples:
+		p, name = &lim.triples, "MaxTriples"
+	default:
+		return fmt.Errorf("unknown limit option: %v", o)
+	}
+	n, ok := v.(int)
+	if !ok || n < 0 {
+		return fmt.Errorf("ParseOption \"%s\" must be a non-negative int.", name)
+	}
+	*p = n
+	return nil
+}
+
+// countTriple counts a decoded triple, and returns ErrTooManyTriples if
+// there are more triples than allowed.
+func (lim *limits) countTriple() error {
+	lim.count++
+	if lim.triples > 0 && lim.count > lim.triples {
+		return ErrTooManyTriples
+	}
+	return nil
+}
+
+// checkTerms returns the error of the first IRI or literal in the triple
+// exceeding its length limit, if any, with its value. Decoders which cannot
+// check the lengths while parsing check the triples they decode.
+func (lim *limits) checkTerms(t Triple) (string, error) {
+	for _, term := range [...]Term{t.Subj, t.Pred, t.Obj} {
+		switch term := term.(type) {
+		case IRI:
+			if lim.iriLen > 0 && len(term.str) > lim.iriLen {
+				return term.str, ErrIRITooLong
+			}
+		case Literal:
+			if lim.literalLen > 0 && len(term.str) > lim.literalLen {
+				return term.str, ErrLiteralTooLong
+			}
+			if lim.iriLen > 0 && len(term.DataType.str) > lim.iriLen {
+				return term.DataType.str, ErrIRITooLong
+			}
+		}
+	}
+	return "", nil
+}
+
+// limit returns the limit which err reports being exceeded.
+func (lim *limits) limit(err error) int {
+	switch err {
+	case ErrLiteralTooLong:
+		return lim.literalLen
+	case ErrIRITooLong:
+		return lim.iriLen
+	case ErrLineTooLong:
+		return lim.lineLen
+	case ErrNestingTooDeep:
+		return lim.nesting
+	case ErrXMLTooDeep:
+		return lim.xmlDepth
+	}
+	return lim.triples
+}
+
+// errorAt returns the ParseError for the limit exceeded at the given
+// position, by the input starting with src.
+func (lim *limits) errorAt(err error, line, col int, off int64, src []byte) *ParseError {
+	return &ParseError{
+		Format: lim.format,
+		Kind:   LimitExceeded,
+		Line:   line,
+		Column: col,
+		Offset: off,
+		Token:  snippet(src),
+		Msg:    fmt.Sprintf("%v (limit is %d)", err, lim.limit(err)),
+		Err:    err,
+	}
+}
+
+// snippetLen is the maximum length of the input quoted in limit errors.
+const snippetLen = 40
+
+// snippet returns the start of the input, shortened to snippetLen bytes.
+func snippet(src []byte) string {
+	if len(src) <= snippetLen {
+		return string(src)
+	}
+	n := snippetLen
+	for n > 0 && !utf8.RuneStart(src[n]) {
+		n--
+	}
+	return string(src[:n]) + "..."
+}
@@ -0,0 +1,152 @@
+package rdf
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDecoderLimits(t *testing.T) {
+	long := strings.Repeat("x", 50)
+	xmlDoc := func(body string) string {
+		return "<rdf:RDF\n  xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\"\n  xmlns:ex=\"http://ex/\">\n" + body + "\n</rdf:RDF>\n"
+	}
+	tests := []struct {
+		format Format
+		option ParseOption
+		limit  int
+		input  string
+		want   error // nil if the document is within the limit
+		line   int
+	}{
+		{NTriples, MaxLiteralLen, 50, "<http://ex/s> <http://ex/p> \"" + long + "\" .\n", nil, 0},
+		{NTriples, MaxLiteralLen, 49, "<http://ex/s> <http://ex/p> \"a\" .\n<http://ex/s> <http://ex/p> \"" + long + "\" .\n", ErrLiteralTooLong, 2},
+		{NTriples, MaxIRILen, 20, "<http://ex/s> <http://ex/p> <http://ex/" + long + "> .\n", ErrIRITooLong, 1},
+		{NTriples, MaxLineLen, 60, "<http://ex/s> <http://ex/p> \"a\" .\n<http://ex/s> <http://ex/p> \"" + long + "\" .\n", ErrLineTooLong, 2},
+		{NTriples, MaxLineLen, 84, "<http://ex/s> <http://ex/p> \"" + long + "\" .\n", nil, 0},
+		{NTriples, MaxTriples, 2, "<http://ex/s> <http://ex/p> \"a\" .\n<http://ex/s> <http://ex/p> \"b\" .\n", nil, 0},
+		{NTriples, MaxTriples, 1, "<http://ex/s> <http://ex/p> \"a\" .\n<http://ex/s> <http://ex/p> \"b\" .\n", ErrTooManyTriples, 0},
+		{NQuads, MaxIRILen, 20, "<http://ex/s> <http://ex/p> <http://ex/o> <http://ex/" + long + "> .\n", ErrIRITooLong, 1},
+		{NQuads, MaxTriples, 1, "<http://ex/s> <http://ex/p> \"a\" .\n<http://ex/s> <http://ex/p> \"b\" .\n", ErrTooManyTriples, 0},
+		{Turtle, MaxLiteralLen, 49, "@prefix ex: <http://ex/> .\nex:s ex:p \"\"\"" + long[:20] + "\n" + long[:20] + "\n" + long[:20] + "\"\"\" .\n", ErrLiteralTooLong, 2},
+		{Turtle, MaxIRILen, 20, "@prefix ex: <http://ex/> .\nex:s ex:p ex:" + long + " .\n", ErrIRITooLong, 2},
+		{Turtle, MaxLineLen, 30, "@prefix ex: <http://ex/> .\nex:s ex:p \"" + long + "\" .\n", ErrLineTooLong, 2},
+		{Turtle, MaxNesting, 3, "@prefix ex: <http://ex/> .\nex:s ex:p [ ex:p [ ex:p ( ( ex:o ) ) ] ] .\n", ErrNestingTooDeep, 2},
+		{Turtle, MaxNesting, 4, "@prefix ex: <http://ex/> .\nex:s ex:p [ ex:p [ ex:p ( ( ex:o ) ) ] ] .\n( ( ( ( ex:a ex:b ) ) ) ) ex:p ex:o .\n", nil, 0},
+		{Turtle, MaxNesting, 3, "@prefix ex: <http://ex/> .\nex:s ex:p ex:o .\n\n( ( ( ( ex:a ex:b ) ) ) ) ex:p ex:o .\n", ErrNestingTooDeep, 4},
+		{Turtle, MaxTriples, 2, "@prefix ex: <http://ex/> .\nex:s ex:p ex:o1, ex:o2, ex:o3 .\n", ErrTooManyTriples, 0},
+		{RDFXML, MaxLiteralLen, 49, xmlDoc(`<rdf:Description rdf:about="http://ex/s"><ex:p>` + long + `</ex:p></rdf:Description>`), ErrLiteralTooLong, 4},
+		{RDFXML, MaxIRILen, 20, xmlDoc(`<rdf:Description rdf:about="http://ex/` + long + `"><ex:p>a</ex:p></rdf:Description>`), ErrIRITooLong, 4},
+		{RDFXML, MaxLineLen, 60, xmlDoc(`<rdf:Description rdf:about="http://ex/s">` + "\n" + `<ex:p>` + long + `</ex:p></rdf:Description>`), ErrLineTooLong, 5},
+		{RDFXML, MaxXMLDepth, 3, xmlDoc(`<rdf:Description rdf:about="http://ex/s"><ex:p><rdf:Description><ex:p>a</ex:p></rdf:Description></ex:p></rdf:Description>`), ErrXMLTooDeep, 4},
+		{RDFXML, MaxXMLDepth, 5, xmlDoc(`<rdf:Description rdf:about="http://ex/s"><ex:p><rdf:Description><ex:p>a</ex:p></rdf:Description></ex:p></rdf:Description>`), nil, 0},
+		{RDFXML, MaxTriples, 1, xmlDoc(`<rdf:Description rdf:about="http://ex/s"><ex:p>a</ex:p><ex:p>b</ex:p></rdf:Description>`), ErrTooManyTriples, 0},
+	}
+	for i, tt := range tests {
+		var err error
+		if tt.format == NQuads {
+			dec := NewQuadDecoder(strings.NewReader(tt.input), NQuads)
+			if err := dec.SetOption(tt.option, tt.limit); err != nil {
+				t.Fatalf("%d: SetOption failed with %v", i, err)
+			}
+			dec.SetOption(Strict, false)
+			_, err = dec.DecodeAll()
+		} else {
+			dec := NewTripleDecoder(strings.NewReader(tt.input), tt.format)
+			if err := dec.SetOption(tt.option, tt.limit); err != nil {
+				t.Fatalf("%d: SetOption failed with %v", i, err)
+			}
+			// Exceeded limits are not skipped in lenient mode.
+			dec.SetOption(Strict, false)
+			_, err = dec.DecodeAll()
+		}
+		if !errors.Is(err, tt.want) || (tt.want == nil) != (err == nil) {
+			t.Errorf("%d: %v => %v; want %v", i, tt.format, err, tt.want)
+			continue
+		}
+		var perr *ParseError
+		if errors.As(err, &perr) {
+			if perr.Kind != LimitExceeded || perr.Line != tt.line || perr.Format != tt.format {
+				t.Errorf("%d: %v => %v error at line %d; want limit exceeded at line %d", i, tt.format, perr.Kind, perr.Line, tt.line)
+			}
+		} else if err != nil && tt.line != 0 {
+			t.Errorf("%d: %v => %T; want *ParseError", i, tt.format, err)
+		}
+	}
+}
+
+// endlessReader returns an endless line of the byte.
+type endlessReader byte
+
+func (r endlessReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = byte(r)
+	}
+	return len(p), nil
+}
+
+func TestLineLimitEndlessInput(t *testing.T) {
+	for f, start := range map[Format]string{
+		NTriples: "<http://ex/s> <http://ex/p> \"",
+		Turtle:   "<http://ex/s> <http://ex/p> \"",
+		RDFXML:   `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"><rdf:Description rdf:about="http://ex/s"><rdf:value>`,
+	} {
+		r := io.MultiReader(strings.NewReader(start), endlessReader('x'))
+		dec := NewTripleDecoder(r, f)
+		dec.SetOption(MaxLineLen, 100000)
+		if _, err := dec.Decode(); !errors.Is(err, ErrLineTooLong) {
+			t.Errorf("%v: Decode() => %v; want ErrLineTooLong", f, err)
+		}
+		if _, err := dec.Decode(); !errors.Is(err, ErrLineTooLong) {
+			t.Errorf("%v: second Decode() => %v; want ErrLineTooLong", f, err)
+		}
+	}
+
+	r := io.MultiReader(strings.NewReader(parallelTestInput(100, false)+"<http://ex/s> <http://ex/p> \""), endlessReader('x'))
+	dec := NewParallelDecoder(r, NTriples, 2)
+	dec.chunkSize = 1024
+	dec.SetOption(MaxLineLen, 100000)
+	var err error
+	n := 0
+	for _, err = range dec.All() {
+		if err != nil {
+			break
+		}
+		n++
+	}
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Err != ErrLineTooLong || perr.Line != 101 || n != 80 {
+		t.Errorf("ParallelDecoder => %d quads, then %v; want 80 quads, then ErrLineTooLong on line 101", n, err)
+	}
+}
+
+func TestParallelDecoderLimits(t *testing.T) {
+	input := parallelTestInput(1000, false)
+	for _, ordered := range []bool{true, false} {
+		dec := NewParallelDecoder(strings.NewReader(input), NTriples, 4)
+		dec.chunkSize = 512
+		dec.SetOption(Ordered, ordered)
+		dec.SetOption(MaxTriples, 500)
+		qs, err := dec.DecodeAll()
+		if err != ErrTooManyTriples || qs != nil {
+			t.Errorf("ordered=%v: DecodeAll() => %d quads, %v; want ErrTooManyTriples", ordered, len(qs), err)
+		}
+	}
+
+	dec := NewParallelDecoder(strings.NewReader(input), NTriples, 4)
+	dec.chunkSize = 512
+	dec.SetOption(MaxTriples, 800)
+	if qs, err := dec.DecodeAll(); err != nil || len(qs) != 800 {
+		t.Errorf("DecodeAll() at the limit => %d quads, %v; want 800", len(qs), err)
+	}
+
+	for _, v := range []interface{}{-1, "10", int64(10)} {
+		if err := NewTripleDecoder(strings.NewReader(""), NTriples).SetOption(MaxLineLen, v); err == nil {
+			t.Errorf("SetOption(MaxLineLen, %#v) => no error", v)
+		}
+	}
+	if err := NewTripleDecoder(strings.NewReader(""), NTriples).SetOption(MaxNesting, 1); err == nil {
+		t.Error("SetOption(MaxNesting) on N-Triples decoder => no error")
+	}
+}
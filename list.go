@@ -0,0 +1,150 @@
+package rdf
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/knakk/rdf/internal/ns"
+)
+
+// NewList returns the triples of a rdf:List (a collection) with the given
+// objects as members, and the head of the list. The list nodes are new blank
+// nodes, labelled by a NewUUIDAllocator, so that lists created by different
+// calls never share nodes. The head of an empty list is rdf:nil, with no triples.
+func NewList(objects []Object) (head Object, triples []Triple) {
+	alloc := NewUUIDAllocator()
+	head = rdfNil
+	nodes := make([]Blank, len(objects))
+	for i := range nodes {
+		nodes[i] = Blank{id: "_:" + alloc.New()}
+	}
+	triples = make([]Triple, 0, 2*len(objects))
+	for i, o := range objects {
+		var rest Object = rdfNil
+		if i+1 < len(nodes) {
+			rest = nodes[i+1]
+		}
+		triples = append(triples,
+			Triple{Subj: nodes[i], Pred: rdfFirst, Obj: o},
+			Triple{Subj: nodes[i], Pred: rdfRest, Obj: rest})
+	}
+	if len(nodes) > 0 {
+		head = nodes[0]
+	}
+	return head, triples
+}
+
+// ReadList returns the members of the rdf:List in the graph starting at head.
+//
+// An error is returned if the list is malformed, i.e. if a node of the list does
+// not have exactly one rdf:first and one rdf:rest, or if the list is cyclic.
+func ReadList(g *Graph, head Object) ([]Object, error) {
+	var objs []Object
+	seen := make(map[termKey]bool)
+	for !TermsEqual(head, rdfNil) {
+		node, ok := head.(Subject)
+		if !ok {
+			return nil, fmt.Errorf("malformed rdf:List: %v is not a list node", head.Serialize(NTriples))
+		}
+		k := keyOf(node)
+		if seen[k] {
+			return nil, fmt.Errorf("cyclic rdf:List at %s", head.Serialize(NTriples))
+		}
+		seen[k] = true
+		r := g.Resource(node)
+		first, rest := r.Objects(rdfFirst), r.Objects(rdfRest)
+		if len(first) != 1 || len(rest) != 1 {
+			return nil, fmt.Errorf("malformed rdf:List at %s: %d rdf:first and %d rdf:rest", head.Serialize(NTriples), len(first), len(rest))
+		}
+		objs = append(objs, first[0])
+		head = rest[0]
+	}
+	return objs, nil
+}
+
+// NewSeq returns the triples of a rdf:Seq container with the given objects as
+// members, and the container node, which is a new blank node.
+func NewSeq(objects []Object) (Blank, []Triple) {
+	return newContainer(rdfSeq, objects)
+}
+
+// NewBag returns the triples of a rdf:Bag container with the given objects as
+// members, and the container node, which is a new blank node.
+func NewBag(objects []Object) (Blank, []Triple) {
+	return newContainer(rdfBag, objects)
+}
+
+// NewAlt returns the triples of a rdf:Alt container with the given objects as
+// members, and the container node, which is a new blank node.
+func NewAlt(objects []Object) (Blank, []Triple) {
+	return newContainer(rdfAlt, objects)
+}
+
+func newContainer(typ IRI, objects []Object) (Blank, []Triple) {
+	node := Blank{id: "_:" + NewUUIDAllocator().New()}
+	triples := make([]Triple, 0, len(objects)+1)
+	triples = append(triples, Triple{Subj: node, Pred: rdfType, Obj: typ})
+	for i, o := range objects {
+		triples = append(triples, Triple{Subj: node, Pred: memberProperty(i + 1), Obj: o})
+	}
+	return node, triples
+}
+
+// ReadContainer returns the members of the container in the graph, ordered
+// by their rdf:_n membership properties, and the type of the container; one of
+// rdf:Seq, rdf:Bag or rdf:Alt. Gaps in the membership indexes are allowed.
+//
+// An error is returned if the node is not a container, or if more than one
+// member have the same index.
+func ReadContainer(g *Graph, node Subject) (typ IRI, members []Object, err error) {
+	r := g.Resource(node)
+	for _, t := range r.Types() {
+		if t == rdfSeq || t == rdfBag || t == rdfAlt {
+			typ = t
+			break
+		}
+	}
+	if typ == (IRI{}) {
+		return IRI{}, nil, fmt.Errorf("%s is not a rdf:Seq, rdf:Bag or rdf:Alt", node.Serialize(NTriples))
+	}
+	type member struct {
+		n int
+		o Object
+	}
+	var ms []member
+	for _, id := range g.withSubject(node) {
+		t := g.triple(id)
+		if n, ok := memberIndex(t.Pred); ok {
+			ms = append(ms, member{n, t.Obj})
+		}
+	}
+	sort.SliceStable(ms, func(i, j int) bool { return ms[i].n < ms[j].n })
+	for i, m := range ms {
+		if i > 0 && ms[i-1].n == m.n {
+			return IRI{}, nil, fmt.Errorf("container %s has several members with index %d", node.Serialize(NTriples), m.n)
+		}
+		members = append(members, m.o)
+	}
+	return typ, members, nil
+}
+
+// memberProperty returns the container membership property rdf:_n.
+func memberProperty(n int) IRI {
+	return IRI{str: ns.RDF + "_" + strconv.Itoa(n)}
+}
+
+// memberIndex returns n if the predicate is a container membership property rdf:_n.
+func memberIndex(p Predicate) (int, bool) {
+	u, ok := p.(IRI)
+	if !ok || !strings.HasPrefix(u.str, ns.RDF+"_") {
+		return 0, false
+	}
+	digits := u.str[len(ns.RDF)+1:]
+	if digits == "" || digits[0] < '1' || digits[0] > '9' || !isInteger(digits) {
+		return 0, false
+	}
+	n, err := strconv.Atoi(digits)
+	return n, err == nil
+}
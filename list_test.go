@@ -0,0 +1,170 @@
+package rdf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewListReadList(t *testing.T) {
+	objs := []Object{IRI{str: "http://ex/a"}, NewTypedLiteral("b", xsdString), Blank{id: "_:c"}}
+	head, triples := NewList(objs)
+	if len(triples) != 6 {
+		t.Fatalf("NewList => %d triples; want 6", len(triples))
+	}
+	other, _ := NewList(objs)
+	if TermsEqual(head, other) {
+		t.Errorf("NewList => same head %v in two calls", head)
+	}
+	got, err := ReadList(NewGraph(triples...), head)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(objs) {
+		t.Fatalf("ReadList => %v; want %v", got, objs)
+	}
+	for i := range objs {
+		if !TermsEqual(got[i], objs[i]) {
+			t.Errorf("ReadList => member %d: %v; want %v", i, got[i], objs[i])
+		}
+	}
+
+	head, triples = NewList(nil)
+	if head != rdfNil || len(triples) != 0 {
+		t.Errorf("NewList(nil) => %v, %v; want rdf:nil and no triples", head, triples)
+	}
+	if got, err := ReadList(NewGraph(), head); err != nil || len(got) != 0 {
+		t.Errorf("ReadList(rdf:nil) => %v, %v; want empty list", got, err)
+	}
+}
+
+func TestReadListTurtle(t *testing.T) {
+	triples, err := NewTripleDecoder(strings.NewReader(`<http://ex/s> <http://ex/p> (1 "two" <http://ex/three>) .`), Turtle).DecodeAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGraph(triples...)
+	head, _ := g.Resource(IRI{str: "http://ex/s"}).Object(IRI{str: "http://ex/p"})
+	got, err := ReadList(g, head)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Object{NewTypedLiteral("1", xsdInteger), NewTypedLiteral("two", xsdString), IRI{str: "http://ex/three"}}
+	if len(got) != len(want) {
+		t.Fatalf("ReadList => %v; want %v", got, want)
+	}
+	for i := range want {
+		if !TermsEqual(got[i], want[i]) {
+			t.Errorf("ReadList => member %d: %v; want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestReadListErrors(t *testing.T) {
+	a, b := Blank{id: "_:a"}, Blank{id: "_:b"}
+	one := NewTypedLiteral("1", xsdInteger)
+	tests := []struct {
+		triples []Triple
+		head    Object
+		want    string
+	}{
+		{nil, one, `malformed rdf:List: "1"^^<http://www.w3.org/2001/XMLSchema#integer> is not a list node`},
+		{nil, a, `malformed rdf:List at _:a: 0 rdf:first and 0 rdf:rest`},
+		{[]Triple{
+			{Subj: a, Pred: rdfFirst, Obj: one},
+			{Subj: a, Pred: rdfFirst, Obj: NewTypedLiteral("2", xsdInteger)},
+			{Subj: a, Pred: rdfRest, Obj: rdfNil},
+		}, a, `malformed rdf:List at _:a: 2 rdf:first and 1 rdf:rest`},
+		{[]Triple{
+			{Subj: a, Pred: rdfFirst, Obj: one},
+			{Subj: a, Pred: rdfRest, Obj: b},
+			{Subj: b, Pred: rdfFirst, Obj: one},
+			{Subj: b, Pred: rdfRest, Obj: a},
+		}, a, `cyclic rdf:List at _:a`},
+		{[]Triple{
+			{Subj: a, Pred: rdfFirst, Obj: one},
+			{Subj: a, Pred: rdfRest, Obj: one},
+		}, a, `malformed rdf:List: "1"^^<http://www.w3.org/2001/XMLSchema#integer> is not a list node`},
+	}
+	for _, tt := range tests {
+		_, err := ReadList(NewGraph(tt.triples...), tt.head)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("ReadList(%v, %v) => %v; want %v", tt.triples, tt.head, err, tt.want)
+		}
+	}
+}
+
+func TestContainers(t *testing.T) {
+	objs := []Object{IRI{str: "http://ex/a"}, IRI{str: "http://ex/b"}}
+	for _, tt := range []struct {
+		fn  func([]Object) (Blank, []Triple)
+		typ IRI
+	}{
+		{NewSeq, rdfSeq},
+		{NewBag, rdfBag},
+		{NewAlt, rdfAlt},
+	} {
+		node, triples := tt.fn(objs)
+		typ, got, err := ReadContainer(NewGraph(triples...), node)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if typ != tt.typ || len(got) != 2 || !TermsEqual(got[0], objs[0]) || !TermsEqual(got[1], objs[1]) {
+			t.Errorf("ReadContainer => %v, %v; want %v, %v", typ, got, tt.typ, objs)
+		}
+	}
+
+	input := `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+  <rdf:Seq rdf:about="http://ex/seq">
+    <rdf:_3>c</rdf:_3>
+    <rdf:li>a</rdf:li>
+    <rdf:li>b</rdf:li>
+    <rdf:_10>d</rdf:_10>
+  </rdf:Seq>
+</rdf:RDF>`
+	triples, err := NewTripleDecoder(strings.NewReader(input), RDFXML).DecodeAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGraph(triples...)
+	typ, got, err := ReadContainer(g, IRI{str: "http://ex/seq"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var members []string
+	for _, o := range got {
+		members = append(members, o.String())
+	}
+	if typ != rdfSeq || strings.Join(members, " ") != "a b c d" {
+		t.Errorf("ReadContainer => %v, %v; want rdf:Seq, [a b c d]", typ, members)
+	}
+
+	g.Add(Triple{Subj: IRI{str: "http://ex/seq"}, Pred: memberProperty(2), Obj: IRI{str: "http://ex/x"}})
+	if _, _, err := ReadContainer(g, IRI{str: "http://ex/seq"}); err == nil {
+		t.Error("ReadContainer with duplicate index => no error")
+	}
+	if _, _, err := ReadContainer(g, IRI{str: "http://ex/other"}); err == nil {
+		t.Error("ReadContainer of non-container => no error")
+	}
+}
+
+func TestMemberIndex(t *testing.T) {
+	tests := []struct {
+		p    string
+		want int
+	}{
+		{rdfNS + "_1", 1},
+		{rdfNS + "_42", 42},
+		{rdfNS + "_0", 0},
+		{rdfNS + "_01", 0},
+		{rdfNS + "_+1", 0},
+		{rdfNS + "_", 0},
+		{rdfNS + "_1a", 0},
+		{"http://ex/_1", 0},
+	}
+	for _, tt := range tests {
+		if n, ok := memberIndex(IRI{str: tt.p}); n != tt.want || ok != (tt.want > 0) {
+			t.Errorf("memberIndex(%s) => %d, %v; want %d", tt.p, n, ok, tt.want)
+		}
+	}
+}
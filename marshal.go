@@ -0,0 +1,603 @@
+package rdf
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Marshal returns the triples describing v, which must be a struct, or a
+// pointer to a struct. Each exported struct field with a "rdf" tag becomes
+// one or more triples, with the field's tag as predicate:
+//
+//  type Book struct {
+//  	ID      string    `rdf:"@id"`
+//  	Types   []IRI     `rdf:"@type"`
+//  	Title   string    `rdf:"http://purl.org/dc/terms/title,lang=en"`
+//  	Authors []Person  `rdf:"http://purl.org/dc/terms/creator,list"`
+//  	Issued  time.Time `rdf:"http://purl.org/dc/terms/issued,omitempty"`
+//  }
+//
+// The subject of the triples is the IRI in the field tagged "@id", which must
+// be a string or an IRI. If there is no such field, or if it is empty, the
+// subject is a new blank node. Fields tagged "@type" give the rdf:type of the
+// subject.
+//
+// The tag options, following the predicate IRI and separated by commas, are:
+//
+//  lang=<tag>   string values are language tagged literals
+//  iri          string values are IRIs
+//  list         slice values are written as a rdf:List, instead of as one triple per element
+//  omitempty    zero values are omitted
+//
+// Field values are converted to objects as follows:
+//
+//  Go type                 Object
+//  ---------------------   ----------------------------------------------
+//  IRI, Blank, Literal     the term itself
+//  string                  xsd:string literal, or as given by options
+//  bool                    xsd:boolean literal
+//  int, uint types         literal as from NewLiteral, e.g. xsd:int for int32
+//  float types             xsd:double literal
+//  time.Time               xsd:dateTime literal
+//  []byte                  xsd:base64Binary literal
+//  struct                  the subject of the triples describing the struct
+//  pointer                 the value pointed to; nil pointers are omitted
+//  slice, array            one triple per element, or a rdf:List
+//
+// Fields without a "rdf" tag, or with the tag "-", are ignored.
+func Marshal(v interface{}) ([]Triple, error) {
+	m := marshaler{}
+	rv := reflect.ValueOf(v)
+	var ptr uintptr
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, errors.New("cannot marshal nil")
+		}
+		ptr = rv.Pointer()
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot marshal %v; must be a struct", rv.Type())
+	}
+	if _, err := m.marshalStruct(rv, ptr); err != nil {
+		return nil, err
+	}
+	return m.triples, nil
+}
+
+// Unmarshal fills the struct pointed to by v with the objects of the triples
+// describing the given subject, using the same "rdf" tags and conversions as
+// Marshal. Objects which are subjects of other triples are unmarshaled into
+// struct fields recursively.
+//
+// If a non-slice field has several matching objects, the first one is used,
+// unless the field has a lang option; then the first literal with a matching
+// language tag is preferred.
+func Unmarshal(triples []Triple, subject Subject, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot unmarshal into %T; must be a pointer to a struct", v)
+	}
+	u := unmarshaler{
+		props: make(map[termKey]map[IRI][]Object),
+		ptrs:  make(map[ptrKey]reflect.Value),
+		busy:  make(map[ptrKey]bool),
+	}
+	for _, t := range triples {
+		p, ok := t.Pred.(IRI)
+		if !ok {
+			continue
+		}
+		k := keyOf(t.Subj)
+		if u.props[k] == nil {
+			u.props[k] = make(map[IRI][]Object)
+		}
+		u.props[k][p] = append(u.props[k][p], t.Obj)
+	}
+	u.ptrs[ptrKey{keyOf(subject), rv.Elem().Type()}] = rv
+	return u.unmarshalStruct(subject, rv.Elem())
+}
+
+// fieldTag is a parsed "rdf" struct tag.
+type fieldTag struct {
+	pred      string
+	lang      string
+	iri       bool
+	list      bool
+	omitEmpty bool
+}
+
+// parseTag parses a struct tag. It returns false if the field should be ignored.
+func parseTag(f reflect.StructField) (fieldTag, bool, error) {
+	tag, ok := f.Tag.Lookup("rdf")
+	if !ok || tag == "-" || f.PkgPath != "" {
+		return fieldTag{}, false, nil
+	}
+	parts := strings.Split(tag, ",")
+	ft := fieldTag{pred: parts[0]}
+	for _, opt := range parts[1:] {
+		switch {
+		case opt == "iri":
+			ft.iri = true
+		case opt == "list":
+			ft.list = true
+		case opt == "omitempty":
+			ft.omitEmpty = true
+		case strings.HasPrefix(opt, "lang="):
+			lang, err := parseLangTag(opt[5:])
+			if err != nil {
+				return fieldTag{}, false, fmt.Errorf("field %s: %v", f.Name, err)
+			}
+			ft.lang = lang
+		default:
+			return fieldTag{}, false, fmt.Errorf("field %s: unknown tag option: %q", f.Name, opt)
+		}
+	}
+	return ft, true, nil
+}
+
+var (
+	typeTime    = reflect.TypeOf(time.Time{})
+	typeIRI     = reflect.TypeOf(IRI{})
+	typeBlank   = reflect.TypeOf(Blank{})
+	typeLiteral = reflect.TypeOf(Literal{})
+	typeTerm    = reflect.TypeOf((*Term)(nil)).Elem()
+	typeBytes   = reflect.TypeOf([]byte(nil))
+)
+
+// intTypes are the integer types NewLiteral accepts, by kind.
+var intTypes = map[reflect.Kind]reflect.Type{
+	reflect.Int:     reflect.TypeOf(int(0)),
+	reflect.Int8:    reflect.TypeOf(int8(0)),
+	reflect.Int16:   reflect.TypeOf(int16(0)),
+	reflect.Int32:   reflect.TypeOf(int32(0)),
+	reflect.Int64:   reflect.TypeOf(int64(0)),
+	reflect.Uint:    reflect.TypeOf(uint(0)),
+	reflect.Uint8:   reflect.TypeOf(uint8(0)),
+	reflect.Uint16:  reflect.TypeOf(uint16(0)),
+	reflect.Uint32:  reflect.TypeOf(uint32(0)),
+	reflect.Uint64:  reflect.TypeOf(uint64(0)),
+	reflect.Uintptr: reflect.TypeOf(uint64(0)),
+}
+
+type marshaler struct {
+	triples []Triple
+	bnodes  BlankAllocator      // labels the blank nodes of this call to Marshal
+	seen    map[uintptr]Subject // subjects of structs marshaled through pointers
+}
+
+// marshalStruct appends the triples describing the struct, and returns its
+// subject. If the struct is pointed to by a pointer, ptr is its address.
+func (m *marshaler) marshalStruct(rv reflect.Value, ptr uintptr) (Subject, error) {
+	var subj Subject
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		ft, ok, err := parseTag(rt.Field(i))
+		if err != nil {
+			return nil, err
+		}
+		if !ok || ft.pred != "@id" {
+			continue
+		}
+		switch id := rv.Field(i).Interface().(type) {
+		case IRI:
+			if id.str != "" {
+				subj = id
+			}
+		case string:
+			if id != "" {
+				iri, err := NewIRI(id)
+				if err != nil {
+					return nil, fmt.Errorf("field %s: %v", rt.Field(i).Name, err)
+				}
+				subj = iri
+			}
+		default:
+			return nil, fmt.Errorf("field %s: @id must be a string or an IRI", rt.Field(i).Name)
+		}
+	}
+	if subj == nil {
+		subj = m.blank()
+	}
+	if ptr != 0 {
+		// Register the subject before marshaling the fields, in case
+		// they point back to this struct.
+		if m.seen == nil {
+			m.seen = make(map[uintptr]Subject)
+		}
+		m.seen[ptr] = subj
+	}
+
+	for i := 0; i < rt.NumField(); i++ {
+		ft, ok, err := parseTag(rt.Field(i))
+		if err != nil {
+			return nil, err
+		}
+		if !ok || ft.pred == "@id" {
+			continue
+		}
+		fv := rv.Field(i)
+		if ft.omitEmpty && fv.IsZero() {
+			continue
+		}
+		pred := rdfType
+		if ft.pred != "@type" {
+			if pred, err = NewIRI(ft.pred); err != nil {
+				return nil, fmt.Errorf("field %s: %v", rt.Field(i).Name, err)
+			}
+		} else {
+			ft.iri = true
+		}
+		objs, err := m.marshalValue(fv, ft)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", rt.Field(i).Name, err)
+		}
+		for _, o := range objs {
+			m.triples = append(m.triples, Triple{Subj: subj, Pred: pred, Obj: o})
+		}
+	}
+	return subj, nil
+}
+
+// marshalValue returns the objects representing the value.
+func (m *marshaler) marshalValue(rv reflect.Value, ft fieldTag) ([]Object, error) {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		if rv.Kind() == reflect.Ptr && isNodeStruct(rv.Type().Elem()) {
+			subj, ok := m.seen[rv.Pointer()]
+			if !ok {
+				var err error
+				if subj, err = m.marshalStruct(rv.Elem(), rv.Pointer()); err != nil {
+					return nil, err
+				}
+			}
+			return []Object{subj.(Object)}, nil
+		}
+		rv = rv.Elem()
+	}
+	if (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && rv.Type() != typeBytes {
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return nil, nil
+		}
+		elemTag := ft
+		elemTag.list = false
+		var objs []Object
+		for i := 0; i < rv.Len(); i++ {
+			o, err := m.marshalValue(rv.Index(i), elemTag)
+			if err != nil {
+				return nil, err
+			}
+			objs = append(objs, o...)
+		}
+		if !ft.list {
+			return objs, nil
+		}
+		return []Object{m.list(objs)}, nil
+	}
+	o, err := m.marshalSingle(rv, ft)
+	if err != nil {
+		return nil, err
+	}
+	return []Object{o}, nil
+}
+
+// blank returns a new blank node. The nodes are labelled by a
+// NewUUIDAllocator, so that the triples of different calls to Marshal never
+// share blank nodes.
+func (m *marshaler) blank() Blank {
+	if m.bnodes == nil {
+		m.bnodes = NewUUIDAllocator()
+	}
+	return Blank{id: "_:" + m.bnodes.New()}
+}
+
+// list appends the triples of a rdf:List with the given objects, and returns its head.
+func (m *marshaler) list(objs []Object) Object {
+	var head Object = rdfNil
+	for i := len(objs) - 1; i >= 0; i-- {
+		node := m.blank()
+		m.triples = append(m.triples,
+			Triple{Subj: node, Pred: rdfFirst, Obj: objs[i]},
+			Triple{Subj: node, Pred: rdfRest, Obj: head})
+		head = node
+	}
+	return head
+}
+
+// marshalSingle returns the object representing a single (non-slice) value.
+func (m *marshaler) marshalSingle(rv reflect.Value, ft fieldTag) (Object, error) {
+	if o, ok := rv.Interface().(Object); ok {
+		return o, nil
+	}
+	switch rv.Type() {
+	case typeTime:
+		return NewLiteral(rv.Interface().(time.Time))
+	case typeBytes:
+		return NewLiteral(rv.Bytes())
+	}
+	switch rv.Kind() {
+	case reflect.String:
+		switch {
+		case ft.iri:
+			return NewIRI(rv.String())
+		case ft.lang != "":
+			return Literal{str: rv.String(), lang: ft.lang, DataType: rdfLangString}, nil
+		default:
+			return Literal{str: rv.String(), DataType: xsdString}, nil
+		}
+	case reflect.Bool:
+		return NewLiteral(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		// Convert named types to their underlying type, for NewLiteral.
+		return NewLiteral(rv.Convert(intTypes[rv.Kind()]).Interface())
+	case reflect.Float32, reflect.Float64:
+		return NewLiteral(rv.Float())
+	case reflect.Struct:
+		subj, err := m.marshalStruct(rv, 0)
+		if err != nil {
+			return nil, err
+		}
+		return subj.(Object), nil
+	}
+	return nil, fmt.Errorf("cannot marshal %v", rv.Type())
+}
+
+// isNodeStruct checks if t is a struct type which is unmarshaled from the
+// properties of a subject, i.e. any struct except time.Time and the RDF terms.
+func isNodeStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != typeTime && t != typeIRI && t != typeBlank && t != typeLiteral
+}
+
+// ptrKey identifies a pointer to a struct unmarshaled from a subject.
+type ptrKey struct {
+	subj termKey
+	typ  reflect.Type
+}
+
+type unmarshaler struct {
+	props map[termKey]map[IRI][]Object // subject -> predicate -> objects
+	ptrs  map[ptrKey]reflect.Value     // already unmarshaled pointers to structs
+	busy  map[ptrKey]bool              // structs being unmarshaled, to detect cycles
+}
+
+// unmarshalStruct fills the struct with the properties of the subject.
+func (u *unmarshaler) unmarshalStruct(subj Subject, rv reflect.Value) error {
+	key := ptrKey{keyOf(subj), rv.Type()}
+	if u.busy[key] {
+		return fmt.Errorf("cannot unmarshal cyclic graph into %v; use a pointer", rv.Type())
+	}
+	u.busy[key] = true
+	defer delete(u.busy, key)
+
+	rt := rv.Type()
+	props := u.props[keyOf(subj)]
+	for i := 0; i < rt.NumField(); i++ {
+		ft, ok, err := parseTag(rt.Field(i))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		fv := rv.Field(i)
+		var objs []Object
+		switch ft.pred {
+		case "@id":
+			switch fv.Interface().(type) {
+			case IRI, string:
+				if iri, ok := subj.(IRI); ok {
+					if err := u.unmarshalSingle(iri, fv, fieldTag{iri: true}); err != nil {
+						return fmt.Errorf("field %s: %v", rt.Field(i).Name, err)
+					}
+				}
+				continue
+			default:
+				return fmt.Errorf("field %s: @id must be a string or an IRI", rt.Field(i).Name)
+			}
+		case "@type":
+			objs = props[rdfType]
+			ft.iri = true
+		default:
+			pred, err := NewIRI(ft.pred)
+			if err != nil {
+				return fmt.Errorf("field %s: %v", rt.Field(i).Name, err)
+			}
+			objs = props[pred]
+		}
+		if len(objs) == 0 {
+			continue
+		}
+		if err := u.unmarshalField(objs, fv, ft); err != nil {
+			return fmt.Errorf("field %s: %v", rt.Field(i).Name, err)
+		}
+	}
+	return nil
+}
+
+// unmarshalField sets the field to the given objects.
+func (u *unmarshaler) unmarshalField(objs []Object, fv reflect.Value, ft fieldTag) error {
+	isSlice := (fv.Kind() == reflect.Slice || fv.Kind() == reflect.Array) && fv.Type() != typeBytes
+	if !isSlice {
+		return u.unmarshalSingle(u.pick(objs, ft.lang), fv, ft)
+	}
+	if ft.list {
+		head, ok := objs[0].(Subject)
+		if !ok {
+			return fmt.Errorf("rdf:List head must be an IRI or blank node, got %v", objs[0])
+		}
+		var err error
+		if objs, err = u.list(head); err != nil {
+			return err
+		}
+	}
+	if fv.Kind() == reflect.Array {
+		if len(objs) > fv.Len() {
+			return fmt.Errorf("%d values does not fit in %v", len(objs), fv.Type())
+		}
+	} else {
+		fv.Set(reflect.MakeSlice(fv.Type(), len(objs), len(objs)))
+	}
+	for i, o := range objs {
+		if err := u.unmarshalSingle(o, fv.Index(i), ft); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pick returns the first object, or the first literal with the given
+// language tag, if any.
+func (u *unmarshaler) pick(objs []Object, lang string) Object {
+	if lang != "" {
+		for _, o := range objs {
+			if l, ok := o.(Literal); ok && strings.EqualFold(l.lang, lang) {
+				return o
+			}
+		}
+	}
+	return objs[0]
+}
+
+// list returns the members of the rdf:List starting at head.
+func (u *unmarshaler) list(head Subject) ([]Object, error) {
+	var objs []Object
+	seen := make(map[termKey]bool)
+	for head != rdfNil {
+		k := keyOf(head)
+		if seen[k] {
+			return nil, errors.New("cyclic rdf:List")
+		}
+		seen[k] = true
+		first, rest := u.props[k][rdfFirst], u.props[k][rdfRest]
+		if len(first) != 1 || len(rest) != 1 {
+			return nil, fmt.Errorf("malformed rdf:List at %v", head)
+		}
+		objs = append(objs, first[0])
+		next, ok := rest[0].(Subject)
+		if !ok {
+			return nil, fmt.Errorf("malformed rdf:List at %v", head)
+		}
+		head = next
+	}
+	return objs, nil
+}
+
+// unmarshalSingle sets a single (non-slice) value from the object.
+func (u *unmarshaler) unmarshalSingle(o Object, fv reflect.Value, ft fieldTag) error {
+	ot := reflect.TypeOf(o)
+	switch {
+	case fv.Type() == typeTerm || (fv.Kind() == reflect.Interface && ot.Implements(fv.Type())):
+		fv.Set(reflect.ValueOf(o))
+		return nil
+	case fv.Type() == typeIRI, fv.Type() == typeBlank, fv.Type() == typeLiteral:
+		if ot != fv.Type() {
+			return fmt.Errorf("cannot unmarshal %v into %v", o, fv.Type())
+		}
+		fv.Set(reflect.ValueOf(o))
+		return nil
+	case fv.Kind() == reflect.Ptr:
+		if subj, ok := o.(Subject); ok && isNodeStruct(fv.Type().Elem()) {
+			// Share pointers to the same subject, so that cyclic graphs can be unmarshaled.
+			key := ptrKey{keyOf(subj), fv.Type().Elem()}
+			if p, ok := u.ptrs[key]; ok {
+				fv.Set(p)
+				return nil
+			}
+			p := reflect.New(fv.Type().Elem())
+			u.ptrs[key] = p
+			fv.Set(p)
+			return u.unmarshalStruct(subj, p.Elem())
+		}
+		p := reflect.New(fv.Type().Elem())
+		if err := u.unmarshalSingle(o, p.Elem(), ft); err != nil {
+			return err
+		}
+		fv.Set(p)
+		return nil
+	case isNodeStruct(fv.Type()):
+		subj, ok := o.(Subject)
+		if !ok {
+			return fmt.Errorf("cannot unmarshal %v into %v", o, fv.Type())
+		}
+		return u.unmarshalStruct(subj, fv)
+	}
+
+	if fv.Kind() == reflect.String {
+		switch t := o.(type) {
+		case IRI:
+			fv.SetString(t.str)
+			return nil
+		case Literal:
+			if !ft.iri {
+				fv.SetString(t.str)
+				return nil
+			}
+		}
+		return fmt.Errorf("cannot unmarshal %v into %v", o, fv.Type())
+	}
+
+	l, ok := o.(Literal)
+	if !ok {
+		return fmt.Errorf("cannot unmarshal %v into %v", o, fv.Type())
+	}
+	switch fv.Type() {
+	case typeTime:
+		v, err := l.Typed()
+		if t, ok := v.(time.Time); ok && err == nil {
+			fv.Set(reflect.ValueOf(t))
+			return nil
+		}
+		return fmt.Errorf("cannot unmarshal %v into %v", o, fv.Type())
+	case typeBytes:
+		v, err := l.Typed()
+		if b, ok := v.([]byte); ok && err == nil {
+			fv.SetBytes(b)
+			return nil
+		}
+		if l.DataType == xsdString {
+			// Accept base64 encoded strings as well.
+			if b, err := base64.StdEncoding.DecodeString(l.str); err == nil {
+				fv.SetBytes(b)
+				return nil
+			}
+		}
+		return fmt.Errorf("cannot unmarshal %v into %v", o, fv.Type())
+	}
+	switch fv.Kind() {
+	case reflect.Bool:
+		switch l.str {
+		case "true", "1":
+			fv.SetBool(true)
+			return nil
+		case "false", "0":
+			fv.SetBool(false)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, err := strconv.ParseInt(l.str, 10, fv.Type().Bits()); err == nil {
+			fv.SetInt(n)
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if n, err := strconv.ParseUint(strings.TrimPrefix(l.str, "+"), 10, fv.Type().Bits()); err == nil {
+			fv.SetUint(n)
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		if f, ok := parseFloat(l.str, fv.Type().Bits()); ok {
+			fv.SetFloat(f)
+			return nil
+		}
+	default:
+		return fmt.Errorf("cannot unmarshal into %v", fv.Type())
+	}
+	return fmt.Errorf("cannot unmarshal %v into %v", o, fv.Type())
+}
@@ -0,0 +1,247 @@
+package rdf
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testPerson struct {
+	ID    string        `rdf:"@id"`
+	Name  string        `rdf:"http://xmlns.com/foaf/0.1/name"`
+	Knows []*testPerson `rdf:"http://xmlns.com/foaf/0.1/knows,omitempty"`
+}
+
+type testAddress struct {
+	Street string `rdf:"http://schema.org/streetAddress"`
+	Zip    *int   `rdf:"http://schema.org/postalCode"`
+}
+
+type testBook struct {
+	ID       IRI          `rdf:"@id"`
+	Types    []IRI        `rdf:"@type"`
+	Title    string       `rdf:"http://purl.org/dc/terms/title,lang=en"`
+	Pages    uint16       `rdf:"http://schema.org/numberOfPages"`
+	Price    float64      `rdf:"http://schema.org/price,omitempty"`
+	InPrint  bool         `rdf:"http://ex/inPrint"`
+	Issued   time.Time    `rdf:"http://purl.org/dc/terms/issued"`
+	Authors  []testPerson `rdf:"http://purl.org/dc/terms/creator,list"`
+	Subjects []string     `rdf:"http://purl.org/dc/terms/subject,iri"`
+	Address  testAddress  `rdf:"http://schema.org/address"`
+	Extra    Object       `rdf:"http://ex/extra"`
+	Ignored  string
+	Skipped  string `rdf:"-"`
+}
+
+func TestMarshal(t *testing.T) {
+	book := testBook{
+		ID:       IRI{str: "http://ex/book"},
+		Types:    []IRI{{str: "http://schema.org/Book"}},
+		Title:    "Moby Dick",
+		Pages:    635,
+		InPrint:  true,
+		Issued:   time.Date(1851, 10, 18, 0, 0, 0, 0, time.UTC),
+		Authors:  []testPerson{{ID: "http://ex/melville", Name: "Herman Melville"}},
+		Subjects: []string{"http://ex/whales"},
+		Address:  testAddress{Street: "Main St."},
+		Extra:    Blank{id: "_:x"},
+		Ignored:  "x",
+		Skipped:  "y",
+	}
+	triples, err := Marshal(&book)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := IRI{str: "http://ex/book"}
+	// The blank nodes are labelled by a NewUUIDAllocator.
+	list, ok := triples[6].Subj.(Blank)
+	if !ok {
+		t.Fatalf("Marshal => list node %v; want a blank node", triples[6].Subj)
+	}
+	addr, ok := triples[10].Subj.(Blank)
+	if !ok || addr == list {
+		t.Fatalf("Marshal => address node %v; want a new blank node", triples[10].Subj)
+	}
+	want := []Triple{
+		{Subj: s, Pred: rdfType, Obj: IRI{str: "http://schema.org/Book"}},
+		{Subj: s, Pred: IRI{str: "http://purl.org/dc/terms/title"}, Obj: Literal{str: "Moby Dick", lang: "en", DataType: rdfLangString}},
+		{Subj: s, Pred: IRI{str: "http://schema.org/numberOfPages"}, Obj: NewTypedLiteral("635", xsdUnsignedShort)},
+		{Subj: s, Pred: IRI{str: "http://ex/inPrint"}, Obj: NewTypedLiteral("true", xsdBoolean)},
+		{Subj: s, Pred: IRI{str: "http://purl.org/dc/terms/issued"}, Obj: NewTypedLiteral("1851-10-18T00:00:00Z", xsdDateTime)},
+		{Subj: IRI{str: "http://ex/melville"}, Pred: IRI{str: "http://xmlns.com/foaf/0.1/name"}, Obj: NewTypedLiteral("Herman Melville", xsdString)},
+		{Subj: list, Pred: rdfFirst, Obj: IRI{str: "http://ex/melville"}},
+		{Subj: list, Pred: rdfRest, Obj: rdfNil},
+		{Subj: s, Pred: IRI{str: "http://purl.org/dc/terms/creator"}, Obj: list},
+		{Subj: s, Pred: IRI{str: "http://purl.org/dc/terms/subject"}, Obj: IRI{str: "http://ex/whales"}},
+		{Subj: addr, Pred: IRI{str: "http://schema.org/streetAddress"}, Obj: NewTypedLiteral("Main St.", xsdString)},
+		{Subj: s, Pred: IRI{str: "http://schema.org/address"}, Obj: addr},
+		{Subj: s, Pred: IRI{str: "http://ex/extra"}, Obj: Blank{id: "_:x"}},
+	}
+	if len(triples) != len(want) {
+		t.Fatalf("Marshal => %d triples; want %d:\n%v", len(triples), len(want), triples)
+	}
+	for i := range want {
+		if !TriplesEqual(triples[i], want[i]) {
+			t.Errorf("Marshal => triple %d: %v; want %v", i, triples[i].Serialize(NTriples), want[i].Serialize(NTriples))
+		}
+	}
+}
+
+func TestMarshalBlankNodes(t *testing.T) {
+	// Blank nodes from different calls to Marshal are distinct.
+	g := NewGraph()
+	for _, street := range []string{"Main St.", "High St."} {
+		triples, err := Marshal(testAddress{Street: street})
+		if err != nil {
+			t.Fatal(err)
+		}
+		g.Add(triples...)
+	}
+	if subjs := g.Subjects(); len(subjs) != 2 || g.Len() != 2 {
+		t.Errorf("Graph of two marshaled structs => %d triples about %v; want 2 subjects", g.Len(), subjs)
+	}
+}
+
+func TestMarshalUnmarshal(t *testing.T) {
+	zip := 1234
+	book := testBook{
+		ID:       IRI{str: "http://ex/book"},
+		Types:    []IRI{{str: "http://schema.org/Book"}, {str: "http://ex/Novel"}},
+		Title:    "Moby Dick",
+		Pages:    635,
+		Price:    9.5,
+		InPrint:  true,
+		Issued:   time.Date(1851, 10, 18, 0, 0, 0, 0, time.UTC),
+		Authors:  []testPerson{{ID: "http://ex/a", Name: "A"}, {Name: "B"}},
+		Subjects: []string{"http://ex/whales", "http://ex/sea"},
+		Address:  testAddress{Street: "Main St.", Zip: &zip},
+		Extra:    NewTypedLiteral("x", xsdString),
+	}
+	triples, err := Marshal(book)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got testBook
+	if err := Unmarshal(triples, book.ID, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, book) {
+		t.Errorf("Unmarshal(Marshal(%+v)) =>\n%+v", book, got)
+	}
+}
+
+func TestMarshalCycle(t *testing.T) {
+	a := &testPerson{ID: "http://ex/a", Name: "A"}
+	b := &testPerson{ID: "http://ex/b", Name: "B", Knows: []*testPerson{a}}
+	a.Knows = []*testPerson{b}
+
+	triples, err := Marshal(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(triples) != 4 {
+		t.Fatalf("Marshal => %d triples; want 4:\n%v", len(triples), triples)
+	}
+
+	var got testPerson
+	if err := Unmarshal(triples, IRI{str: "http://ex/a"}, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Knows) != 1 || got.Knows[0].Name != "B" || len(got.Knows[0].Knows) != 1 || got.Knows[0].Knows[0].Name != "A" {
+		t.Errorf("Unmarshal of cyclic graph => %+v", got)
+	}
+}
+
+func TestUnmarshalLang(t *testing.T) {
+	type doc struct {
+		Title string `rdf:"http://ex/title,lang=nb"`
+	}
+	s := IRI{str: "http://ex/s"}
+	p := IRI{str: "http://ex/title"}
+	triples := []Triple{
+		{Subj: s, Pred: p, Obj: Literal{str: "Title", lang: "en", DataType: rdfLangString}},
+		{Subj: s, Pred: p, Obj: Literal{str: "Tittel", lang: "nb", DataType: rdfLangString}},
+	}
+	var d doc
+	if err := Unmarshal(triples, s, &d); err != nil {
+		t.Fatal(err)
+	}
+	if d.Title != "Tittel" {
+		t.Errorf("Unmarshal with lang=nb => %q; want %q", d.Title, "Tittel")
+	}
+}
+
+func TestMarshalIntTypes(t *testing.T) {
+	type count int32
+	v := struct {
+		A int     `rdf:"http://ex/a"`
+		B count   `rdf:"http://ex/b"`
+		C uintptr `rdf:"http://ex/c"`
+	}{1, 2, 3}
+	triples, err := Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []IRI{xsdInteger, xsdInt, xsdUnsignedLong}
+	if len(triples) != len(want) {
+		t.Fatalf("Marshal => %d triples; want %d", len(triples), len(want))
+	}
+	for i, tr := range triples {
+		if dt := tr.Obj.(Literal).DataType; dt != want[i] {
+			t.Errorf("Marshal => %v; want datatype %v", tr, want[i])
+		}
+	}
+}
+
+func TestUnmarshalQuotedSubject(t *testing.T) {
+	type claim struct {
+		Source string `rdf:"http://ex/source,iri"`
+	}
+	b, err := NewLiteral([]byte("x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := QuotedTriple{Triple{Subj: IRI{str: "http://ex/s"}, Pred: IRI{str: "http://ex/p"}, Obj: b}}
+	triples := []Triple{{Subj: s, Pred: IRI{str: "http://ex/source"}, Obj: IRI{str: "http://ex/src"}}}
+	var c claim
+	if err := Unmarshal(triples, s, &c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Source != "http://ex/src" {
+		t.Errorf("Unmarshal with quoted subject => %q; want %q", c.Source, "http://ex/src")
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	tests := []struct {
+		v    interface{}
+		want string
+	}{
+		{"a", "cannot marshal string; must be a struct"},
+		{struct {
+			A int `rdf:"http://ex/a,foo"`
+		}{}, `field A: unknown tag option: "foo"`},
+		{struct {
+			A map[string]int `rdf:"http://ex/a"`
+		}{map[string]int{}}, "field A: cannot marshal map[string]int"},
+		{struct {
+			A string `rdf:"not an iri"`
+		}{}, "field A: disallowed character: ' '"},
+	}
+	for _, tt := range tests {
+		_, err := Marshal(tt.v)
+		if err == nil || !strings.HasSuffix(err.Error(), tt.want) {
+			t.Errorf("Marshal(%#v) => %v; want %v", tt.v, err, tt.want)
+		}
+	}
+
+	var n struct {
+		N int8 `rdf:"http://ex/n"`
+	}
+	s := IRI{str: "http://ex/s"}
+	err := Unmarshal([]Triple{{Subj: s, Pred: IRI{str: "http://ex/n"}, Obj: NewTypedLiteral("300", xsdInteger)}}, s, &n)
+	if err == nil || !strings.HasSuffix(err.Error(), "cannot unmarshal 300 into int8") {
+		t.Errorf("Unmarshal of out of range integer => %v; want error", err)
+	}
+}
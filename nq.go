@@ -17,55 +17,24 @@ func (d *QuadDecoder) parseNQ() (q Quad, err error) {
 	q.Ctx = d.DefaultGraph
 
 	// parse quad subject
-	tok := d.expectAs("subject", tokenIRIAbs, tokenBNode)
-	if tok.typ == tokenIRIAbs {
-		q.Subj = IRI{str: tok.text}
-	} else {
-		q.Subj = Blank{id: tok.text}
-	}
+	q.Subj = d.parseSubject()
 
 	// parse quad predicate
-	tok = d.expect1As("predicate", tokenIRIAbs)
-	q.Pred = IRI{str: tok.text}
+	tok := d.expect1As("predicate", tokenIRIAbs)
+	q.Pred = IRI{str: string(tok.text)}
 
 	// parse quad object
-	tok = d.expectAs("object", tokenIRIAbs, tokenBNode, tokenLiteral)
-
-	switch tok.typ {
-	case tokenBNode:
-		q.Obj = Blank{id: tok.text}
-	case tokenLiteral:
-		val := tok.text
-		l := Literal{
-			str:      val,
-			DataType: xsdString,
-		}
-		p := d.peek()
-		switch p.typ {
-		case tokenLangMarker:
-			d.next() // consume peeked token
-			tok = d.expect1As("literal language", tokenLang)
-			l.lang = tok.text
-			l.DataType = rdfLangString
-		case tokenDataTypeMarker:
-			d.next() // consume peeked token
-			tok = d.expect1As("literal datatype", tokenIRIAbs)
-			l.DataType = IRI{str: tok.text}
-		}
-		q.Obj = l
-	case tokenIRIAbs:
-		q.Obj = IRI{str: tok.text}
-	}
+	q.Obj = d.parseObject()
 
 	// parse optional graph
 	p := d.peek()
 	switch p.typ {
 	case tokenIRIAbs:
 		tok = d.next() // consume peeked token
-		q.Ctx = IRI{str: tok.text}
+		q.Ctx = IRI{str: string(tok.text)}
 	case tokenBNode:
 		tok = d.next() // consume peeked token
-		q.Ctx = Blank{id: tok.text}
+		q.Ctx = d.blank(string(tok.text))
 	case tokenDot:
 		break
 	default:
@@ -78,9 +47,68 @@ func (d *QuadDecoder) parseNQ() (q Quad, err error) {
 	// check for extra tokens, assert we reached end of line
 	d.expect1As("end of line", tokenEOL)
 
-	if d.peek().typ == tokenEOF {
-		// drain lexer
-		d.next()
+	if d.dict != nil {
+		q.Triple = d.dict.internIRIs(q.Triple)
+		if u, ok := q.Ctx.(IRI); ok {
+			q.Ctx = d.dict.Intern(u).(IRI)
+		}
 	}
 	return q, err
 }
+
+// parseSubject parses a subject; an IRI, blank node or quoted triple.
+func (d *QuadDecoder) parseSubject() Subject {
+	tok := d.expectAs("subject", tokenIRIAbs, tokenBNode, tokenQuotedStart)
+	switch tok.typ {
+	case tokenIRIAbs:
+		return IRI{str: string(tok.text)}
+	case tokenBNode:
+		return d.blank(string(tok.text))
+	default:
+		return d.parseQuoted()
+	}
+}
+
+// parseObject parses an object; an IRI, blank node, literal or quoted triple.
+func (d *QuadDecoder) parseObject() Object {
+	tok := d.expectAs("object", tokenIRIAbs, tokenBNode, tokenLiteral, tokenQuotedStart)
+
+	switch tok.typ {
+	case tokenBNode:
+		return d.blank(string(tok.text))
+	case tokenQuotedStart:
+		return d.parseQuoted()
+	case tokenLiteral:
+		l := Literal{
+			str:      string(tok.text),
+			DataType: xsdString,
+		}
+		p := d.peek()
+		switch p.typ {
+		case tokenLangMarker:
+			d.next() // consume peeked token
+			tok = d.expect1As("literal language", tokenLang)
+			l.lang = d.langTag(tok)
+			l.DataType = rdfLangString
+		case tokenDataTypeMarker:
+			d.next() // consume peeked token
+			tok = d.expect1As("literal datatype", tokenIRIAbs)
+			l.DataType = IRI{str: string(tok.text)}
+		}
+		return l
+	default:
+		return IRI{str: string(tok.text)}
+	}
+}
+
+// parseQuoted parses a quoted triple, after its opening '<<'. Quoted triples
+// are only lexed when parsing statements with UnmarshalText.
+func (d *QuadDecoder) parseQuoted() QuotedTriple {
+	var q QuotedTriple
+	q.Subj = d.parseSubject()
+	tok := d.expect1As("predicate", tokenIRIAbs)
+	q.Pred = IRI{str: string(tok.text)}
+	q.Obj = d.parseObject()
+	d.expect1As("end of quoted triple (>>)", tokenQuotedEnd)
+	return q
+}
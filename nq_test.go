@@ -11,10 +11,31 @@ import (
 var defaultGraph = Blank{id: "_:defaultGraph"}
 
 func BenchmarkDecodeNQ(b *testing.B) {
-	input := "#comment\n<http://example/s> <http://example/p> \"123\"^^<http://www.w3.org/2001/XMLSchema#integer> <http://example/g>"
+	input := `#comment
+<http://example/s> <http://example/p> "123"^^<http://www.w3.org/2001/XMLSchema#integer> <http://example/g> .
+<http://example.org/resource1> <http://example.org/property> <http://example.org/resource2> <http://example/g> .
+_:anon <http://example.org/property> <http://example.org/resource2> _:g . #comment
+<http://example.org/resource2> <http://example.org/property> _:anon .
+<http://example.org/resource3> <http://example.org/property> <http://example.org/resource2> <http://example/g> .
+<http://example.org/resource7> <http://example.org/property> "simple literal" <http://example/g> .
+<http://example.org/resource8> <http://example.org/property> "backslash:\\" <http://example/g> .
+<http://example.org/resource9> <http://example.org/property> "dquote:\"" <http://example/g> .
+<http://example.org/resource10> <http://example.org/property> "newline:\n" .
+<http://example.org/resource16> <http://example.org/property> "é" <http://example/g> .
+<http://example.org/resource17> <http://example.org/property> "\u20AC" <http://example/g> .
+<http://example.org/resource24> <http://example.org/property> "<a></a>"^^<http://www.w3.org/2000/01/rdf-schema#XMLLiteral> <http://example/g> .
+<http://example.org/resource30> <http://example.org/property> "chat"@fr <http://example/g> .
+<http://example.org/resource31> <http://example.org/property> "chat"@en _:g .
+<http://example.org/resource32> <http://example.org/property> "abc"^^<http://example.org/datatype1> <http://example/g> .
+`
+	b.ReportAllocs()
+	b.ResetTimer()
 	for n := 0; n < b.N; n++ {
 		dec := NewQuadDecoder(bytes.NewBufferString(input), NQuads)
 		for _, err := dec.Decode(); err != io.EOF; _, err = dec.Decode() {
+			if err != nil {
+				b.Fatal(err)
+			}
 		}
 	}
 	b.SetBytes(int64(len(input)))
@@ -473,7 +494,7 @@ var nqTestSuite = []struct {
 			Triple{
 				Subj: IRI{str: "http://example/s"},
 				Pred: IRI{str: "http://example/p"},
-				Obj:  Literal{str: "string", DataType: rdfLangString, lang: "en-uk"},
+				Obj:  Literal{str: "string", DataType: rdfLangString, lang: "en-UK"},
 			},
 			defaultGraph,
 		},
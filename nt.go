@@ -1,25 +1,53 @@
 package rdf
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"iter"
 	"runtime"
 )
 
 // ntDecoder is a N-Triples parser.
 type ntDecoder struct {
-	l         *lexer   // Turtle lexer (N-Triples is a subset of Turtle)
-	tokens    [2]token // 2 token lookahead
-	peekCount int      // Number of tokens peeked at (position in tokens lookahead array)
+	l         *lexer         // Turtle lexer (N-Triples is a subset of Turtle)
+	bnodes    BlankAllocator // blank node label allocator, if any
+	dict      *Dictionary    // dictionary to intern IRIs in, if any
+	errs      errSink        // handling of malformed input
+	tokens    [2]token       // 2 token lookahead
+	peekCount int            // Number of tokens peeked at (position in tokens lookahead array)
+	last      token          // the token consumed last
 }
 
 // newNTDecoder returns a new N-Triples parser on the given io.Reader.
-func newNTDecoder(r io.Reader) *ntDecoder {
-	return &ntDecoder{l: newLineLexer(r)}
+func newNTDecoder(ctx context.Context, r io.Reader) *ntDecoder {
+	d := &ntDecoder{l: newLineLexer(ctx, r)}
+	d.l.lim.format = NTriples
+	return d
 }
 
 // Decode parses a N-Triples document and returns the next valid Triple or an error.
-func (d *ntDecoder) Decode() (t Triple, err error) {
+func (d *ntDecoder) Decode() (Triple, error) {
+	for {
+		if err := d.l.err(); err != nil {
+			return Triple{}, err
+		}
+		t, err := d.decode()
+		if err == nil {
+			if err = d.l.lim.countTriple(); err != nil {
+				return Triple{}, err
+			}
+			return t, nil
+		}
+		if !d.errs.skip(err) {
+			return t, err
+		}
+		d.skipLine()
+	}
+}
+
+// decode parses the next line with a triple.
+func (d *ntDecoder) decode() (t Triple, err error) {
 	defer d.recover(&err)
 
 again:
@@ -32,27 +60,49 @@ again:
 	}
 
 	// parse triple subject
-	tok := d.expectAs("subject", tokenIRIAbs, tokenBNode)
-	if tok.typ == tokenIRIAbs {
-		t.Subj = IRI{str: tok.text}
-	} else {
-		t.Subj = Blank{id: tok.text}
-	}
+	t.Subj = d.parseSubject()
 
 	// parse triple predicate
-	tok = d.expect1As("predicate", tokenIRIAbs)
-	t.Pred = IRI{str: tok.text}
+	tok := d.expect1As("predicate", tokenIRIAbs)
+	t.Pred = IRI{str: string(tok.text)}
 
 	// parse triple object
-	tok = d.expectAs("object", tokenIRIAbs, tokenBNode, tokenLiteral)
+	t.Obj = d.parseObject()
+
+	// parse final dot
+	d.expect1As("dot (.)", tokenDot)
+
+	// check for extra tokens, assert we reached end of line
+	d.expect1As("end of line", tokenEOL)
+
+	return d.dict.internIRIs(t), err
+}
+
+// parseSubject parses a subject; an IRI, blank node or quoted triple.
+func (d *ntDecoder) parseSubject() Subject {
+	tok := d.expectAs("subject", tokenIRIAbs, tokenBNode, tokenQuotedStart)
+	switch tok.typ {
+	case tokenIRIAbs:
+		return IRI{str: string(tok.text)}
+	case tokenBNode:
+		return d.blank(string(tok.text))
+	default:
+		return d.parseQuoted()
+	}
+}
+
+// parseObject parses an object; an IRI, blank node, literal or quoted triple.
+func (d *ntDecoder) parseObject() Object {
+	tok := d.expectAs("object", tokenIRIAbs, tokenBNode, tokenLiteral, tokenQuotedStart)
 
 	switch tok.typ {
 	case tokenBNode:
-		t.Obj = Blank{id: tok.text}
+		return d.blank(string(tok.text))
+	case tokenQuotedStart:
+		return d.parseQuoted()
 	case tokenLiteral:
-		val := tok.text
 		l := Literal{
-			str:      val,
+			str:      string(tok.text),
 			DataType: xsdString,
 		}
 		p := d.peek()
@@ -60,30 +110,29 @@ again:
 		case tokenLangMarker:
 			d.next() // consume peeked token
 			tok = d.expect1As("literal language", tokenLang)
-			l.lang = tok.text
+			l.lang = d.langTag(tok)
 			l.DataType = rdfLangString
 		case tokenDataTypeMarker:
 			d.next() // consume peeked token
 			tok = d.expect1As("literal datatype", tokenIRIAbs)
-			l.DataType = IRI{str: tok.text}
+			l.DataType = IRI{str: string(tok.text)}
 		}
-		t.Obj = l
-	case tokenIRIAbs:
-		t.Obj = IRI{str: tok.text}
-	}
-
-	// parse final dot
-	d.expect1As("dot (.)", tokenDot)
-
-	// check for extra tokens, assert we reached end of line
-	d.expect1As("end of line", tokenEOL)
-
-	if d.peek().typ == tokenEOF {
-		// drain lexer of final EOF token
-		d.next()
+		return l
+	default:
+		return IRI{str: string(tok.text)}
 	}
+}
 
-	return t, err
+// parseQuoted parses a quoted triple, after its opening '<<'. Quoted triples
+// are only lexed when parsing terms and statements with UnmarshalText.
+func (d *ntDecoder) parseQuoted() QuotedTriple {
+	var q QuotedTriple
+	q.Subj = d.parseSubject()
+	tok := d.expect1As("predicate", tokenIRIAbs)
+	q.Pred = IRI{str: string(tok.text)}
+	q.Obj = d.parseObject()
+	d.expect1As("end of quoted triple (>>)", tokenQuotedEnd)
+	return q
 }
 
 // DecodeAll parses a compete N-Triples document and returns the valid triples,
@@ -99,12 +148,72 @@ func (d *ntDecoder) DecodeAll() ([]Triple, error) {
 	return ts, nil
 }
 
+// All returns an iterator over the remaining triples of the document.
+func (d *ntDecoder) All() iter.Seq2[Triple, error] {
+	return decodeSeq(d.Decode)
+}
+
+// Prefixes returns nil, as N-Triples has no prefix declarations.
+func (d *ntDecoder) Prefixes() map[string]string { return nil }
+
+// Base returns the empty IRI, as N-Triples has no base IRI.
+func (d *ntDecoder) Base() IRI { return IRI{} }
+
+// Close stops the decoder.
+func (d *ntDecoder) Close() error {
+	d.l.stop()
+	return nil
+}
+
 // SetOption sets a ParseOption to the give value
 func (d *ntDecoder) SetOption(o ParseOption, v interface{}) error {
 	switch o {
+	case BlankNodes:
+		a, ok := v.(BlankAllocator)
+		if !ok {
+			return fmt.Errorf("ParseOption \"BlankNodes\" must be a BlankAllocator.")
+		}
+		d.bnodes = a
+	case Intern:
+		dict, ok := v.(*Dictionary)
+		if !ok {
+			return fmt.Errorf("ParseOption \"Intern\" must be a *Dictionary.")
+		}
+		d.dict = dict
+	case Strict, ErrOut:
+		return d.errs.setOption(o, v)
+	case MaxLiteralLen, MaxIRILen, MaxLineLen, MaxTriples:
+		return d.l.lim.setOption(o, v)
 	default:
 		return fmt.Errorf("N-Triples decoder doesn't support option: %v", o)
 	}
+	return nil
+}
+
+// blank returns the blank node with the given label (including the '_:' prefix),
+// scoped to the document if a BlankAllocator is set.
+func (d *ntDecoder) blank(label string) Blank {
+	if d.bnodes == nil {
+		return Blank{id: label}
+	}
+	return Blank{id: "_:" + d.bnodes.Scope(label[2:])}
+}
+
+// langTag returns the language tag of the token in canonical case.
+func (d *ntDecoder) langTag(tok token) string {
+	lang, err := parseLangTag(string(tok.text))
+	if err != nil {
+		e := parseErrorAt(NTriples, tok, InvalidTerm, "bad literal: %v", err)
+		e.Err = err
+		panic(e)
+	}
+	return lang
+}
+
+// skipLine skips the remaining tokens on the line of the malformed triple.
+func (d *ntDecoder) skipLine() {
+	for tok := d.last; !endsLine(tok); tok = d.next() {
+	}
 }
 
 // Parsing functions:
@@ -116,8 +225,8 @@ func (d *ntDecoder) next() token {
 	} else {
 		d.tokens[0] = d.l.nextToken()
 	}
-
-	return d.tokens[d.peekCount]
+	d.last = d.tokens[d.peekCount]
+	return d.last
 }
 
 // peek returns but does not consume the next token.
@@ -145,15 +254,14 @@ func (d *ntDecoder) recover(errp *error) {
 	return
 }
 
-// errorf formats the error and terminates parsing.
-func (d *ntDecoder) errorf(format string, args ...interface{}) {
-	format = fmt.Sprintf("%s", format)
-	panic(fmt.Errorf(format, args...))
+// errorf formats the error, located at the given token, and terminates parsing.
+func (d *ntDecoder) errorf(tok token, kind ErrorKind, format string, args ...interface{}) {
+	panic(parseErrorAt(NTriples, tok, kind, format, args...))
 }
 
 // unexpected complains about the given token and terminates parsing.
 func (d *ntDecoder) unexpected(t token, context string) {
-	d.errorf("%d:%d unexpected %v as %s", t.line, t.col, t.typ, context)
+	d.errorf(t, UnexpectedToken, "unexpected %v as %s", t.typ, context)
 }
 
 // expect1As consumes the next token and guarantees that it has the expected type.
@@ -161,7 +269,7 @@ func (d *ntDecoder) expect1As(context string, expected tokenType) token {
 	t := d.next()
 	if t.typ != expected {
 		if t.typ == tokenError {
-			d.errorf("%d:%d: syntax error: %s", t.line, t.col, t.text)
+			d.errorf(t, SyntaxError, "syntax error: %s", string(t.text))
 		} else {
 			d.unexpected(t, context)
 		}
@@ -178,7 +286,7 @@ func (d *ntDecoder) expectAs(context string, expected ...tokenType) token {
 		}
 	}
 	if t.typ == tokenError {
-		d.errorf("syntax error: %s", t.text)
+		d.errorf(t, SyntaxError, "syntax error: %s", string(t.text))
 	} else {
 		d.unexpected(t, context)
 	}
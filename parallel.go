@@ -0,0 +1,393 @@
+package rdf
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"runtime"
+	"sync"
+)
+
+// parallelChunkSize is the approximate size of the chunks of input the
+// ParallelDecoder hands to its workers.
+const parallelChunkSize = 1 << 20
+
+// ParallelDecoder parses N-Triples or N-Quads documents on multiple
+// goroutines. Since both formats have one statement per line, the input can
+// be split into chunks of whole lines, which are parsed independently by a
+// pool of workers.
+//
+// The quads are returned in the order of the document, unless the Ordered
+// option is set to false; then they are returned as soon as their chunk is
+// parsed, for maximum throughput. The triples of an N-Triples document are
+// returned as quads in the DefaultGraph. Compressed documents are
+// decompressed, like by the other decoders, before they are split.
+//
+// Errors are reported with the line numbers and offsets of the whole
+// document. When the decoder is not in strict mode, the errors are passed
+// to ErrOut from the goroutine calling Decode, so ErrOut need not be safe
+// for concurrent use. A BlankAllocator given with the BlankNodes option is
+// only called by one worker at a time.
+type ParallelDecoder struct {
+	r         io.Reader
+	format    Format
+	workers   int
+	chunkSize int
+	ordered   bool
+	bnodes    BlankAllocator // blank node label allocator, if any
+	dict      *Dictionary    // dictionary to intern IRIs in, if any
+	errs      errSink        // handling of malformed input
+	lim       limits         // resource limits
+	chunkLim  limits         // resource limits of the decoders of the chunks
+
+	DefaultGraph Context // default graph
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	started bool
+	slots   chan struct{} // limits the number of chunks in flight
+	work    chan chunk    // chunks to be parsed
+	results chan *batch   // parsed chunks
+	pending map[int]*batch
+	next    int    // sequence number of the next batch, when ordered
+	cur     *batch // the batch quads are returned from
+	i       int    // index of the next quad in cur
+	err     error  // error returned from all further calls to Decode
+}
+
+// chunk is a part of the input, made of whole lines.
+type chunk struct {
+	seq  int    // sequence number of the chunk in the input
+	data []byte // the lines
+	line int    // line number of the first line
+	off  int64  // byte offset of the first line
+	err  error  // error reading the input after data, if any
+}
+
+// batch holds the result of parsing a chunk.
+type batch struct {
+	seq   int
+	quads []Quad
+	errs  []error // errors skipped when not in strict mode
+	err   error   // error which ended the parse of the chunk, if any
+}
+
+// NewParallelDecoder returns a new ParallelDecoder capable of parsing
+// the given io.Reader in the given serialization format, which must be
+// N-Triples or N-Quads, using the given number of worker goroutines. If
+// workers is not positive, GOMAXPROCS workers are used.
+func NewParallelDecoder(r io.Reader, f Format, workers int) *ParallelDecoder {
+	return NewParallelDecoderContext(context.Background(), r, f, workers)
+}
+
+// NewParallelDecoderContext is like NewParallelDecoder, but the decoder
+// stops when the given context is done; Decode then returns the context's
+// error.
+func NewParallelDecoderContext(ctx context.Context, r io.Reader, f Format, workers int) *ParallelDecoder {
+	if f != NTriples && f != NQuads {
+		panic(fmt.Errorf("Parallel decoder for serialization format %v not implemented", f))
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	d := &ParallelDecoder{
+		r:            decompress(r),
+		format:       f,
+		workers:      workers,
+		chunkSize:    parallelChunkSize,
+		ordered:      true,
+		DefaultGraph: Blank{id: "_:defaultGraph"},
+		pending:      make(map[int]*batch),
+		lim:          limits{format: f},
+	}
+	d.ctx, d.cancel = context.WithCancel(ctx)
+	return d
+}
+
+// SetOption sets a ParseOption to the give value. It must be called before
+// the first call to Decode.
+func (d *ParallelDecoder) SetOption(o ParseOption, v interface{}) error {
+	switch o {
+	case BlankNodes:
+		a, ok := v.(BlankAllocator)
+		if !ok {
+			return fmt.Errorf("ParseOption \"BlankNodes\" must be a BlankAllocator.")
+		}
+		d.bnodes = &lockedAllocator{a: a}
+	case Intern:
+		dict, ok := v.(*Dictionary)
+		if !ok {
+			return fmt.Errorf("ParseOption \"Intern\" must be a *Dictionary.")
+		}
+		d.dict = dict
+	case Strict, ErrOut:
+		return d.errs.setOption(o, v)
+	case Ordered:
+		ordered, ok := v.(bool)
+		if !ok {
+			return fmt.Errorf("ParseOption \"Ordered\" must be a bool.")
+		}
+		d.ordered = ordered
+	case MaxLiteralLen, MaxIRILen, MaxLineLen, MaxTriples:
+		return d.lim.setOption(o, v)
+	default:
+		return fmt.Errorf("Parallel decoder doesn't support option: %v", o)
+	}
+	return nil
+}
+
+// Decode returns the next valid Quad, or an error. It returns io.EOF when
+// the whole document is parsed.
+func (d *ParallelDecoder) Decode() (Quad, error) {
+	if !d.started {
+		d.start()
+	}
+	for d.err == nil {
+		if d.cur != nil && d.i < len(d.cur.quads) {
+			if err := d.lim.countTriple(); err != nil {
+				d.stop(err)
+				break
+			}
+			d.i++
+			return d.cur.quads[d.i-1], nil
+		}
+		if d.cur != nil {
+			if d.cur.err != nil {
+				d.stop(d.cur.err)
+				break
+			}
+			d.cur = nil
+			<-d.slots
+		}
+		b, err := d.nextBatch()
+		if err != nil {
+			d.stop(err)
+			break
+		}
+		d.cur, d.i = b, 0
+		if d.errs.report != nil {
+			for _, err := range b.errs {
+				d.errs.report(err)
+			}
+		}
+	}
+	return Quad{}, d.err
+}
+
+// DecodeAll decodes and returns all Quads from source, or an error.
+func (d *ParallelDecoder) DecodeAll() ([]Quad, error) {
+	var qs []Quad
+	for q, err := d.Decode(); err != io.EOF; q, err = d.Decode() {
+		if err != nil {
+			return nil, err
+		}
+		qs = append(qs, q)
+	}
+	return qs, nil
+}
+
+// All returns an iterator over the remaining Quads from source. It stops
+// at the end of the source, or after yielding an error.
+func (d *ParallelDecoder) All() iter.Seq2[Quad, error] {
+	return decodeSeq(d.Decode)
+}
+
+// Close stops the decoder and its goroutines. Decode returns
+// ErrDecoderClosed after Close. It does not close the io.Reader; a
+// goroutine blocked reading from it terminates when the read returns.
+func (d *ParallelDecoder) Close() error {
+	d.stop(ErrDecoderClosed)
+	return nil
+}
+
+// stop makes Decode return err from now on, and stops the goroutines.
+func (d *ParallelDecoder) stop(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+	d.cancel()
+}
+
+// nextBatch returns the next parsed chunk; the next one in the document
+// when ordered, or else the first one ready.
+func (d *ParallelDecoder) nextBatch() (*batch, error) {
+	for {
+		if d.ordered {
+			if b, ok := d.pending[d.next]; ok {
+				delete(d.pending, d.next)
+				d.next++
+				return b, nil
+			}
+		}
+		select {
+		case b, ok := <-d.results:
+			if !ok {
+				return nil, io.EOF
+			}
+			if !d.ordered {
+				return b, nil
+			}
+			d.pending[b.seq] = b
+		case <-d.ctx.Done():
+			return nil, context.Cause(d.ctx)
+		}
+	}
+}
+
+// start starts the goroutines splitting the input and parsing the chunks.
+func (d *ParallelDecoder) start() {
+	d.started = true
+	d.chunkLim = d.chunkLimits()
+	d.slots = make(chan struct{}, 2*d.workers)
+	d.work = make(chan chunk)
+	d.results = make(chan *batch, 2*d.workers)
+
+	go d.split()
+	var wg sync.WaitGroup
+	for i := 0; i < d.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range d.work {
+				select {
+				case d.results <- d.parse(c):
+				case <-d.ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(d.results)
+	}()
+}
+
+// split splits the input into chunks of whole lines, and sends them to the
+// workers. The number of chunks in flight is limited by slots, which are
+// released by Decode as it is done with the chunks.
+func (d *ParallelDecoder) split() {
+	defer close(d.work)
+	rdr := bufio.NewReader(d.r)
+	line, off := 1, int64(0)
+	for seq := 0; ; seq++ {
+		select {
+		case d.slots <- struct{}{}:
+		case <-d.ctx.Done():
+			return
+		}
+		data := make([]byte, d.chunkSize)
+		n, err := io.ReadFull(rdr, data)
+		data = data[:n]
+		if err == nil {
+			data, err = d.readLineEnd(rdr, data)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			err = io.EOF
+		}
+		c := chunk{seq: seq, data: data, line: line, off: off}
+		if err != io.EOF {
+			c.err = err
+		}
+		select {
+		case d.work <- c:
+		case <-d.ctx.Done():
+			return
+		}
+		if err != nil {
+			return
+		}
+		line += bytes.Count(data, []byte{'\n'})
+		off += int64(len(data))
+	}
+}
+
+// readLineEnd appends the rest of the current line to data. When the line is
+// longer than MaxLineLen, it stops reading after the limit; the decoder of
+// the chunk then fails with ErrLineTooLong, and the chunk is the last one.
+func (d *ParallelDecoder) readLineEnd(rdr *bufio.Reader, data []byte) ([]byte, error) {
+	n := len(data) - bytes.LastIndexByte(data, '\n') - 1 // length of the line in data
+	for {
+		frag, err := rdr.ReadSlice('\n')
+		data = append(data, frag...)
+		n += len(frag)
+		if d.lim.lineLen > 0 && n > d.lim.lineLen+1 {
+			return data, io.EOF
+		}
+		if err != bufio.ErrBufferFull {
+			return data, err
+		}
+	}
+}
+
+// parse parses the chunk.
+func (d *ParallelDecoder) parse(c chunk) *batch {
+	b := &batch{seq: c.seq}
+	errs := errSink{lenient: d.errs.lenient}
+	if errs.lenient {
+		errs.report = func(err error) { b.errs = append(b.errs, err) }
+	}
+
+	var decode func() (Quad, error)
+	r := bytes.NewReader(c.data)
+	if d.format == NTriples {
+		dec := newNTDecoder(context.Background(), r)
+		dec.bnodes, dec.dict, dec.errs = d.bnodes, d.dict, errs
+		dec.l.line, dec.l.read = c.line-1, c.off
+		dec.l.lim = d.chunkLim
+		decode = func() (Quad, error) {
+			t, err := dec.Decode()
+			return Quad{Triple: t, Ctx: d.DefaultGraph}, err
+		}
+	} else {
+		dec := newQuadDecoder(context.Background(), r, NQuads)
+		dec.bnodes, dec.dict, dec.errs = d.bnodes, d.dict, errs
+		dec.DefaultGraph = d.DefaultGraph
+		dec.l.line, dec.l.read = c.line-1, c.off
+		dec.l.lim = d.chunkLim
+		decode = dec.Decode
+	}
+
+	for {
+		q, err := decode()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			b.err = err
+			return b
+		}
+		b.quads = append(b.quads, q)
+	}
+	b.err = c.err
+	return b
+}
+
+// chunkLimits returns the limits of the decoders of the chunks. The triples
+// are counted by Decode, for the whole document.
+func (d *ParallelDecoder) chunkLimits() limits {
+	lim := d.lim
+	lim.triples, lim.count = 0, 0
+	return lim
+}
+
+// lockedAllocator serializes the calls to a BlankAllocator.
+type lockedAllocator struct {
+	mu sync.Mutex
+	a  BlankAllocator
+}
+
+func (l *lockedAllocator) New() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.a.New()
+}
+
+func (l *lockedAllocator) Scope(label string) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.a.Scope(label)
+}
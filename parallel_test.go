@@ -0,0 +1,164 @@
+package rdf
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// parallelTestInput returns an N-Quads document with n lines. The lines
+// have different lengths, so that they are cut at different places when
+// split into chunks.
+func parallelTestInput(n int, quads bool) string {
+	var b strings.Builder
+	for i := 1; i <= n; i++ {
+		g := ""
+		if quads && i%3 == 0 {
+			g = fmt.Sprintf(" <http://ex/g%d>", i%7)
+		}
+		switch i % 5 {
+		case 0:
+			fmt.Fprintf(&b, "# comment %d\n", i)
+		case 1:
+			fmt.Fprintf(&b, "<http://ex/s%d> <http://ex/p> \"%s\"@en%s .\n", i, strings.Repeat("é", i%40), g)
+		case 2:
+			fmt.Fprintf(&b, "_:b%d <http://ex/p> <http://ex/o%d>%s .\n", i%11, i, g)
+		default:
+			fmt.Fprintf(&b, "<http://ex/s%d> <http://ex/p> \"%d\"^^<http://www.w3.org/2001/XMLSchema#integer>%s .\n", i, i, g)
+		}
+	}
+	return b.String()
+}
+
+func TestParallelDecoder(t *testing.T) {
+	for _, f := range []Format{NTriples, NQuads} {
+		input := parallelTestInput(5000, f == NQuads)
+		want, err := NewQuadDecoder(strings.NewReader(input), NQuads).DecodeAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, ordered := range []bool{true, false} {
+			dec := NewParallelDecoder(strings.NewReader(input), f, 4)
+			dec.chunkSize = 4096
+			if err := dec.SetOption(Ordered, ordered); err != nil {
+				t.Fatal(err)
+			}
+			got, err := dec.DecodeAll()
+			if err != nil {
+				t.Fatalf("%v ordered=%v: DecodeAll() failed with %v", f, ordered, err)
+			}
+			want := want
+			if !ordered {
+				want = append([]Quad(nil), want...)
+				sort.Slice(got, func(i, j int) bool { return CompareQuads(got[i], got[j]) < 0 })
+				sort.Slice(want, func(i, j int) bool { return CompareQuads(want[i], want[j]) < 0 })
+			}
+			if len(got) != len(want) {
+				t.Fatalf("%v ordered=%v: decoded %d quads; want %d", f, ordered, len(got), len(want))
+			}
+			for i := range got {
+				if !QuadsEqual(got[i], want[i]) {
+					t.Fatalf("%v ordered=%v: quad %d => %v; want %v", f, ordered, i, got[i], want[i])
+				}
+			}
+		}
+	}
+}
+
+func TestParallelDecoderErrors(t *testing.T) {
+	lines := strings.SplitAfter(parallelTestInput(3000, false), "\n")
+	lines[1233] = "<http://ex/s> <http://ex/p> <http://ex/o> <http://ex/g> .\n"
+	lines[2499] = "<http://ex/s> <http://ex/p> \"unterminated .\n" // replaces a comment
+	input := strings.Join(lines, "")
+	off1 := int64(len(strings.Join(lines[:1233], "")))
+
+	// Strict mode stops at the first error, after the triples before it.
+	dec := NewParallelDecoder(strings.NewReader(input), NTriples, 4)
+	dec.chunkSize = 4096
+	n := 0
+	var err error
+	for _, err = dec.Decode(); err == nil; _, err = dec.Decode() {
+		n++
+	}
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("Decode() => %v; want *ParseError", err)
+	}
+	if perr.Line != 1234 || perr.Column != 43 || perr.Offset != off1+42 {
+		t.Errorf("Decode() => error at %d:%d (offset %d); want 1234:43 (offset %d)", perr.Line, perr.Column, perr.Offset, off1+42)
+	}
+	if want := 1233 - 1233/5; n != want {
+		t.Errorf("decoded %d triples before the error; want %d", n, want)
+	}
+	if _, err2 := dec.Decode(); err2 != err {
+		t.Errorf("Decode() after error => %v; want %v", err2, err)
+	}
+
+	// Lenient mode reports all the errors, and skips their lines.
+	for _, ordered := range []bool{true, false} {
+		var errs []error
+		dec = NewParallelDecoder(strings.NewReader(input), NTriples, 4)
+		dec.chunkSize = 4096
+		dec.SetOption(Ordered, ordered)
+		dec.SetOption(Strict, false)
+		dec.SetOption(ErrOut, func(err error) { errs = append(errs, err) })
+		qs, err := dec.DecodeAll()
+		if err != nil {
+			t.Fatalf("ordered=%v: DecodeAll() failed with %v", ordered, err)
+		}
+		if want := 3000 - 3000/5 - 1; len(qs) != want {
+			t.Errorf("ordered=%v: decoded %d triples; want %d", ordered, len(qs), want)
+		}
+		var got []int
+		for _, err := range errs {
+			if errors.As(err, &perr) {
+				got = append(got, perr.Line)
+			}
+		}
+		sort.Ints(got)
+		if fmt.Sprint(got) != "[1234 2500]" {
+			t.Errorf("ordered=%v: errors at lines %v; want [1234 2500]", ordered, got)
+		}
+	}
+}
+
+func TestParallelDecoderClose(t *testing.T) {
+	before := runtime.NumGoroutine()
+	dec := NewParallelDecoder(strings.NewReader(parallelTestInput(5000, true)), NQuads, 4)
+	dec.chunkSize = 1024
+	if _, err := dec.Decode(); err != nil {
+		t.Fatal(err)
+	}
+	dec.Close()
+	if _, err := dec.Decode(); err != ErrDecoderClosed {
+		t.Errorf("Decode() after Close() => %v; want ErrDecoderClosed", err)
+	}
+	waitGoroutines(t, before)
+
+	dec = NewParallelDecoder(strings.NewReader(""), NTriples, 0)
+	if _, err := dec.Decode(); err != io.EOF {
+		t.Errorf("Decode() of empty input => %v; want io.EOF", err)
+	}
+	if err := dec.SetOption(Base, IRI{str: "http://ex/"}); err == nil {
+		t.Error("SetOption(Base) => no error; want error")
+	}
+}
+
+func BenchmarkDecodeParallelNT(b *testing.B) {
+	input := parallelTestInput(200000, false)
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dec := NewParallelDecoder(strings.NewReader(input), NTriples, 0)
+		dec.SetOption(Ordered, false)
+		if _, err := dec.DecodeAll(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
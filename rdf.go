@@ -22,78 +22,125 @@
 //  JSON-LD    | -      | -
 //
 // The parsers are implemented as streaming decoders, consuming an io.Reader
-// and emitting triples/quads as soon as they are available. Simply call
-// Decode() until the reader is exhausted and emits io.EOF:
+// and emitting triples/quads as soon as they are available. Simply range
+// over All(), which stops when the reader is exhausted:
 //
 //    f, err := os.Open("mytriples.ttl")
 //    if err != nil {
 //        // handle error
 //    }
 //    dec := rdf.NewTripleDecoder(f, rdf.Turtle)
-//    for triple, err := dec.Decode(); err != io.EOF; triple, err = dec.Decode() {
+//    for triple, err := range dec.All() {
+//        if err != nil {
+//            // handle error
+//        }
 //        // do something with triple ..
 //    }
 //
+// Or call Decode() until it returns io.EOF, or another error.
+//
+// The format of a document can be found from its media type with
+// FormatFromMediaType, from its file extension with FormatFromExtension, or
+// from its content with DetectFormat. Open opens a file for decoding, in the
+// format found from its name or content.
+//
 // The encoders work similarily.
 // For a complete working example, see the rdf2rdf application, which converts between different serialization formats using the decoders and encoders of the rdf package: https://github.com/knakk/rdf2rdf.
 package rdf
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"math/big"
+	"net"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
 	"unicode/utf8"
+
+	"github.com/knakk/rdf/internal/ns"
 )
 
 // DateFormat defines the string representation of xsd:DateTime values. You can override
 // it if you need another layout.
 var DateFormat = time.RFC3339
 
+// Terms from the RDF vocabulary used by the decoders and encoders:
+var (
+	rdfType      = IRI{str: ns.RDF + "type"}
+	rdfFirst     = IRI{str: ns.RDF + "first"}
+	rdfRest      = IRI{str: ns.RDF + "rest"}
+	rdfNil       = IRI{str: ns.RDF + "nil"}
+	rdfSubj      = IRI{str: ns.RDF + "subject"}
+	rdfPred      = IRI{str: ns.RDF + "predicate"}
+	rdfObj       = IRI{str: ns.RDF + "object"}
+	rdfStatement = IRI{str: ns.RDF + "Statement"}
+	rdfSeq       = IRI{str: ns.RDF + "Seq"}
+	rdfBag       = IRI{str: ns.RDF + "Bag"}
+	rdfAlt       = IRI{str: ns.RDF + "Alt"}
+)
+
 // The XML schema built-in datatypes (xsd):
 // https://dvcs.w3.org/hg/rdf/raw-file/default/rdf-concepts/index.html#xsd-datatypes
 var (
 	// Core types:                                                    // Corresponding Go datatype:
 
-	xsdString  = IRI{str: "http://www.w3.org/2001/XMLSchema#string"}  // string
-	xsdBoolean = IRI{str: "http://www.w3.org/2001/XMLSchema#boolean"} // bool
-	xsdDecimal = IRI{str: "http://www.w3.org/2001/XMLSchema#decimal"} // float64
-	xsdInteger = IRI{str: "http://www.w3.org/2001/XMLSchema#integer"} // int
+	xsdString  = IRI{str: ns.XSD + "string"}  // string
+	xsdBoolean = IRI{str: ns.XSD + "boolean"} // bool
+	xsdDecimal = IRI{str: ns.XSD + "decimal"} // *big.Rat
+	xsdInteger = IRI{str: ns.XSD + "integer"} // *big.Int
 
 	// IEEE floating-point numbers:
 
-	xsdDouble = IRI{str: "http://www.w3.org/2001/XMLSchema#double"} // float64
-	xsdFloat  = IRI{str: "http://www.w3.org/2001/XMLSchema#float"}  // float64
+	xsdDouble = IRI{str: ns.XSD + "double"} // float64
+	xsdFloat  = IRI{str: ns.XSD + "float"}  // float32
 
 	// Time and date:
 
-	//xsdDate = IRI{str: "http://www.w3.org/2001/XMLSchema#date"}
-	//xsdTime          = IRI{str: "http://www.w3.org/2001/XMLSchema#time"}
-	xsdDateTime = IRI{str: "http://www.w3.org/2001/XMLSchema#dateTime"} // time.Time
-	//xsdDateTimeStamp = IRI{str: "http://www.w3.org/2001/XMLSchema#dateTimeStamp"}
+	xsdDate          = IRI{str: ns.XSD + "date"}          // Date
+	xsdTime          = IRI{str: ns.XSD + "time"}          // Time
+	xsdDateTime      = IRI{str: ns.XSD + "dateTime"}      // time.Time
+	xsdDateTimeStamp = IRI{str: ns.XSD + "dateTimeStamp"} // time.Time
 
 	// Recurring and partial dates:
 
-	//xsdYear              = IRI{str: "http://www.w3.org/2001/XMLSchema#gYear"}
-	//xsdMonth             = IRI{str: "http://www.w3.org/2001/XMLSchema#gMonth"}
-	//xsdDay               = IRI{str: "http://www.w3.org/2001/XMLSchema#gDay"}
-	//xsdYearMonth         = IRI{str: "http://www.w3.org/2001/XMLSchema#gYearMonth"}
-	//xsdDuration          = IRI{str: "http://www.w3.org/2001/XMLSchema#Duration"}
-	//xsdYearMonthDuration = IRI{str: "http://www.w3.org/2001/XMLSchema#yearMonthDuration"}
-	//xsdDayTimeDuration   = IRI{str: "http://www.w3.org/2001/XMLSchema#dayTimeDuration"}
+	xsdYear = IRI{str: ns.XSD + "gYear"} // GYear
+	//xsdMonth             = IRI{str: ns.XSD + "gMonth"}
+	//xsdDay               = IRI{str: ns.XSD + "gDay"}
+	xsdYearMonth         = IRI{str: ns.XSD + "gYearMonth"}        // GYearMonth
+	xsdDuration          = IRI{str: ns.XSD + "duration"}          // Duration
+	xsdYearMonthDuration = IRI{str: ns.XSD + "yearMonthDuration"} // Duration
+	xsdDayTimeDuration   = IRI{str: ns.XSD + "dayTimeDuration"}   // time.Duration
 
 	// Limited-range integer numbers
 
-	xsdByte = IRI{str: "http://www.w3.org/2001/XMLSchema#byte"} // []byte
-	//xsdShort = IRI{str: "http://www.w3.org/2001/XMLSchema#short"} // int16
-	xsdInt = IRI{str: "http://www.w3.org/2001/XMLSchema#int"} // int32
-	//xsdLong  = IRI{str: "http://www.w3.org/2001/XMLSchema#long"}  // int64
+	xsdByte  = IRI{str: ns.XSD + "byte"}  // int8
+	xsdShort = IRI{str: ns.XSD + "short"} // int16
+	xsdInt   = IRI{str: ns.XSD + "int"}   // int32
+	xsdLong  = IRI{str: ns.XSD + "long"}  // int64
+
+	xsdUnsignedByte  = IRI{str: ns.XSD + "unsignedByte"}  // uint8
+	xsdUnsignedShort = IRI{str: ns.XSD + "unsignedShort"} // uint16
+	xsdUnsignedInt   = IRI{str: ns.XSD + "unsignedInt"}   // uint32
+	xsdUnsignedLong  = IRI{str: ns.XSD + "unsignedLong"}  // uint64
+
+	xsdPositiveInteger    = IRI{str: ns.XSD + "positiveInteger"}    // *big.Int
+	xsdNonNegativeInteger = IRI{str: ns.XSD + "nonNegativeInteger"} // *big.Int
+	xsdNegativeInteger    = IRI{str: ns.XSD + "negativeInteger"}    // *big.Int
+	xsdNonPositiveInteger = IRI{str: ns.XSD + "nonPositiveInteger"} // *big.Int
+
+	// Encoded binary data
+
+	xsdHexBinary    = IRI{str: ns.XSD + "hexBinary"}    // []byte
+	xsdBase64Binary = IRI{str: ns.XSD + "base64Binary"} // []byte
 
 	// Various
 
-	rdfLangString = IRI{str: "http://www.w3.org/1999/02/22-rdf-syntax-ns#langString"} // string
-	xmlLiteral    = IRI{str: "http://www.w3.org/1999/02/22-rdf-syntax-ns#XMLLiteral"} // string
+	xsdAnyURI     = IRI{str: ns.XSD + "anyURI"}     // *url.URL
+	rdfLangString = IRI{str: ns.RDF + "langString"} // string
+	xmlLiteral    = IRI{str: ns.RDF + "XMLLiteral"} // string
 )
 
 // Format represents a RDF serialization format.
@@ -106,18 +153,19 @@ const (
 	NTriples Format = iota
 	Turtle
 	RDFXML
-	// TODO: JSON-LD
 
 	// Quad serialization:
 
 	NQuads // N-Quads
+	JSONLD // JSON-LD; only recognized by DetectFormat, not decoded or encoded yet
 	// TODO: Format TriG
 
 	// Internal formats
 	formatInternal
 )
 
-// Term represents an RDF term. There are 3 term types: Blank node, Literal and IRI.
+// Term represents an RDF term. There are 4 term types: Blank node, Literal, IRI
+// and the RDF-star quoted triple.
 type Term interface {
 	// Serialize returns a string representation of the Term in the specified serialization format.
 	Serialize(Format) string
@@ -129,7 +177,7 @@ type Term interface {
 	Type() TermType
 }
 
-// TermType describes the type of RDF term: Blank node, IRI or Literal
+// TermType describes the type of RDF term: Blank node, IRI, Literal or quoted triple
 type TermType int
 
 // Exported RDF term types.
@@ -137,6 +185,7 @@ const (
 	TermBlank TermType = iota
 	TermIRI
 	TermLiteral
+	TermQuotedTriple
 )
 
 // Blank represents a RDF blank node; an unqualified IRI with identified by a label.
@@ -299,96 +348,212 @@ func (l Literal) String() string {
 }
 
 // Typed tries to parse the Literal's value into a Go type, acordig to the
-// the DataType.
+// the DataType. The XSD datatypes map to Go types as follows:
+//
+//  xsd:string, rdf:langString          string
+//  xsd:boolean                         bool
+//  xsd:decimal                         *big.Rat
+//  xsd:integer (and unbounded subtypes) *big.Int
+//  xsd:long, int, short, byte          int64, int32, int16, int8
+//  xsd:unsignedLong, .. unsignedByte   uint64, uint32, uint16, uint8
+//  xsd:double, xsd:float               float64, float32
+//  xsd:dateTime, xsd:dateTimeStamp     time.Time
+//  xsd:date, xsd:time                  Date, Time
+//  xsd:gYear, xsd:gYearMonth           GYear, GYearMonth
+//  xsd:duration, yearMonthDuration     Duration
+//  xsd:dayTimeDuration                 time.Duration
+//  xsd:hexBinary, xsd:base64Binary     []byte
+//  xsd:anyURI                          *url.URL
+//
+// Literals of any other datatype are returned as a string. An error is
+// returned if the lexical form is not valid for the datatype. The *big.Int
+// and *big.Rat values are copies, which the caller may modify.
 func (l Literal) Typed() (interface{}, error) {
-	if l.val == nil {
-		switch l.DataType.str {
-		case xsdInteger.str, xsdInt.str:
-			i, err := strconv.Atoi(l.str)
-			if err != nil {
-				return nil, err
-			}
-			l.val = i
-			return i, nil
-		case xsdDouble.str, xsdDecimal.str:
-			f, err := strconv.ParseFloat(l.str, 64)
-			if err != nil {
-				return nil, err
-			}
-			l.val = f
-			return f, nil
-		case xsdBoolean.str:
-			b, err := strconv.ParseBool(l.str)
-			if err != nil {
-				return nil, err
-			}
-			l.val = b
-			return b, nil
-		case xsdByte.str:
-			return []byte(l.str), nil
-			// TODO xsdDateTime etc
-		default:
-			return l.str, nil
-		}
+	switch v := l.val.(type) {
+	case nil:
+		return parseTyped(l.str, l.DataType)
+	case *big.Int:
+		// A copy, so that the caller cannot modify the literal.
+		return new(big.Int).Set(v), nil
+	case *big.Rat:
+		return new(big.Rat).Set(v), nil
 	}
 	return l.val, nil
 }
 
+// Int64 returns the value of a numeric literal as an int64, or an error if
+// the value is not an integer in the range of int64. See LiteralAs.
+func (l Literal) Int64() (int64, error) {
+	return LiteralAs[int64](l)
+}
+
+// Uint64 returns the value of a numeric literal as an uint64, or an error if
+// the value is not an integer in the range of uint64. See LiteralAs.
+func (l Literal) Uint64() (uint64, error) {
+	return LiteralAs[uint64](l)
+}
+
+// Float64 returns the value of a numeric literal as a float64, or an error
+// if the literal is not a number. See LiteralAs.
+func (l Literal) Float64() (float64, error) {
+	return LiteralAs[float64](l)
+}
+
+// Bool returns the value of a xsd:boolean literal, or an error if the
+// literal is not a boolean.
+func (l Literal) Bool() (bool, error) {
+	return LiteralAs[bool](l)
+}
+
+// Time returns the value of a xsd:dateTime or xsd:dateTimeStamp literal, or
+// an error if the literal is not a dateTime.
+func (l Literal) Time() (time.Time, error) {
+	return LiteralAs[time.Time](l)
+}
+
+// IsWellTyped reports whether the Literal's lexical form is valid for its
+// datatype. Literals with a datatype not known to the package are considered
+// well-typed, as are all strings.
+func (l Literal) IsWellTyped() bool {
+	if l.DataType == rdfLangString {
+		return l.lang != ""
+	}
+	_, err := parseTyped(l.str, l.DataType)
+	return err == nil
+}
+
+// Canonical returns the Literal with its lexical form in the canonical
+// representation of the datatype, so that literals with the same value have
+// the same lexical form; for example "01"^^xsd:integer becomes
+// "1"^^xsd:integer, and "1E2"^^xsd:double becomes "1.0E2"^^xsd:double.
+// xsd:dateTime and xsd:time values with a timezone are normalized to UTC.
+// Dates and the other date types keep their timezone, since normalizing it
+// could change the date; a zero offset is written as "Z".
+//
+// Literals with a datatype not known to the package are returned unchanged.
+// It returns an error if the Literal is not well-typed.
+func (l Literal) Canonical() (Literal, error) {
+	s, err := canonicalForm(l.str, l.DataType)
+	if err != nil {
+		return l, err
+	}
+	return Literal{str: s, lang: l.lang, DataType: l.DataType}, nil
+}
+
 // validAsObject denotes that a Literal is valid as a Triple's Object.
 func (l Literal) validAsObject() {}
 
 // NewLiteral returns a new Literal, or an error on invalid input. It tries
-// to map the given Go values to a corresponding xsd datatype.
+// to map the given Go values to a corresponding xsd datatype:
+//
+//  Go type                          Datatype
+//  ------------------------------   --------------------------------------
+//  bool                             xsd:boolean
+//  int, *big.Int                    xsd:integer
+//  int64, int32, int16, int8        xsd:long, xsd:int, xsd:short, xsd:byte
+//  uint                             xsd:nonNegativeInteger
+//  uint64, uint32, uint16, uint8    xsd:unsignedLong, .. xsd:unsignedByte
+//  float64, float32                 xsd:double, xsd:float
+//  *big.Rat                         xsd:decimal
+//  string                           xsd:string
+//  time.Time                        xsd:dateTime
+//  time.Duration                    xsd:dayTimeDuration
+//  Date, Time                       xsd:date, xsd:time
+//  GYear, GYearMonth                xsd:gYear, xsd:gYearMonth
+//  Duration                         xsd:duration
+//  []byte                           xsd:base64Binary
+//  *url.URL                         xsd:anyURI
+//  net.IP                           xsd:string, in the textual form of the IP
+//
+// This is the reverse of the mapping done by Literal.Typed, so that
+// LiteralAs can convert the literal back into the same Go type. A *big.Rat is
+// only accepted if it has a finite decimal expansion.
 func NewLiteral(v interface{}) (Literal, error) {
 	switch t := v.(type) {
 	case bool:
-		return Literal{val: t, str: fmt.Sprintf("%v", t), DataType: xsdBoolean}, nil
-	case int, int32, int64:
-		return Literal{val: t, str: fmt.Sprintf("%v", t), DataType: xsdInteger}, nil
+		return Literal{val: t, str: strconv.FormatBool(t), DataType: xsdBoolean}, nil
+	case int:
+		return Literal{val: big.NewInt(int64(t)), str: strconv.Itoa(t), DataType: xsdInteger}, nil
+	case int64:
+		return Literal{val: t, str: strconv.FormatInt(t, 10), DataType: xsdLong}, nil
+	case int32:
+		return Literal{val: t, str: strconv.FormatInt(int64(t), 10), DataType: xsdInt}, nil
+	case int16:
+		return Literal{val: t, str: strconv.FormatInt(int64(t), 10), DataType: xsdShort}, nil
+	case int8:
+		return Literal{val: t, str: strconv.FormatInt(int64(t), 10), DataType: xsdByte}, nil
+	case uint:
+		return Literal{val: new(big.Int).SetUint64(uint64(t)), str: strconv.FormatUint(uint64(t), 10), DataType: xsdNonNegativeInteger}, nil
+	case uint64:
+		return Literal{val: t, str: strconv.FormatUint(t, 10), DataType: xsdUnsignedLong}, nil
+	case uint32:
+		return Literal{val: t, str: strconv.FormatUint(uint64(t), 10), DataType: xsdUnsignedInt}, nil
+	case uint16:
+		return Literal{val: t, str: strconv.FormatUint(uint64(t), 10), DataType: xsdUnsignedShort}, nil
+	case uint8:
+		return Literal{val: t, str: strconv.FormatUint(uint64(t), 10), DataType: xsdUnsignedByte}, nil
+	case *big.Int:
+		if t == nil {
+			return Literal{}, errors.New("cannot create literal from nil *big.Int")
+		}
+		return Literal{val: new(big.Int).Set(t), str: t.String(), DataType: xsdInteger}, nil
+	case *big.Rat:
+		if t == nil {
+			return Literal{}, errors.New("cannot create literal from nil *big.Rat")
+		}
+		s, ok := formatRat(t)
+		if !ok {
+			return Literal{}, fmt.Errorf("cannot create xsd:decimal from %v; no finite decimal expansion", t)
+		}
+		return Literal{val: new(big.Rat).Set(t), str: s, DataType: xsdDecimal}, nil
 	case string:
 		return Literal{str: t, DataType: xsdString}, nil
-	case float32, float64:
-		return Literal{val: t, str: fmt.Sprintf("%v", t), DataType: xsdDouble}, nil
+	case float32:
+		return Literal{val: t, str: formatFloat(float64(t), 32), DataType: xsdFloat}, nil
+	case float64:
+		return Literal{val: t, str: formatFloat(t, 64), DataType: xsdDouble}, nil
 	case time.Time:
 		return Literal{val: t, str: t.Format(DateFormat), DataType: xsdDateTime}, nil
+	case time.Duration:
+		return Literal{val: t, str: Duration{Time: t}.String(), DataType: xsdDayTimeDuration}, nil
+	case Date:
+		return Literal{val: t, str: t.String(), DataType: xsdDate}, nil
+	case Time:
+		return Literal{val: t, str: t.String(), DataType: xsdTime}, nil
+	case GYear:
+		return Literal{val: t, str: t.String(), DataType: xsdYear}, nil
+	case GYearMonth:
+		return Literal{val: t, str: t.String(), DataType: xsdYearMonth}, nil
+	case Duration:
+		return Literal{val: t, str: t.String(), DataType: xsdDuration}, nil
 	case []byte:
-		return Literal{val: t, str: string(t), DataType: xsdByte}, nil
+		return Literal{val: t, str: base64.StdEncoding.EncodeToString(t), DataType: xsdBase64Binary}, nil
+	case *url.URL:
+		if t == nil {
+			return Literal{}, errors.New("cannot create literal from nil *url.URL")
+		}
+		return Literal{val: t, str: t.String(), DataType: xsdAnyURI}, nil
+	case net.IP:
+		if len(t) != net.IPv4len && len(t) != net.IPv6len {
+			return Literal{}, fmt.Errorf("cannot create literal from invalid IP %v", []byte(t))
+		}
+		return Literal{str: t.String(), DataType: xsdString}, nil
 	default:
 		return Literal{}, fmt.Errorf("cannot infer XSD datatype from %#v", t)
 	}
 }
 
 // NewLangLiteral creates a RDF literal with a given language tag, or fails
-// if the language tag is not well-formed.
+// if the language tag is not well-formed according to BCP 47. The language
+// tag is stored in canonical case, e.g. "EN-us" becomes "en-US".
 //
-// The literal will have the datatype IRI xsd:String.
+// The literal will have the datatype IRI rdf:langString.
 func NewLangLiteral(v, lang string) (Literal, error) {
-	afterDash := false
-	if len(lang) >= 1 && lang[0] == '-' {
-		return Literal{}, errors.New("invalid language tag: must start with a letter")
-	}
-	for _, r := range lang {
-		switch {
-		case (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z'):
-			continue
-		case r == '-':
-			if afterDash {
-				return Literal{}, errors.New("invalid language tag: only one '-' allowed")
-			}
-			afterDash = true
-		case r >= '0' && r <= '9':
-			if afterDash {
-				continue
-			}
-			fallthrough
-		default:
-			return Literal{}, fmt.Errorf("invalid language tag: unexpected character: %q", r)
-		}
+	tag, err := parseLangTag(lang)
+	if err != nil {
+		return Literal{}, err
 	}
-	if lang[len(lang)-1] == '-' {
-		return Literal{}, errors.New("invalid language tag: trailing '-' disallowed")
-	}
-	return Literal{str: v, lang: lang, DataType: rdfLangString}, nil
+	return Literal{str: v, lang: tag, DataType: rdfLangString}, nil
 }
 
 // NewTypedLiteral returns a literal with the given datatype.
@@ -396,6 +561,34 @@ func NewTypedLiteral(v string, dt IRI) Literal {
 	return Literal{str: v, DataType: dt}
 }
 
+// QuotedTriple represents a RDF-star quoted triple; a triple used as the
+// subject or object of another triple, without being asserted itself.
+type QuotedTriple struct {
+	Triple
+}
+
+// validAsSubject denotes that a QuotedTriple is valid as a Triple's Subject.
+func (q QuotedTriple) validAsSubject() {}
+
+// validAsObject denotes that a QuotedTriple is valid as a Triple's Object.
+func (q QuotedTriple) validAsObject() {}
+
+// Serialize returns a string representation of a QuotedTriple, in the
+// RDF-star syntax: << subject predicate object >>.
+func (q QuotedTriple) Serialize(f Format) string {
+	return fmt.Sprintf("<< %s %s %s >>", q.Subj.Serialize(f), q.Pred.Serialize(f), q.Obj.Serialize(f))
+}
+
+// Type returns the TermType of a QuotedTriple.
+func (q QuotedTriple) Type() TermType {
+	return TermQuotedTriple
+}
+
+// String returns the quoted triple in the RDF-star syntax, with the terms as stored.
+func (q QuotedTriple) String() string {
+	return fmt.Sprintf("<< %s %s %s >>", q.Subj, q.Pred, q.Obj)
+}
+
 // Subject interface distiguishes which Terms are valid as a Subject of a Triple.
 type Subject interface {
 	Term
@@ -439,6 +632,8 @@ func (t Triple) Serialize(f Format) string {
 		s = term.Serialize(f)
 	case Blank:
 		s = term.Serialize(f)
+	case QuotedTriple:
+		s = term.Serialize(f)
 	}
 	switch term := t.Obj.(type) {
 	case IRI:
@@ -447,6 +642,8 @@ func (t Triple) Serialize(f Format) string {
 		o = term.Serialize(f)
 	case Blank:
 		o = term.Serialize(f)
+	case QuotedTriple:
+		o = term.Serialize(f)
 	}
 	return fmt.Sprintf(
 		"%s %s %s .\n",
@@ -470,6 +667,8 @@ func (q Quad) Serialize(f Format) string {
 		s = term.Serialize(f)
 	case Blank:
 		s = term.Serialize(f)
+	case QuotedTriple:
+		s = term.Serialize(f)
 	}
 	switch term := q.Obj.(type) {
 	case IRI:
@@ -478,6 +677,8 @@ func (q Quad) Serialize(f Format) string {
 		o = term.Serialize(f)
 	case Blank:
 		o = term.Serialize(f)
+	case QuotedTriple:
+		o = term.Serialize(f)
 	}
 	switch term := q.Ctx.(type) {
 	case IRI:
@@ -495,13 +696,66 @@ func (q Quad) Serialize(f Format) string {
 }
 
 // TermsEqual returns true if two Terms are equal, or false if they are not.
+//
+// Literals are equal only if their lexical forms, datatypes and language tags
+// are equal; language tags are compared case-insensitively. Use
+// LiteralsValueEqual to compare the values of literals.
 func TermsEqual(a, b Term) bool {
 	if a.Type() != b.Type() {
 		return false
 	}
+	if la, ok := a.(Literal); ok {
+		lb, ok := b.(Literal)
+		return ok && la.str == lb.str && la.DataType == lb.DataType && strings.EqualFold(la.lang, lb.lang)
+	}
+	if qa, ok := a.(QuotedTriple); ok {
+		qb, ok := b.(QuotedTriple)
+		return ok && TriplesEqual(qa.Triple, qb.Triple)
+	}
 	return a.Serialize(formatInternal) == b.Serialize(formatInternal)
 }
 
+// LiteralsValueEqual returns true if two Literals represent the same value,
+// even if their lexical forms differ. Numbers are compared by value across
+// the numeric datatypes, so "01"^^xsd:integer, "1.0"^^xsd:decimal and
+// "1E0"^^xsd:double are all equal. Likewise for xsd:dateTime values in
+// different timezones, and for durations. A xsd:dateTime without a timezone
+// is not equal to one with a timezone, as XML Schema leaves their order
+// indeterminate. Other literals are equal if they have the same datatype and
+// canonical lexical form.
+//
+// Literals which are not well-typed, or have a datatype not known to the
+// package, are compared with TermsEqual.
+func LiteralsValueEqual(a, b Literal) bool {
+	fa, fb := valueSpaceOf(a.DataType), valueSpaceOf(b.DataType)
+	if fa == spaceOther || fa != fb {
+		return TermsEqual(a, b)
+	}
+	va, erra := a.Typed()
+	vb, errb := b.Typed()
+	if erra != nil || errb != nil {
+		return TermsEqual(a, b)
+	}
+	switch fa {
+	case spaceNumeric:
+		c, ok := compareNumbers(va, vb)
+		return ok && c == 0
+	case spaceDateTime:
+		ta, za, _ := parseDateTime(a.str, false)
+		tb, zb, _ := parseDateTime(b.str, false)
+		return za == zb && ta.Equal(tb)
+	case spaceDuration:
+		return asDuration(va) == asDuration(vb)
+	default:
+		if a.DataType != b.DataType {
+			return false
+		}
+		ca, _ := canonicalForm(a.str, a.DataType)
+		cb, _ := canonicalForm(b.str, b.DataType)
+		return ca == cb
+	}
+}
+
 // TriplesEqual tests if two Triples are identical.
 func TriplesEqual(a, b Triple) bool {
 	return TermsEqual(a.Subj, b.Subj) && TermsEqual(a.Pred, b.Pred) && TermsEqual(a.Obj, b.Obj)
@@ -2,7 +2,11 @@ package rdf
 
 import (
 	"fmt"
+	"math/big"
+	"net"
+	"net/url"
 	"testing"
+	"time"
 )
 
 func TestIRI(t *testing.T) {
@@ -30,19 +34,33 @@ func TestLiteral(t *testing.T) {
 	inferTypeTests := []struct {
 		input     interface{}
 		dt        IRI
+		lex       string
 		errString string
 	}{
-		{1, xsdInteger, ""},
-		{int64(1), xsdInteger, ""},
-		{int32(1), xsdInteger, ""},
-		{3.14, xsdDouble, ""},
-		{float32(3.14), xsdDouble, ""},
-		{float64(3.14), xsdDouble, ""},
-		{true, xsdBoolean, ""},
-		{false, xsdBoolean, ""},
-		{"a", xsdString, ""},
-		{[]byte("123"), xsdByte, ""},
-		{struct{ a, b string }{"1", "2"}, IRI{}, `cannot infer XSD datatype from struct { a string; b string }{a:"1", b:"2"}`},
+		{1, xsdInteger, "1", ""},
+		{int64(1), xsdLong, "1", ""},
+		{int32(1), xsdInt, "1", ""},
+		{int16(-1), xsdShort, "-1", ""},
+		{int8(1), xsdByte, "1", ""},
+		{uint(1), xsdNonNegativeInteger, "1", ""},
+		{uint64(18446744073709551615), xsdUnsignedLong, "18446744073709551615", ""},
+		{uint32(1), xsdUnsignedInt, "1", ""},
+		{uint16(1), xsdUnsignedShort, "1", ""},
+		{uint8(1), xsdUnsignedByte, "1", ""},
+		{new(big.Int).Lsh(big.NewInt(1), 70), xsdInteger, "1180591620717411303424", ""},
+		{big.NewRat(5, 4), xsdDecimal, "1.25", ""},
+		{big.NewRat(1, 3), IRI{}, "", "cannot create xsd:decimal from 1/3; no finite decimal expansion"},
+		{3.14, xsdDouble, "3.14", ""},
+		{float32(3.14), xsdFloat, "3.14", ""},
+		{float64(3.14), xsdDouble, "3.14", ""},
+		{true, xsdBoolean, "true", ""},
+		{false, xsdBoolean, "false", ""},
+		{"a", xsdString, "a", ""},
+		{[]byte("123"), xsdBase64Binary, "MTIz", ""},
+		{90 * time.Minute, xsdDayTimeDuration, "PT1H30M", ""},
+		{&url.URL{Scheme: "http", Host: "example.org", Path: "/a"}, xsdAnyURI, "http://example.org/a", ""},
+		{net.ParseIP("192.168.0.1"), xsdString, "192.168.0.1", ""},
+		{struct{ a, b string }{"1", "2"}, IRI{}, "", `cannot infer XSD datatype from struct { a string; b string }{a:"1", b:"2"}`},
 	}
 
 	for _, tt := range inferTypeTests {
@@ -64,23 +82,44 @@ func TestLiteral(t *testing.T) {
 		if l.DataType != tt.dt {
 			t.Errorf("NewLiteral(%#v).DataType => %v; want %v", tt.input, l.DataType, tt.dt)
 		}
+		if l.str != tt.lex {
+			t.Errorf("NewLiteral(%#v) => %q; want %q", tt.input, l.str, tt.lex)
+		}
 	}
 
 	langTagTests := []struct {
 		tag     string
+		want    string
 		errWant string
 	}{
-		{"en", ""},
-		{"en-GB", ""},
-		{"nb-no2", ""},
-		{"no-no-a", "invalid language tag: only one '-' allowed"},
-		{"1", "invalid language tag: unexpected character: '1'"},
-		{"fr-ø", "invalid language tag: unexpected character: 'ø'"},
-		{"en-", "invalid language tag: trailing '-' disallowed"},
-		{"-en", "invalid language tag: must start with a letter"},
+		{"en", "en", ""},
+		{"en-GB", "en-GB", ""},
+		{"EN-us", "en-US", ""},
+		{"zh-hant-tw", "zh-Hant-TW", ""},
+		{"sr-Latn-RS", "sr-Latn-RS", ""},
+		{"es-419", "es-419", ""},
+		{"zh-yue-HK", "zh-yue-HK", ""},
+		{"sl-ROZAJ-biske-1994", "sl-rozaj-biske-1994", ""},
+		{"en-a-BBB-x-A-CCC", "en-a-bbb-x-a-ccc", ""},
+		{"X-Whatever", "x-whatever", ""},
+		{"I-Klingon", "i-klingon", ""},
+		{"en-gb-oed", "en-GB-oed", ""},
+		{"nb-no2", "", `invalid language tag: invalid subtag: "no2"`},
+		{"no-no-a", "", `invalid language tag: empty extension: "a"`},
+		{"de-DE-1901-1901", "", `invalid language tag: duplicate variant: "1901"`},
+		{"en-a-bbb-a-ccc", "", `invalid language tag: duplicate singleton: "a"`},
+		{"en--US", "", "invalid language tag: empty subtag"},
+		{"en-x", "", "invalid language tag: empty private use subtag"},
+		{"abcdefghi", "", `invalid language tag: subtag too long: "abcdefghi"`},
+		{"a-DE", "", `invalid language tag: invalid primary language subtag: "a"`},
+		{"", "", "invalid language tag: empty"},
+		{"1", "", "invalid language tag: must start with a letter"},
+		{"fr-ø", "", "invalid language tag: unexpected character: 'ø'"},
+		{"en-", "", "invalid language tag: trailing '-' disallowed"},
+		{"-en", "", "invalid language tag: must start with a letter"},
 	}
 	for _, tt := range langTagTests {
-		_, err := NewLangLiteral("string", tt.tag)
+		l, err := NewLangLiteral("string", tt.tag)
 		if err != nil {
 			if tt.errWant == "" {
 				t.Errorf("NewLangLiteral(\"string\", %#v) failed with %v; want no error", tt.tag, err)
@@ -95,6 +134,58 @@ func TestLiteral(t *testing.T) {
 			t.Errorf("NewLangLiteral(\"string\", %#v) => <no error>; want error %v", tt.tag, tt.errWant)
 			continue
 		}
+		if err == nil && l.Lang() != tt.want {
+			t.Errorf("NewLangLiteral(\"string\", %#v).Lang() => %q; want %q", tt.tag, l.Lang(), tt.want)
+		}
+
+	}
+}
+
+func TestTermsEqual(t *testing.T) {
+	tests := []struct {
+		a, b Term
+		want bool
+	}{
+		{IRI{str: "http://ex/a"}, IRI{str: "http://ex/a"}, true},
+		{IRI{str: "http://ex/a"}, Blank{id: "_:a"}, false},
+		{NewTypedLiteral("1", xsdInteger), NewTypedLiteral("1", xsdInteger), true},
+		{NewTypedLiteral("1", xsdInteger), NewTypedLiteral("1", xsdInt), false},
+		{NewTypedLiteral("1", xsdInteger), NewTypedLiteral("01", xsdInteger), false},
+		{Literal{str: "a", lang: "en", DataType: rdfLangString}, Literal{str: "a", lang: "nb", DataType: rdfLangString}, false},
+		{Literal{str: "a", lang: "en-US", DataType: rdfLangString}, Literal{str: "a", lang: "en-us", DataType: rdfLangString}, true},
+	}
+	for _, tt := range tests {
+		if got := TermsEqual(tt.a, tt.b); got != tt.want {
+			t.Errorf("TermsEqual(%v, %v) => %v; want %v", tt.a.Serialize(NTriples), tt.b.Serialize(NTriples), got, tt.want)
+		}
+	}
+}
 
+func TestLiteralsValueEqual(t *testing.T) {
+	tests := []struct {
+		a, b Literal
+		want bool
+	}{
+		{NewTypedLiteral("01", xsdInteger), NewTypedLiteral("1", xsdInteger), true},
+		{NewTypedLiteral("1", xsdInteger), NewTypedLiteral("1.0", xsdDecimal), true},
+		{NewTypedLiteral("1", xsdByte), NewTypedLiteral("1E0", xsdDouble), true},
+		{NewTypedLiteral("0.1", xsdDecimal), NewTypedLiteral("0.1", xsdDouble), false},
+		{NewTypedLiteral("INF", xsdDouble), NewTypedLiteral("INF", xsdFloat), true},
+		{NewTypedLiteral("NaN", xsdDouble), NewTypedLiteral("NaN", xsdDouble), false},
+		{NewTypedLiteral("2", xsdInteger), NewTypedLiteral("3", xsdInteger), false},
+		{NewTypedLiteral("2002-10-10T12:00:00-05:00", xsdDateTime), NewTypedLiteral("2002-10-10T17:00:00Z", xsdDateTime), true},
+		{NewTypedLiteral("2002-10-10T17:00:00", xsdDateTime), NewTypedLiteral("2002-10-10T17:00:00Z", xsdDateTime), false},
+		{NewTypedLiteral("2002-10-10T17:00:00", xsdDateTime), NewTypedLiteral("2002-10-10T17:00:00.0", xsdDateTime), true},
+		{NewTypedLiteral("PT24H", xsdDuration), NewTypedLiteral("P1D", xsdDayTimeDuration), true},
+		{NewTypedLiteral("1", xsdBoolean), NewTypedLiteral("true", xsdBoolean), true},
+		{NewTypedLiteral("2002-10-10Z", xsdDate), NewTypedLiteral("2002-10-10+00:00", xsdDate), true},
+		{NewTypedLiteral("1", xsdBoolean), NewTypedLiteral("1", xsdInteger), false},
+		{NewTypedLiteral("x", xsdInteger), NewTypedLiteral("x", xsdInteger), true},
+		{NewTypedLiteral("1", xsdString), NewTypedLiteral("1", xsdInteger), false},
+	}
+	for _, tt := range tests {
+		if got := LiteralsValueEqual(tt.a, tt.b); got != tt.want {
+			t.Errorf("LiteralsValueEqual(%v, %v) => %v; want %v", tt.a.Serialize(NTriples), tt.b.Serialize(NTriples), got, tt.want)
+		}
 	}
 }
@@ -1,20 +1,23 @@
 package rdf
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"regexp"
 	"runtime"
-	"strings"
-	"unicode/utf8"
+
+	"github.com/knakk/rdf/internal/ns"
 )
 
 const (
-	rdfNS = `http://www.w3.org/1999/02/22-rdf-syntax-ns#`
-	xmlNS = `http://www.w3.org/XML/1998/namespace`
+	rdfNS = ns.RDF
+	xmlNS = ns.XML
 
 	// XML elements
 	elAbout           = "about"
@@ -39,17 +42,6 @@ const (
 	elXMLNS           = "xmlns"
 )
 
-var (
-	rdfType      = IRI{str: "http://www.w3.org/1999/02/22-rdf-syntax-ns#type"}
-	rdfFirst     = IRI{str: "http://www.w3.org/1999/02/22-rdf-syntax-ns#first"}
-	rdfRest      = IRI{str: "http://www.w3.org/1999/02/22-rdf-syntax-ns#rest"}
-	rdfNil       = IRI{str: "http://www.w3.org/1999/02/22-rdf-syntax-ns#nil"}
-	rdfSubj      = IRI{str: "http://www.w3.org/1999/02/22-rdf-syntax-ns#subject"}
-	rdfPred      = IRI{str: "http://www.w3.org/1999/02/22-rdf-syntax-ns#predicate"}
-	rdfObj       = IRI{str: "http://www.w3.org/1999/02/22-rdf-syntax-ns#object"}
-	rdfStatement = IRI{str: "http://www.w3.org/1999/02/22-rdf-syntax-ns#Statement"}
-)
-
 var rgxpNCName = regexp.MustCompile(`^[\pL_][\d\pL\pM_.-]*$`)
 
 // evalCtx represents the evaluation context for an xml node.
@@ -68,28 +60,107 @@ type evalCtx struct {
 //   decoder only emits valid triples as soon as they are available in a stream, and then
 //   it's up to the consumer to decide what to do with duplicates.
 type rdfXMLDecoder struct {
-	dec *xml.Decoder
+	dec    *xml.Decoder
+	r      *xmlReader      // the input of dec
+	runCtx context.Context // decoding stops when runCtx is done
+	closed bool            // true when the decoder is closed
 
 	// xml parser state
-	state     parseXMLFn // current state function
-	nextState parseXMLFn // which state function enter on the next call to Decode()
-	ns        []string   // prefix and namespaces (only from the top-level element, usually rdf:RDF)
-	base      string     // top level xml:base
-	bnodeN    int        // anonymous blank node counter
-	tok       xml.Token  // current XML token
-	topElem   string     // top level element (namespace+localname)
-	reifyID   string     // if not "", id to be resolved against the current in-scope Base IRI
-	dt        *IRI       // datatype of the Literal to be parsed
-	lang      string     // xml element in-scope xml:lang
-	current   Triple     // the current triple beeing parsed
-	ctx       evalCtx    // current node evaluation context
-	ctxStack  []evalCtx  // stack of parent evaluation contexts
+	state     parseXMLFn        // current state function
+	nextState parseXMLFn        // which state function enter on the next call to Decode()
+	ns        []string          // prefix and namespaces (only from the top-level element, usually rdf:RDF)
+	prefixes  map[string]string // all prefixes declared so far, map[prefix]namespace
+	base      string            // top level xml:base
+	bnodeN    int               // anonymous blank node counter
+	bnodes    BlankAllocator    // blank node label allocator, if any
+	dict      *Dictionary       // dictionary to intern IRIs in, if any
+	errs      errSink           // handling of malformed input
+	lim       limits            // resource limits
+	xmlErr    error             // error from the XML decoder, if any
+	tok       xml.Token         // current XML token
+	tokLine   int               // line of the current XML token
+	tokCol    int               // rune column of the current XML token
+	tokOff    int64             // byte offset of the current XML token
+	depth     int               // nesting depth of the current XML token
+	topElem   string            // top level element (namespace+localname)
+	reifyID   string            // if not "", id to be resolved against the current in-scope Base IRI
+	dt        *IRI              // datatype of the Literal to be parsed
+	lang      string            // xml element in-scope xml:lang
+	current   Triple            // the current triple beeing parsed
+	ctx       evalCtx           // current node evaluation context
+	ctxStack  []evalCtx         // stack of parent evaluation contexts
 
 	triples []Triple // complete, valid triples to be emitted
 }
 
-func newRDFXMLDecoder(r io.Reader) *rdfXMLDecoder {
-	return &rdfXMLDecoder{dec: xml.NewDecoder(r), nextState: parseXMLTopElem}
+func newRDFXMLDecoder(ctx context.Context, r io.Reader) *rdfXMLDecoder {
+	xr := &xmlReader{r: bufio.NewReader(cancelable(ctx, r)), line: 1}
+	d := &rdfXMLDecoder{
+		dec:       xml.NewDecoder(xr),
+		r:         xr,
+		runCtx:    ctx,
+		nextState: parseXMLTopElem,
+		prefixes:  make(map[string]string),
+		lim:       limits{format: RDFXML},
+	}
+	xr.lim = &d.lim
+	return d
+}
+
+// xmlReader is the input of the XML decoder. It counts the UTF-8
+// continuation bytes on the current and previous line, so that the byte
+// columns reported by the XML decoder can be converted to rune columns.
+// It fails with ErrLineTooLong when a line is longer than allowed.
+type xmlReader struct {
+	r              *bufio.Reader
+	lim            *limits
+	line           int   // current line, starting at 1
+	cont, prevCont int   // continuation bytes on the current and previous line
+	off, lineOff   int64 // byte offset of the input, and of the current line
+	err            error // ErrLineTooLong, once a line is too long
+}
+
+// ReadByte reads a byte. The XML decoder reads its input byte by byte
+// when it implements io.ByteReader.
+func (r *xmlReader) ReadByte() (byte, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	b, err := r.r.ReadByte()
+	if err == nil {
+		r.count(b)
+	}
+	return b, err
+}
+
+// Read implements io.Reader.
+func (r *xmlReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	n, err := r.r.Read(p)
+	for _, b := range p[:n] {
+		r.count(b)
+	}
+	if r.err != nil {
+		err = r.err
+	}
+	return n, err
+}
+
+func (r *xmlReader) count(b byte) {
+	r.off++
+	switch {
+	case b == '\n':
+		r.line++
+		r.prevCont, r.cont = r.cont, 0
+		r.lineOff = r.off
+	case b&0xC0 == 0x80:
+		r.cont++
+	}
+	if max := r.lim.lineLen; max > 0 && r.off-r.lineOff > int64(max) {
+		r.err = ErrLineTooLong
+	}
 }
 
 // SetOption sets a ParseOption to the give value
@@ -101,6 +172,23 @@ func (d *rdfXMLDecoder) SetOption(o ParseOption, v interface{}) error {
 			return fmt.Errorf("ParseOption \"Base\" must be an IRI.")
 		}
 		d.ctx.Base = iri.str
+		d.base = iri.str
+	case BlankNodes:
+		a, ok := v.(BlankAllocator)
+		if !ok {
+			return fmt.Errorf("ParseOption \"BlankNodes\" must be a BlankAllocator.")
+		}
+		d.bnodes = a
+	case Intern:
+		dict, ok := v.(*Dictionary)
+		if !ok {
+			return fmt.Errorf("ParseOption \"Intern\" must be a *Dictionary.")
+		}
+		d.dict = dict
+	case Strict, ErrOut:
+		return d.errs.setOption(o, v)
+	case MaxLiteralLen, MaxIRILen, MaxLineLen, MaxXMLDepth, MaxTriples:
+		return d.lim.setOption(o, v)
 	default:
 		return fmt.Errorf("RDF/XML decoder doesn't support option: %v", o)
 	}
@@ -109,7 +197,37 @@ func (d *rdfXMLDecoder) SetOption(o ParseOption, v interface{}) error {
 
 // Decode parses a RDF/XML document, and returns the next available triple,
 // or an error.
-func (d *rdfXMLDecoder) Decode() (t Triple, err error) {
+func (d *rdfXMLDecoder) Decode() (Triple, error) {
+	for {
+		if d.closed {
+			return Triple{}, ErrDecoderClosed
+		}
+		if d.runCtx.Err() != nil {
+			return Triple{}, context.Cause(d.runCtx)
+		}
+		t, err := d.decode()
+		if err == nil {
+			if err = d.lim.countTriple(); err != nil {
+				return Triple{}, err
+			}
+			return t, nil
+		}
+		if d.runCtx.Err() != nil {
+			// The read was abandoned when the context was done.
+			return Triple{}, context.Cause(d.runCtx)
+		}
+		if !d.canSkip() || !d.errs.skip(err) {
+			return t, err
+		}
+		if err = d.skipNodeElem(); err != nil {
+			return t, err
+		}
+	}
+}
+
+// decode returns the next triple in the pipeline, running the parser state
+// machine when the pipeline is empty.
+func (d *rdfXMLDecoder) decode() (t Triple, err error) {
 	defer d.recover(&err)
 
 	if len(d.triples) == 0 {
@@ -126,6 +244,12 @@ func (d *rdfXMLDecoder) Decode() (t Triple, err error) {
 	}
 
 	t = d.triples[0]
+	// The XML decoder has already read the tokens holding the terms
+	// into memory; only MaxLineLen bounds what it reads.
+	if text, err := d.lim.checkTerms(t); err != nil {
+		return Triple{}, d.lim.errorAt(err, d.tokLine, d.tokCol, d.tokOff, []byte(text))
+	}
+	t = d.dict.internIRIs(t)
 	d.triples = d.triples[1:]
 	return t, err
 }
@@ -143,6 +267,60 @@ func (d *rdfXMLDecoder) DecodeAll() ([]Triple, error) {
 	return ts, nil
 }
 
+// All returns an iterator over the remaining triples of the document.
+func (d *rdfXMLDecoder) All() iter.Seq2[Triple, error] {
+	return decodeSeq(d.Decode)
+}
+
+// Prefixes returns the name space prefixes declared in the document so far,
+// with their namespaces. A default name space is returned with the empty
+// prefix. If a prefix is declared more than once, the last declaration wins.
+func (d *rdfXMLDecoder) Prefixes() map[string]string {
+	m := make(map[string]string, len(d.prefixes))
+	for prefix, ns := range d.prefixes {
+		m[prefix] = ns
+	}
+	return m
+}
+
+// Base returns the xml:base of the top-level element, or the one given by
+// the Base option.
+func (d *rdfXMLDecoder) Base() IRI {
+	return IRI{str: d.base}
+}
+
+// Close stops the decoder. The RDF/XML decoder reads its input as it
+// decodes, so there is nothing to clean up.
+func (d *rdfXMLDecoder) Close() error {
+	d.closed = true
+	return nil
+}
+
+// canSkip reports whether the decoder can recover from an error by skipping
+// the node element it occured in. That is only possible for node elements
+// in a rdf:RDF element, and not after errors in the XML syntax.
+func (d *rdfXMLDecoder) canSkip() bool {
+	return d.xmlErr == nil && d.topElem == rdfNS+elRDF && d.depth >= 1
+}
+
+// skipNodeElem skips the rest of the node element where an error occured,
+// and resets the parser to continue with the next node element.
+func (d *rdfXMLDecoder) skipNodeElem() (err error) {
+	defer d.recover(&err)
+	for d.depth > 1 {
+		d.nextXMLToken()
+	}
+	d.triples = d.triples[:0]
+	d.ctxStack = d.ctxStack[:0]
+	d.popContext()
+	d.current = Triple{}
+	d.dt = nil
+	d.lang = ""
+	d.reifyID = ""
+	d.nextState = parseXMLNodeElem
+	return nil
+}
+
 // parseXMLFn represents the state of the parser as a function that returns the
 // next state. A new xml.Token is assumed to be generated and stored in d.tok
 // before entering a new state function.
@@ -165,7 +343,7 @@ func parseXMLTopElem(d *rdfXMLDecoder) parseXMLFn {
 
 		// Store top-level base
 		if as := attrXML(elem, elBase); as != nil {
-			d.base = as[0].Value
+			d.base = d.resolve(d.ctx.Base, as[0].Value)
 		}
 
 		// Store top-level prefix and namespaces
@@ -217,7 +395,7 @@ func parseXMLNodeElem(d *rdfXMLDecoder) parseXMLFn {
 					if a := attrRDF(elem, elAbout); a != nil {
 						panic(errors.New("A node element cannot have both rdf:about and rdf:nodeID"))
 					}
-					d.current.Subj = Blank{id: fmt.Sprintf("_:%s", as[0].Value)}
+					d.current.Subj = d.blank(as[0].Value)
 				}
 
 				if as := attrRDF(elem, elType); as != nil {
@@ -232,14 +410,13 @@ func parseXMLNodeElem(d *rdfXMLDecoder) parseXMLFn {
 				}
 
 				if l := attrXML(elem, elLang); l != nil {
-					d.ctx.Lang = l[0].Value
+					d.ctx.Lang = langTagXML(l[0].Value)
 				}
 
 				if len(elem.Attr) == 0 || d.current.Subj == nil {
 					// A rdf:Description with no ID or about attribute describes an
 					// un-named resource, aka a bNode.
-					d.current.Subj = Blank{id: fmt.Sprintf("_:b%d", d.bnodeN)}
-					d.bnodeN++
+					d.current.Subj = d.newBlank()
 				}
 
 				if as := attrRest(elem); as != nil {
@@ -293,8 +470,7 @@ func parseXMLNodeElem(d *rdfXMLDecoder) parseXMLFn {
 
 		if d.current.Subj == nil {
 			// A typed element without with no attributes
-			d.current.Subj = Blank{id: fmt.Sprintf("_:b%d", d.bnodeN)}
-			d.bnodeN++
+			d.current.Subj = d.newBlank()
 		}
 
 		d.current.Pred = rdfType
@@ -400,8 +576,7 @@ first:
 				// A new element
 				if len(elem.Attr) == 0 {
 					// Element is a blank node
-					d.current.Obj = Blank{id: fmt.Sprintf("_:b%d", d.bnodeN)}
-					d.bnodeN++
+					d.current.Obj = d.newBlank()
 					d.triples = append(d.triples, d.current)
 
 					d.current.Subj = d.current.Obj.(Subject)
@@ -453,8 +628,7 @@ second:
 
 				if as := attrRest(elem); as != nil {
 					// Element is an anonymous blank node
-					d.current.Obj = Blank{id: fmt.Sprintf("_:b%d", d.bnodeN)}
-					d.bnodeN++
+					d.current.Obj = d.newBlank()
 					d.triples = append(d.triples, d.current)
 					d.reifyCheck()
 
@@ -472,7 +646,7 @@ second:
 				}
 
 				if as := attrRDF(elem, elNodeID); as != nil {
-					d.current.Obj = Blank{id: fmt.Sprintf("_:%s", as[0].Value)}
+					d.current.Obj = d.blank(as[0].Value)
 					d.triples = append(d.triples, d.current)
 					d.reifyCheck()
 
@@ -482,8 +656,7 @@ second:
 				}
 
 				// Default case, Element is a blank node
-				d.current.Obj = Blank{id: fmt.Sprintf("_:b%d", d.bnodeN)}
-				d.bnodeN++
+				d.current.Obj = d.newBlank()
 				d.triples = append(d.triples, d.current)
 				d.reifyCheck()
 
@@ -547,12 +720,12 @@ func parseXMLPropElem(d *rdfXMLDecoder) parseXMLFn {
 			switch elem.Name.Local {
 			case elLi:
 				d.ctx.LiN++
-				d.current.Pred = IRI{str: fmt.Sprintf("http://www.w3.org/1999/02/22-rdf-syntax-ns#_%d", d.ctx.LiN)}
+				d.current.Pred = memberProperty(d.ctx.LiN)
 			case elDescription, elRDF, elID, elAbout, elBagID, elParseType, elResource, elNodeID, elAboutEach, elAboutEachPrefix:
 				panic(fmt.Errorf("disallowed as property element name: rdf:%s", elem.Name.Local))
 			default:
 				if isLn(elem.Name.Local) {
-					d.current.Pred = IRI{str: fmt.Sprintf("http://www.w3.org/1999/02/22-rdf-syntax-ns#_%s", elem.Name.Local[1:])}
+					d.current.Pred = IRI{str: ns.RDF + "_" + elem.Name.Local[1:]}
 				}
 				// Default case, rdf name space
 				d.current.Pred = IRI{str: elem.Name.Space + elem.Name.Local}
@@ -572,8 +745,7 @@ func parseXMLPropElem(d *rdfXMLDecoder) parseXMLFn {
 			case "Resource":
 				// Omitting rdf:Decsription for blank node
 				// http://www.w3.org/TR/rdf-syntax-grammar/#section-Syntax-parsetype-resource
-				d.current.Obj = Blank{id: fmt.Sprintf("_:b%d", d.bnodeN)}
-				d.bnodeN++
+				d.current.Obj = d.newBlank()
 
 				d.triples = append(d.triples, d.current)
 				d.reifyCheck()
@@ -637,7 +809,7 @@ func parseXMLPropElem(d *rdfXMLDecoder) parseXMLFn {
 			// predicate is pointing to a blank node,
 			// create it and return
 
-			d.current.Obj = Blank{id: fmt.Sprintf("_:%s", as[0].Value)}
+			d.current.Obj = d.blank(as[0].Value)
 			d.triples = append(d.triples, d.current)
 			d.reifyCheck()
 
@@ -653,7 +825,7 @@ func parseXMLPropElem(d *rdfXMLDecoder) parseXMLFn {
 			// TODO or error if both?
 			if l := attrXML(elem, elLang); l != nil {
 				// store as in-scope lang
-				d.lang = l[0].Value
+				d.lang = langTagXML(l[0].Value)
 			}
 		}
 
@@ -665,8 +837,7 @@ func parseXMLPropElem(d *rdfXMLDecoder) parseXMLFn {
 			// these can be abbreviated by moving them to be property attributes
 			// on the containing property element which is made an empty element.
 			// http://www.w3.org/TR/rdf-syntax-grammar/#section-Syntax-property-attributes-on-property-element
-			d.current.Obj = Blank{id: fmt.Sprintf("_:b%d", d.bnodeN)}
-			d.bnodeN++
+			d.current.Obj = d.newBlank()
 			d.triples = append(d.triples, d.current)
 			d.pushContext()
 
@@ -701,8 +872,7 @@ func parseXMLPropElem(d *rdfXMLDecoder) parseXMLFn {
 // element with attribute parseType="Collection".
 // Subject an Predicate is set.
 func parseXMLColl(d *rdfXMLDecoder) parseXMLFn {
-	d.current.Obj = Blank{id: fmt.Sprintf("_:b%d", d.bnodeN)}
-	d.bnodeN++
+	d.current.Obj = d.newBlank()
 
 	d.triples = append(d.triples, d.current)
 
@@ -724,8 +894,7 @@ outer:
 						first = false
 					} else {
 						d.current.Pred = rdfRest
-						d.current.Obj = Blank{id: fmt.Sprintf("_:b%d", d.bnodeN)}
-						d.bnodeN++
+						d.current.Obj = d.newBlank()
 						d.triples = append(d.triples, d.current)
 
 						d.current.Subj = d.current.Obj.(Subject)
@@ -874,6 +1043,25 @@ func (d *rdfXMLDecoder) reifyCheck() {
 	}
 }
 
+// newBlank returns a new anonymous blank node.
+func (d *rdfXMLDecoder) newBlank() Blank {
+	if d.bnodes != nil {
+		return Blank{id: "_:" + d.bnodes.New()}
+	}
+	b := Blank{id: fmt.Sprintf("_:b%d", d.bnodeN)}
+	d.bnodeN++
+	return b
+}
+
+// blank returns the blank node with the given rdf:nodeID, scoped to the
+// document if a BlankAllocator is set.
+func (d *rdfXMLDecoder) blank(id string) Blank {
+	if d.bnodes == nil {
+		return Blank{id: "_:" + id}
+	}
+	return Blank{id: "_:" + d.bnodes.Scope(id)}
+}
+
 // getPrefix returns the in-scope prefix for the given name space.
 func (d *rdfXMLDecoder) getPrefix(ns string) string {
 	// First check for context local declarations
@@ -893,25 +1081,6 @@ func (d *rdfXMLDecoder) getPrefix(ns string) string {
 	panic(fmt.Errorf("no prefix found for name space: %q", ns))
 }
 
-// getNS returns the in-scope name space for the prefix.
-func (d *rdfXMLDecoder) getNS(prefix string) string {
-	// First check for context local declarations
-	for i := 0; i < len(d.ctx.NS); i += 2 {
-		if d.ctx.NS[i+1] == prefix {
-			return d.ctx.NS[i]
-		}
-	}
-
-	// Check in top-level declarations
-	for i := 0; i < len(d.ns); i += 2 {
-		if d.ns[i+1] == prefix {
-			return d.ns[i]
-		}
-	}
-
-	panic(fmt.Errorf("no name space found for prefix: %q", prefix))
-}
-
 // storePrefixNS stores any name space prefixes declared to the element context.
 // It also stores the base URI, if xml:base is present.
 // TODO also store xml:lang?
@@ -919,10 +1088,16 @@ func (d *rdfXMLDecoder) storePrefixNS(elem xml.StartElement) {
 	if as := attrXMLNS(elem); as != nil {
 		for _, a := range as {
 			d.ctx.NS = append(d.ctx.NS, a.Value, a.Name.Local)
+			d.prefixes[a.Name.Local] = a.Value
+		}
+	}
+	for _, a := range elem.Attr {
+		if a.Name.Space == "" && a.Name.Local == elXMLNS {
+			d.prefixes[""] = a.Value
 		}
 	}
 	if as := attrXML(elem, elBase); as != nil {
-		d.ctx.Base = as[0].Value
+		d.ctx.Base = d.resolve(d.ctx.Base, as[0].Value)
 	}
 }
 
@@ -967,158 +1142,106 @@ func (d *rdfXMLDecoder) recover(errp *error) {
 			panic(e)
 		}
 		//d.stop() something to clean up?
-		*errp = e.(error)
+		*errp = d.parseError(e.(error))
 	}
 	return
 }
 
-func (d *rdfXMLDecoder) nextXMLToken() {
-	var err error
-	d.tok, err = d.dec.Token()
-	if err != nil {
-		panic(err)
+// parseError returns the error as a ParseError located at the current XML
+// token, or where the XML decoder stopped in case of errors in the XML syntax.
+func (d *rdfXMLDecoder) parseError(err error) error {
+	if perr, ok := err.(*ParseError); ok {
+		return perr
 	}
-}
-
-func (d *rdfXMLDecoder) resolve(base string, path string) string {
-	for i := 0; i < len(path); {
-		r, w := utf8.DecodeRuneInString(path[i:])
-		i += w
-		if r == ':' {
-			if strings.HasPrefix(path[i:], "//") {
-				// scheme found; it means the path is not relative
-				return path
-			}
-			if i < len(path) {
-				// URI is composed of prefix:suffix
-				return d.getNS(path[:i-1]) + path[i:]
-			}
-			break
-		}
+	if err == io.EOF {
+		return err
 	}
-	if len(base) == 0 {
-		return path
+	if err == ErrLineTooLong {
+		return d.lim.errorAt(err, d.r.line, 1, d.r.lineOff, nil)
 	}
-	if len(path) == 0 {
-		return base[:iriFragmentIdx(base)]
+	perr := &ParseError{
+		Format: RDFXML,
+		Kind:   InvalidRDFXML,
+		Line:   d.tokLine,
+		Column: d.tokCol,
+		Offset: d.tokOff,
+		Token:  xmlTokenString(d.tok),
+		Msg:    err.Error(),
+		Err:    err,
 	}
-	switch path[0] {
-	case '#':
-		return base[:iriFragmentIdx(base)] + path
-	case '/':
-		if len(path) > 1 && path[1] == '/' {
-			return base[:iriSchemeEnd(base)] + path
-		}
-		return base[:iriHostEnd(base)] + path
-	case '.':
-		numLevels := len(strings.Split(path, "../"))
-		return base[:iriSlashIdx(base, numLevels)] + strings.TrimLeft(path, "../")
-	default:
-		i := iriLastSlashIdx(base)
-		if base[i-1] != '/' {
-			return base + "/" + path
+	if d.xmlErr != nil {
+		perr.Kind = InvalidXML
+		perr.Line, perr.Column = d.position()
+		perr.Offset = d.dec.InputOffset()
+		perr.Token = ""
+		if serr, ok := err.(*xml.SyntaxError); ok {
+			perr.Msg = "XML syntax error: " + serr.Msg
 		}
-		return base[:i] + path
 	}
+	return perr
 }
 
-func iriFragmentIdx(s string) int {
-	i := len(s)
-	for i > 0 {
-		r, w := utf8.DecodeLastRuneInString(s[0:i])
-		i -= w
-		if r == '#' {
-			return i
-		}
-		if r == '/' {
-			break
-		}
+// position returns the line and rune column where the XML decoder is.
+func (d *rdfXMLDecoder) position() (line, col int) {
+	line, col = d.dec.InputPos()
+	if line == d.r.line {
+		return line, col - d.r.cont
 	}
-	return len(s)
+	// The XML decoder has put back a newline it read.
+	return line, col - d.r.prevCont
 }
 
-func iriHostEnd(s string) int {
-	i := 0
-	l := len(s)
-	for i < l {
-		r, w := utf8.DecodeRuneInString(s[i:])
-		if w == 0 {
-			return i
-		}
-		i += w
-		if r == '.' {
-			for r, w = utf8.DecodeRuneInString(s[i:]); isAlpha(r); r, w = utf8.DecodeRuneInString(s[i:]) {
-				i += w
-			}
-			if w == 0 {
-				return i
-			}
-			if r == '/' {
-				return i
-			}
-		}
+// xmlTokenString returns the XML token as it may appear in the document, for
+// error messages. Elements are shown without their namespace prefix.
+func xmlTokenString(tok xml.Token) string {
+	switch tok := tok.(type) {
+	case xml.StartElement:
+		return "<" + tok.Name.Local + ">"
+	case xml.EndElement:
+		return "</" + tok.Name.Local + ">"
+	case xml.CharData:
+		return string(tok)
 	}
-	return i
+	return ""
 }
 
-func iriSchemeEnd(s string) int {
-	if strings.HasPrefix(s, "http://") {
-		return 5
+func (d *rdfXMLDecoder) nextXMLToken() {
+	d.tokLine, d.tokCol = d.position()
+	d.tokOff = d.dec.InputOffset()
+
+	var err error
+	d.tok, err = d.dec.Token()
+	if err != nil {
+		d.xmlErr = err
+		panic(err)
 	}
-	i := 0
-	l := len(s)
-	for i < l {
-		r, w := utf8.DecodeRuneInString(s[i:])
-		if w == 0 {
-			return i
-		}
-		i += w
-		if r == ':' {
-			if i+2 < l && s[i] == '/' && s[i+1] == '/' {
-				return i
-			}
+	switch d.tok.(type) {
+	case xml.StartElement:
+		d.depth++
+		if max := d.lim.xmlDepth; max > 0 && d.depth > max {
+			panic(d.lim.errorAt(ErrXMLTooDeep, d.tokLine, d.tokCol, d.tokOff, []byte(xmlTokenString(d.tok))))
 		}
+	case xml.EndElement:
+		d.depth--
 	}
-	return i
 }
 
-func iriLastSlashIdx(s string) int {
-	i := len(s)
-	for i > 0 {
-		r, w := utf8.DecodeLastRuneInString(s[0:i])
-		if r == '/' {
-			if i > 1 && s[i-w-1] == '/' {
-				return len(s)
-			}
-			return i
-		}
-		i -= w
+// langTagXML returns the value of an xml:lang attribute in canonical case.
+// An empty value is allowed, and means that no language is in scope.
+func langTagXML(s string) string {
+	if s == "" {
+		return ""
+	}
+	lang, err := parseLangTag(s)
+	if err != nil {
+		panic(err)
 	}
-	return 0
+	return lang
 }
 
-func iriSlashIdx(s string, n int) int {
-	c := 0
-	i := len(s)
-	for {
-		r, w := utf8.DecodeLastRuneInString(s[:i])
-		if w == 0 {
-			break
-		}
-		i -= w
-
-		if r == '/' {
-			if i > 0 && s[i-1] == '/' {
-				// Return if we have reached the URI scheme.
-				return i + 1
-			}
-			c++
-			if c == n {
-				return i + 1
-			}
-		}
-	}
-	return i
+// resolve resolves the IRI reference path against the given base IRI.
+func (d *rdfXMLDecoder) resolve(base string, path string) string {
+	return resolveIRI(base, path)
 }
 
 // isLn checks if string matches ^_[1-9]\d*$
@@ -0,0 +1,228 @@
+package rdf
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Reification is a statement described by the RDF reification vocabulary:
+//
+//  stmt rdf:type rdf:Statement .
+//  stmt rdf:subject s .
+//  stmt rdf:predicate p .
+//  stmt rdf:object o .
+//
+// The RDF/XML decoder produces such triples for property elements with a rdf:ID.
+type Reification struct {
+	Statement Subject // the node describing the triple
+	Triple    Triple  // the triple described; it is not necessarily asserted
+}
+
+// Reify returns the reification triples describing the triple t, with stmt
+// as the statement node.
+func Reify(stmt Subject, t Triple) []Triple {
+	return []Triple{
+		{Subj: stmt, Pred: rdfType, Obj: rdfStatement},
+		{Subj: stmt, Pred: rdfSubj, Obj: t.Subj.(Object)},
+		{Subj: stmt, Pred: rdfPred, Obj: t.Pred.(Object)},
+		{Subj: stmt, Pred: rdfObj, Obj: t.Obj},
+	}
+}
+
+// Reifications returns the reified statements in the graph, ordered by
+// statement node. A statement node must have exactly one rdf:subject, one
+// rdf:predicate and one rdf:object, with terms valid in their positions;
+// the rdf:type rdf:Statement triple is optional.
+func Reifications(g *Graph) []Reification {
+	var rs []Reification
+	for _, s := range g.Subjects() {
+		if r, ok := reificationOf(g, s); ok {
+			rs = append(rs, r)
+		}
+	}
+	return rs
+}
+
+// reificationOf returns the reified statement with the node stmt, if any.
+func reificationOf(g *Graph, stmt Subject) (Reification, bool) {
+	res := g.Resource(stmt)
+	subjs, preds, objs := res.Objects(rdfSubj), res.Objects(rdfPred), res.Objects(rdfObj)
+	if len(subjs) != 1 || len(preds) != 1 || len(objs) != 1 {
+		return Reification{}, false
+	}
+	subj, ok := subjs[0].(Subject)
+	if !ok {
+		return Reification{}, false
+	}
+	pred, ok := preds[0].(Predicate)
+	if !ok {
+		return Reification{}, false
+	}
+	return Reification{Statement: stmt, Triple: Triple{Subj: subj, Pred: pred, Obj: objs[0]}}, true
+}
+
+// isReificationTriple reports whether t is one of the reification triples of
+// the statement node.
+func isReificationTriple(t Triple, stmts map[termKey]Reification) bool {
+	if _, ok := stmts[keyOf(t.Subj)]; !ok {
+		return false
+	}
+	switch t.Pred {
+	case rdfSubj, rdfPred, rdfObj:
+		return true
+	case rdfType:
+		return TermsEqual(t.Obj, rdfStatement)
+	}
+	return false
+}
+
+// reificationIndex returns the reified statements in the graph by their
+// statement node.
+func reificationIndex(g *Graph) map[termKey]Reification {
+	stmts := make(map[termKey]Reification)
+	for _, r := range Reifications(g) {
+		stmts[keyOf(r.Statement)] = r
+	}
+	return stmts
+}
+
+// StripReifications removes the reification triples of the reified
+// statements from the graph, and returns the statements. Other triples about
+// the statement nodes are kept.
+func StripReifications(g *Graph) []Reification {
+	rs := Reifications(g)
+	stmts := reificationIndex(g)
+	var strip []Triple
+	for _, r := range rs {
+		for _, id := range g.withSubject(r.Statement) {
+			if t := g.triple(id); isReificationTriple(t, stmts) {
+				strip = append(strip, t)
+			}
+		}
+	}
+	g.Remove(strip...)
+	return rs
+}
+
+// ReificationsToStar returns the triples in the graph, with the reified
+// statements converted to RDF-star quoted triples. The reification triples are
+// dropped, and the statement nodes are replaced by the quoted triples they
+// describe, wherever they are the subject or object of other triples:
+//
+//  _:s rdf:subject ex:a ; rdf:predicate ex:b ; rdf:object ex:c ; ex:certainty 0.9 .
+//
+// becomes
+//
+//  << ex:a ex:b ex:c >> ex:certainty 0.9 .
+//
+// The identity of the statement nodes are lost in the conversion. The
+// triples are returned sorted by CompareTriples.
+func ReificationsToStar(g *Graph) []Triple {
+	stmts := reificationIndex(g)
+	var triples []Triple
+	for _, t := range g.Triples() {
+		if isReificationTriple(t, stmts) {
+			continue
+		}
+		if r, ok := stmts[keyOf(t.Subj)]; ok {
+			t.Subj = QuotedTriple{r.Triple}
+		}
+		if r, ok := stmts[keyOf(t.Obj)]; ok {
+			t.Obj = QuotedTriple{r.Triple}
+		}
+		triples = append(triples, t)
+	}
+	return triples
+}
+
+// StarToReifications returns the triples in the graph, with RDF-star quoted
+// triples converted to reified statements. Each distinct quoted triple is
+// replaced by a new blank node, for which the reification triples are added.
+// Quoted triples nested in quoted triples are converted as well.
+func StarToReifications(g *Graph) []Triple {
+	c := starConverter{
+		alloc: NewUUIDAllocator(),
+		nodes: make(map[termKey]Blank),
+	}
+	for _, t := range g.Triples() {
+		c.triples = append(c.triples, c.convert(t))
+	}
+	return c.triples
+}
+
+type starConverter struct {
+	alloc   BlankAllocator
+	nodes   map[termKey]Blank // statement nodes by quoted triple
+	triples []Triple
+}
+
+// convert returns the triple with any quoted triples replaced by statement nodes.
+func (c *starConverter) convert(t Triple) Triple {
+	if q, ok := t.Subj.(QuotedTriple); ok {
+		t.Subj = c.node(q)
+	}
+	if q, ok := t.Obj.(QuotedTriple); ok {
+		t.Obj = c.node(q)
+	}
+	return t
+}
+
+// node returns the statement node of the quoted triple, adding its
+// reification triples the first time.
+func (c *starConverter) node(q QuotedTriple) Blank {
+	k := keyOf(q)
+	if b, ok := c.nodes[k]; ok {
+		return b
+	}
+	b := Blank{id: "_:" + c.alloc.New()}
+	c.nodes[k] = b
+	c.triples = append(c.triples, Reify(b, c.convert(q.Triple))...)
+	return b
+}
+
+// ReificationsToQuads returns the triples in the graph as quads, with the
+// reified statements converted to named graphs; one graph per statement,
+// named by the statement node and holding the triple it describes. The
+// reification triples are dropped, and the other triples are placed in the
+// given default graph. The quads are returned sorted by CompareQuads.
+func ReificationsToQuads(g *Graph, defaultGraph Context) []Quad {
+	stmts := reificationIndex(g)
+	var quads []Quad
+	for _, r := range stmts {
+		quads = append(quads, Quad{Triple: r.Triple, Ctx: r.Statement})
+	}
+	for _, t := range g.Triples() {
+		if !isReificationTriple(t, stmts) {
+			quads = append(quads, Quad{Triple: t, Ctx: defaultGraph})
+		}
+	}
+	sort.Slice(quads, func(i, j int) bool { return CompareQuads(quads[i], quads[j]) < 0 })
+	return quads
+}
+
+// QuadsToReifications converts quads with one named graph per statement to
+// triples, as produced by ReificationsToQuads: the triple of each named graph
+// is reified with the graph name as statement node, while the quads in the
+// default graph are kept as triples.
+//
+// An error is returned if a named graph holds more than one triple.
+func QuadsToReifications(quads []Quad, defaultGraph Context) ([]Triple, error) {
+	var triples []Triple
+	named := make(map[termKey]Quad)
+	for _, q := range quads {
+		if q.Ctx == nil || (defaultGraph != nil && TermsEqual(q.Ctx, defaultGraph)) {
+			triples = append(triples, q.Triple)
+			continue
+		}
+		k := keyOf(q.Ctx)
+		if other, ok := named[k]; ok {
+			if !TriplesEqual(other.Triple, q.Triple) {
+				return nil, fmt.Errorf("graph %s holds more than one triple", q.Ctx.Serialize(NQuads))
+			}
+			continue
+		}
+		named[k] = q
+		triples = append(triples, Reify(q.Ctx, q.Triple)...)
+	}
+	return triples, nil
+}
@@ -0,0 +1,146 @@
+package rdf
+
+import (
+	"strings"
+	"testing"
+)
+
+const testReifyRDFXML = `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+         xmlns:ex="http://ex/"
+         xml:base="http://ex/doc">
+  <rdf:Description rdf:about="http://ex/a">
+    <ex:b rdf:ID="stmt" rdf:resource="http://ex/c"/>
+  </rdf:Description>
+  <rdf:Description rdf:about="#stmt">
+    <ex:certainty>0.9</ex:certainty>
+  </rdf:Description>
+</rdf:RDF>`
+
+func testReifyGraph(t *testing.T) *Graph {
+	triples, err := NewTripleDecoder(strings.NewReader(testReifyRDFXML), RDFXML).DecodeAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewGraph(triples...)
+}
+
+func TestReifications(t *testing.T) {
+	g := testReifyGraph(t)
+	abc := Triple{Subj: IRI{str: "http://ex/a"}, Pred: IRI{str: "http://ex/b"}, Obj: IRI{str: "http://ex/c"}}
+	stmt := IRI{str: "http://ex/doc#stmt"}
+
+	rs := Reifications(g)
+	if len(rs) != 1 || !TermsEqual(rs[0].Statement, stmt) || !TriplesEqual(rs[0].Triple, abc) {
+		t.Fatalf("Reifications => %v; want [{%v %v}]", rs, stmt, abc)
+	}
+
+	rs = StripReifications(g)
+	if len(rs) != 1 {
+		t.Fatalf("StripReifications => %v; want 1 statement", rs)
+	}
+	if g.Len() != 2 || !g.Has(abc) || len(g.Resource(stmt).Objects(IRI{str: "http://ex/certainty"})) != 1 {
+		t.Errorf("StripReifications left %v", g.Triples())
+	}
+	if rs := Reifications(g); len(rs) != 0 {
+		t.Errorf("Reifications after StripReifications => %v; want none", rs)
+	}
+}
+
+func TestReificationsToStar(t *testing.T) {
+	g := testReifyGraph(t)
+	abc := Triple{Subj: IRI{str: "http://ex/a"}, Pred: IRI{str: "http://ex/b"}, Obj: IRI{str: "http://ex/c"}}
+	certainty := IRI{str: "http://ex/certainty"}
+
+	star := ReificationsToStar(g)
+	want := []Triple{
+		abc,
+		{Subj: QuotedTriple{abc}, Pred: certainty, Obj: NewTypedLiteral("0.9", xsdString)},
+	}
+	if len(star) != len(want) {
+		t.Fatalf("ReificationsToStar => %v; want %v", star, want)
+	}
+	for i := range want {
+		if !TriplesEqual(star[i], want[i]) {
+			t.Errorf("ReificationsToStar => triple %d: %v; want %v", i, star[i], want[i])
+		}
+	}
+	if got, want := star[1].Serialize(NTriples), `<< <http://ex/a> <http://ex/b> <http://ex/c> >> <http://ex/certainty> "0.9" .`+"\n"; got != want {
+		t.Errorf("Serialize(NTriples) => %q; want %q", got, want)
+	}
+
+	// Converting back gives a blank statement node, with the same reification.
+	g = NewGraph(StarToReifications(NewGraph(star...))...)
+	if g.Len() != 6 {
+		t.Errorf("StarToReifications => %v; want 6 triples", g.Triples())
+	}
+	rs := Reifications(g)
+	if len(rs) != 1 || rs[0].Statement.Type() != TermBlank || !TriplesEqual(rs[0].Triple, abc) {
+		t.Fatalf("Reifications of StarToReifications => %v", rs)
+	}
+	if o, ok := g.Resource(rs[0].Statement).Object(certainty); !ok || o.String() != "0.9" {
+		t.Errorf("statement node lost its annotation: %v", g.Triples())
+	}
+}
+
+func TestStarToReificationsNested(t *testing.T) {
+	a, b, c := IRI{str: "http://ex/a"}, IRI{str: "http://ex/b"}, IRI{str: "http://ex/c"}
+	inner := QuotedTriple{Triple{Subj: a, Pred: b, Obj: c}}
+	outer := QuotedTriple{Triple{Subj: inner, Pred: b, Obj: c}}
+	g := NewGraph(
+		Triple{Subj: outer, Pred: a, Obj: inner},
+		Triple{Subj: a, Pred: a, Obj: inner},
+	)
+	triples := StarToReifications(g)
+	// 2 converted triples, plus 4 reification triples for each of inner and outer.
+	if len(triples) != 10 {
+		t.Fatalf("StarToReifications => %d triples; want 10:\n%v", len(triples), triples)
+	}
+	for _, tr := range triples {
+		if tr.Subj.Type() == TermQuotedTriple || tr.Obj.Type() == TermQuotedTriple {
+			t.Errorf("StarToReifications left quoted triple in %v", tr)
+		}
+	}
+	if rs := Reifications(NewGraph(triples...)); len(rs) != 2 {
+		t.Errorf("Reifications => %v; want 2", rs)
+	}
+}
+
+func TestReificationsToQuads(t *testing.T) {
+	g := testReifyGraph(t)
+	stmt := IRI{str: "http://ex/doc#stmt"}
+	abc := Triple{Subj: IRI{str: "http://ex/a"}, Pred: IRI{str: "http://ex/b"}, Obj: IRI{str: "http://ex/c"}}
+	def := Blank{id: "_:defaultGraph"}
+
+	quads := ReificationsToQuads(g, def)
+	if len(quads) != 3 {
+		t.Fatalf("ReificationsToQuads => %v; want 3 quads", quads)
+	}
+	var named int
+	for _, q := range quads {
+		if TermsEqual(q.Ctx, stmt) {
+			named++
+			if !TriplesEqual(q.Triple, abc) {
+				t.Errorf("graph %v holds %v; want %v", stmt, q.Triple, abc)
+			}
+		} else if !TermsEqual(q.Ctx, def) {
+			t.Errorf("quad %v in unexpected graph", q)
+		}
+	}
+	if named != 1 {
+		t.Errorf("ReificationsToQuads => %d quads in graph %v; want 1", named, stmt)
+	}
+
+	triples, err := QuadsToReifications(quads, def)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back := NewGraph(triples...); back.Len() != g.Len() {
+		t.Errorf("QuadsToReifications => %v; want %v", back.Triples(), g.Triples())
+	}
+
+	quads = append(quads, Quad{Triple: Triple{Subj: stmt, Pred: stmt, Obj: stmt}, Ctx: stmt})
+	if _, err := QuadsToReifications(quads, def); err == nil || !strings.HasSuffix(err.Error(), "holds more than one triple") {
+		t.Errorf("QuadsToReifications with two triples in a graph => %v; want error", err)
+	}
+}
@@ -0,0 +1,196 @@
+package rdf
+
+import "strings"
+
+// Resource is a view of a subject in a Graph, with methods to navigate the
+// triples describing it:
+//
+//  book := g.Resource(bookIRI)
+//  title, ok := book.Literal(dcterms.Title, "nb", "en")
+//  for _, author := range book.Follow(dcterms.Creator, foaf.Name) {
+//  	...
+//  }
+//
+// The methods return objects in the order their triples were added to the graph.
+type Resource struct {
+	Subject Subject
+	Graph   *Graph
+}
+
+// Objects returns the objects of the triples with the resource as subject and
+// the given predicate.
+func (r Resource) Objects(p Predicate) []Object {
+	pid, ok := r.Graph.dict.lookupOK(p)
+	if !ok {
+		return nil
+	}
+	var objs []Object
+	for _, id := range r.Graph.withSubject(r.Subject) {
+		if id.Pred == pid {
+			objs = append(objs, r.Graph.term(id.Obj).(Object))
+		}
+	}
+	return objs
+}
+
+// Object returns the first object of the triples with the resource as subject
+// and the given predicate, or false if there are none.
+func (r Resource) Object(p Predicate) (Object, bool) {
+	pid, ok := r.Graph.dict.lookupOK(p)
+	if !ok {
+		return nil, false
+	}
+	for _, id := range r.Graph.withSubject(r.Subject) {
+		if id.Pred == pid {
+			return r.Graph.term(id.Obj).(Object), true
+		}
+	}
+	return nil, false
+}
+
+// Literal returns a literal object of the resource with the given predicate,
+// or false if there is none.
+//
+// Without language preferences the first literal is returned. Otherwise the
+// languages are tried in order, and the first literal matching a language is
+// returned. A language matches literals with the same language tag, or with
+// tags starting with the language followed by '-', case-insensitively;
+// so "en" matches "en" and "en-GB", but not "eng". The empty language
+// matches literals without language tag, and "*" matches any literal:
+//
+//  title, ok := r.Literal(dcterms.Title, "nb", "nn", "en", "", "*")
+func (r Resource) Literal(p Predicate, langPrefs ...string) (Literal, bool) {
+	lits := r.Literals(p)
+	if len(lits) == 0 {
+		return Literal{}, false
+	}
+	if len(langPrefs) == 0 {
+		return lits[0], true
+	}
+	for _, lang := range langPrefs {
+		for _, l := range lits {
+			if langMatches(l.lang, lang) {
+				return l, true
+			}
+		}
+	}
+	return Literal{}, false
+}
+
+// langMatches reports whether the language tag matches the language range,
+// following the basic filtering of RFC 4647, section 3.3.1.
+func langMatches(tag, lang string) bool {
+	switch {
+	case lang == "*":
+		return true
+	case len(tag) == len(lang):
+		return strings.EqualFold(tag, lang)
+	case lang == "":
+		return false
+	}
+	return len(tag) > len(lang) && tag[len(lang)] == '-' && strings.EqualFold(tag[:len(lang)], lang)
+}
+
+// Literals returns the literal objects of the resource with the given predicate.
+func (r Resource) Literals(p Predicate) []Literal {
+	var lits []Literal
+	for _, o := range r.Objects(p) {
+		if l, ok := o.(Literal); ok {
+			lits = append(lits, l)
+		}
+	}
+	return lits
+}
+
+// IRIs returns the IRI objects of the resource with the given predicate.
+func (r Resource) IRIs(p Predicate) []IRI {
+	var iris []IRI
+	for _, o := range r.Objects(p) {
+		if u, ok := o.(IRI); ok {
+			iris = append(iris, u)
+		}
+	}
+	return iris
+}
+
+// Types returns the rdf:type IRIs of the resource.
+func (r Resource) Types() []IRI {
+	return r.IRIs(rdfType)
+}
+
+// HasType returns true if the resource has the given rdf:type.
+func (r Resource) HasType(typ IRI) bool {
+	for _, t := range r.Types() {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// Resources returns the IRI and blank node objects of the resource with the
+// given predicate, as resources in the same graph.
+func (r Resource) Resources(p Predicate) []Resource {
+	var res []Resource
+	for _, o := range r.Objects(p) {
+		if s, ok := o.(Subject); ok {
+			res = append(res, Resource{Subject: s, Graph: r.Graph})
+		}
+	}
+	return res
+}
+
+// Inverse returns the subjects of the triples with the given predicate and the
+// resource as object, as resources in the same graph.
+func (r Resource) Inverse(p Predicate) []Resource {
+	obj, ok := r.Subject.(Object)
+	if !ok {
+		return nil
+	}
+	pid, ok := r.Graph.dict.lookupOK(p)
+	if !ok {
+		return nil
+	}
+	var res []Resource
+	for _, id := range r.Graph.withObject(obj) {
+		if id.Pred == pid {
+			res = append(res, Resource{Subject: r.Graph.term(id.Subj).(Subject), Graph: r.Graph})
+		}
+	}
+	return res
+}
+
+// Follow returns the objects reached from the resource by following the
+// predicates of the path in turn; i.e. the objects of the last predicate, for
+// the objects of the predicate before it, and so on. Only IRIs and blank nodes
+// are followed along the path, while the objects returned may be any term.
+// Each object is only returned once. Without a path, the resource's subject
+// is returned.
+//
+//  // The names of the authors of the book:
+//  names := book.Follow(dcterms.Creator, foaf.Name)
+func (r Resource) Follow(path ...Predicate) []Object {
+	start, ok := r.Subject.(Object)
+	if !ok {
+		return nil
+	}
+	objs := []Object{start}
+	for _, p := range path {
+		var next []Object
+		seen := make(map[termKey]bool)
+		for _, o := range objs {
+			s, ok := o.(Subject)
+			if !ok {
+				continue
+			}
+			for _, o := range r.Graph.Resource(s).Objects(p) {
+				if k := keyOf(o); !seen[k] {
+					seen[k] = true
+					next = append(next, o)
+				}
+			}
+		}
+		objs = next
+	}
+	return objs
+}
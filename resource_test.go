@@ -0,0 +1,91 @@
+package rdf
+
+import (
+	"strings"
+	"testing"
+)
+
+const testResourceGraph = `
+@prefix dc: <http://purl.org/dc/terms/> .
+@prefix foaf: <http://xmlns.com/foaf/0.1/> .
+@prefix ex: <http://ex/> .
+
+ex:book a ex:Book, ex:Novel ;
+	dc:title "Moby Dick"@en, "Moby Dick"@nb-NO, "Moby-Dick" ;
+	dc:issued 1851 ;
+	dc:creator ex:melville, [ foaf:name "Anonymous" ] .
+
+ex:melville foaf:name "Herman Melville" .
+ex:review dc:subject ex:book .
+`
+
+func TestResource(t *testing.T) {
+	triples, err := NewTripleDecoder(strings.NewReader(testResourceGraph), Turtle).DecodeAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGraph(triples...)
+	book := g.Resource(IRI{str: "http://ex/book"})
+	title := IRI{str: "http://purl.org/dc/terms/title"}
+	creator := IRI{str: "http://purl.org/dc/terms/creator"}
+	name := IRI{str: "http://xmlns.com/foaf/0.1/name"}
+
+	literalTests := []struct {
+		langs []string
+		want  string
+		ok    bool
+	}{
+		{nil, "Moby Dick@en", true},
+		{[]string{"nb"}, "Moby Dick@nb-NO", true},
+		{[]string{"NB-no"}, "Moby Dick@nb-NO", true},
+		{[]string{"nn", "en"}, "Moby Dick@en", true},
+		{[]string{""}, "Moby-Dick@", true},
+		{[]string{"de", "*"}, "Moby Dick@en", true},
+		{[]string{"n"}, "", false},
+		{[]string{"de"}, "", false},
+	}
+	for _, tt := range literalTests {
+		l, ok := book.Literal(title, tt.langs...)
+		if got := l.String() + "@" + l.Lang(); ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("Literal(%v, %q) => %q, %v; want %q, %v", title, tt.langs, got, ok, tt.want, tt.ok)
+		}
+	}
+	if _, ok := book.Literal(IRI{str: "http://ex/missing"}); ok {
+		t.Error("Literal of missing predicate => true; want false")
+	}
+
+	if types := book.Types(); len(types) != 2 || types[0].str != "http://ex/Book" || types[1].str != "http://ex/Novel" {
+		t.Errorf("Types() => %v; want [http://ex/Book http://ex/Novel]", types)
+	}
+	if !book.HasType(IRI{str: "http://ex/Novel"}) || book.HasType(IRI{str: "http://ex/Film"}) {
+		t.Error("HasType gave wrong answer")
+	}
+	if objs := book.Objects(creator); len(objs) != 2 {
+		t.Errorf("Objects(%v) => %v; want 2 objects", creator, objs)
+	}
+	if iris := book.IRIs(creator); len(iris) != 1 || iris[0].str != "http://ex/melville" {
+		t.Errorf("IRIs(%v) => %v; want [http://ex/melville]", creator, iris)
+	}
+	if o, ok := book.Object(IRI{str: "http://purl.org/dc/terms/issued"}); !ok || !TermsEqual(o, NewTypedLiteral("1851", xsdInteger)) {
+		t.Errorf("Object(dc:issued) => %v, %v; want 1851", o, ok)
+	}
+
+	names := book.Follow(creator, name)
+	if len(names) != 2 || names[0].(Literal).str != "Herman Melville" || names[1].(Literal).str != "Anonymous" {
+		t.Errorf("Follow(%v, %v) => %v", creator, name, names)
+	}
+	if objs := book.Follow(title, name); len(objs) != 0 {
+		t.Errorf("Follow through literals => %v; want none", objs)
+	}
+	if objs := book.Follow(); len(objs) != 1 || !TermsEqual(objs[0], book.Subject) {
+		t.Errorf("Follow() => %v; want [%v]", objs, book.Subject)
+	}
+
+	melville := book.Resources(creator)[0]
+	if res := melville.Inverse(creator); len(res) != 1 || !TermsEqual(res[0].Subject, book.Subject) {
+		t.Errorf("Inverse(%v) => %v; want [%v]", creator, res, book.Subject)
+	}
+	if res := book.Inverse(IRI{str: "http://purl.org/dc/terms/subject"}); len(res) != 1 || res[0].Subject.String() != "http://ex/review" {
+		t.Errorf("Inverse(dc:subject) => %v; want [http://ex/review]", res)
+	}
+}
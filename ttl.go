@@ -1,11 +1,11 @@
 package rdf
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"iter"
 	"runtime"
-	"strconv"
-	"time"
 )
 
 type ttlDecoder struct {
@@ -14,9 +14,13 @@ type ttlDecoder struct {
 	state     parseFn           // state of parser
 	base      IRI               // base (default IRI)
 	bnodeN    int               // anonymous blank node counter
+	bnodes    BlankAllocator    // blank node label allocator, if any
+	dict      *Dictionary       // dictionary to intern IRIs in, if any
+	errs      errSink           // handling of malformed input
 	ns        map[string]string // map[prefix]namespace
 	tokens    [3]token          // 3 token lookahead
 	peekCount int               // number of tokens peeked at (position in tokens lookahead array)
+	last      token             // the token consumed last
 	current   ctxTriple         // the current triple beeing parsed
 
 	// ctxStack keeps track of current and parent triple contexts,
@@ -28,13 +32,15 @@ type ttlDecoder struct {
 	triples []Triple
 }
 
-func newTTLDecoder(r io.Reader) *ttlDecoder {
-	return &ttlDecoder{
-		l:        newLexer(r),
+func newTTLDecoder(ctx context.Context, r io.Reader) *ttlDecoder {
+	d := &ttlDecoder{
+		l:        newLexer(ctx, r),
 		ns:       make(map[string]string),
 		ctxStack: make([]ctxTriple, 0, 8),
 		triples:  make([]Triple, 0, 4),
 	}
+	d.l.lim.format = Turtle
+	return d
 }
 
 // SetOption sets a ParseOption to the give value
@@ -46,14 +52,51 @@ func (d *ttlDecoder) SetOption(o ParseOption, v interface{}) error {
 			return fmt.Errorf("ParseOption \"Base\" must be an IRI.")
 		}
 		d.base = iri
+	case BlankNodes:
+		a, ok := v.(BlankAllocator)
+		if !ok {
+			return fmt.Errorf("ParseOption \"BlankNodes\" must be a BlankAllocator.")
+		}
+		d.bnodes = a
+	case Intern:
+		dict, ok := v.(*Dictionary)
+		if !ok {
+			return fmt.Errorf("ParseOption \"Intern\" must be a *Dictionary.")
+		}
+		d.dict = dict
+	case Strict, ErrOut:
+		return d.errs.setOption(o, v)
+	case MaxLiteralLen, MaxIRILen, MaxLineLen, MaxNesting, MaxTriples:
+		return d.l.lim.setOption(o, v)
 	default:
-		return fmt.Errorf("RDF/XML decoder doesn't support option: %v", o)
+		return fmt.Errorf("Turtle decoder doesn't support option: %v", o)
 	}
 	return nil
 }
 
 // Decode parses a Turtle document, and returns the next valid triple, or an error.
-func (d *ttlDecoder) Decode() (t Triple, err error) {
+func (d *ttlDecoder) Decode() (Triple, error) {
+	for {
+		if err := d.l.err(); err != nil {
+			return Triple{}, err
+		}
+		t, err := d.decode()
+		if err == nil {
+			if err = d.l.lim.countTriple(); err != nil {
+				return Triple{}, err
+			}
+			return t, nil
+		}
+		if !d.errs.skip(err) {
+			return t, err
+		}
+		d.skipStatement()
+	}
+}
+
+// decode returns the next triple in the pipeline, parsing more of the
+// document when the pipeline is empty.
+func (d *ttlDecoder) decode() (t Triple, err error) {
 	defer d.recover(&err)
 
 	// Check if there is allready a triple in the pipeline:
@@ -78,7 +121,7 @@ func (d *ttlDecoder) Decode() (t Triple, err error) {
 	}
 
 done:
-	t = d.triples[0]
+	t = d.dict.internIRIs(d.triples[0])
 	d.triples = d.triples[1:]
 	return t, err
 }
@@ -96,38 +139,79 @@ func (d *ttlDecoder) DecodeAll() ([]Triple, error) {
 	return ts, nil
 }
 
+// All returns an iterator over the remaining triples of the document.
+func (d *ttlDecoder) All() iter.Seq2[Triple, error] {
+	return decodeSeq(d.Decode)
+}
+
+// Prefixes returns the prefixes declared in the document so far, with
+// their namespaces.
+func (d *ttlDecoder) Prefixes() map[string]string {
+	m := make(map[string]string, len(d.ns))
+	for prefix, ns := range d.ns {
+		if prefix == ":" {
+			// The empty prefix is stored as ':'
+			prefix = ""
+		}
+		m[prefix] = ns
+	}
+	return m
+}
+
+// Base returns the base IRI last declared in the document, or the one
+// given by the Base option.
+func (d *ttlDecoder) Base() IRI {
+	return d.base
+}
+
+// Close stops the decoder.
+func (d *ttlDecoder) Close() error {
+	d.l.stop()
+	return nil
+}
+
 // parseStart parses top context
 func parseStart(d *ttlDecoder) parseFn {
 	switch d.next().typ {
 	case tokenPrefix:
 		label := d.expect1As("prefix label", tokenPrefixLabel)
-		if label.text == "" {
+		if string(label.text) == "" {
 			println("empty label")
 		}
 		tok := d.expectAs("prefix IRI", tokenIRIAbs, tokenIRIRel)
 		if tok.typ == tokenIRIRel {
 			// Resolve against document base IRI
-			d.ns[label.text] = d.base.str + tok.text
+			d.ns[string(label.text)] = d.resolve(string(tok.text))
 		} else {
-			d.ns[label.text] = tok.text
+			d.ns[string(label.text)] = string(tok.text)
 		}
 		d.expect1As("directive trailing dot", tokenDot)
 	case tokenSparqlPrefix:
 		label := d.expect1As("prefix label", tokenPrefixLabel)
-		uri := d.expect1As("prefix IRI", tokenIRIAbs)
-		d.ns[label.text] = uri.text
+		tok := d.expectAs("prefix IRI", tokenIRIAbs, tokenIRIRel)
+		if tok.typ == tokenIRIRel {
+			// Resolve against document base IRI
+			d.ns[string(label.text)] = d.resolve(string(tok.text))
+		} else {
+			d.ns[string(label.text)] = string(tok.text)
+		}
 	case tokenBase:
 		tok := d.expectAs("base IRI", tokenIRIAbs, tokenIRIRel)
 		if tok.typ == tokenIRIRel {
 			// Resolve against document base IRI
-			d.base.str = d.base.str + tok.text
+			d.base.str = d.resolve(string(tok.text))
 		} else {
-			d.base.str = tok.text
+			d.base.str = string(tok.text)
 		}
 		d.expect1As("directive trailing dot", tokenDot)
 	case tokenSparqlBase:
-		uri := d.expect1As("base IRI", tokenIRIAbs)
-		d.base.str = uri.text
+		tok := d.expectAs("base IRI", tokenIRIAbs, tokenIRIRel)
+		if tok.typ == tokenIRIRel {
+			// Resolve against document base IRI
+			d.base.str = d.resolve(string(tok.text))
+		} else {
+			d.base.str = string(tok.text)
+		}
 	case tokenEOF:
 		return nil
 	default:
@@ -152,7 +236,7 @@ func parseEnd(d *ttlDecoder) parseFn {
 		case tokenEOF:
 			// trailing semicolon without final dot not allowed
 			// TODO only allowed in property lists?
-			d.errorf("%d:%d: expected triple termination, got %v", tok.line, tok.col, tok.typ)
+			d.errorf(tok, UnexpectedToken, "expected triple termination, got %v", tok.typ)
 			return nil
 		}
 		d.current.Pred = nil
@@ -179,8 +263,8 @@ func parseEnd(d *ttlDecoder) parseFn {
 		return parseEnd
 	case tokenCollectionEnd:
 		// Emit collection closing triple { bnode rdf:rest rdf:nil }
-		d.current.Pred = IRI{str: "http://www.w3.org/1999/02/22-rdf-syntax-ns#rest"}
-		d.current.Obj = IRI{str: "http://www.w3.org/1999/02/22-rdf-syntax-ns#nil"}
+		d.current.Pred = rdfRest
+		d.current.Obj = rdfNil
 		d.emit()
 
 		// Restore parent triple
@@ -198,24 +282,23 @@ func parseEnd(d *ttlDecoder) parseFn {
 		}
 		return nil
 	case tokenError:
-		d.errorf("%d:%d: syntax error: %v", tok.line, tok.col, tok.text)
+		d.errorf(tok, SyntaxError, "syntax error: %s", string(tok.text))
 		return nil
 	default:
 		if d.current.Ctx == ctxColl {
 			d.backup() // unread collection item, to be parsed on next iteration
 
-			d.bnodeN++
-			d.current.Pred = IRI{str: "http://www.w3.org/1999/02/22-rdf-syntax-ns#rest"}
-			d.current.Obj = Blank{id: fmt.Sprintf("_:b%d", d.bnodeN)}
+			d.current.Pred = rdfRest
+			d.current.Obj = d.newBlank()
 			d.emit()
 
 			d.current.Subj = d.current.Obj.(Subject)
 			d.current.Obj = nil
-			d.current.Pred = IRI{str: "http://www.w3.org/1999/02/22-rdf-syntax-ns#first"}
+			d.current.Pred = rdfFirst
 			d.pushContext()
 			return nil
 		}
-		d.errorf("%d:%d: expected triple termination, got %v", tok.line, tok.col, tok.typ)
+		d.errorf(tok, UnexpectedToken, "expected triple termination, got %v", tok.typ)
 		return nil
 	}
 
@@ -235,43 +318,40 @@ func parseSubject(d *ttlDecoder) parseFn {
 	tok := d.next()
 	switch tok.typ {
 	case tokenIRIAbs:
-		d.current.Subj = IRI{str: tok.text}
+		d.current.Subj = IRI{str: string(tok.text)}
 	case tokenIRIRel:
-		d.current.Subj = IRI{str: d.base.str + tok.text}
+		d.current.Subj = IRI{str: d.resolve(string(tok.text))}
 	case tokenBNode:
-		d.current.Subj = Blank{id: tok.text}
+		d.current.Subj = d.blank(string(tok.text))
 	case tokenAnonBNode:
-		d.bnodeN++
-		d.current.Subj = Blank{id: fmt.Sprintf("_:b%d", d.bnodeN)}
+		d.current.Subj = d.newBlank()
 	case tokenPrefixLabel:
-		ns, ok := d.ns[tok.text]
+		ns, ok := d.ns[string(tok.text)]
 		if !ok {
-			d.errorf("missing namespace for prefix: '%s'", tok.text)
+			d.errorf(tok, UndefinedPrefix, "missing namespace for prefix: '%s'", string(tok.text))
 		}
 		suf := d.expect1As("IRI suffix", tokenIRISuffix)
-		d.current.Subj = IRI{str: ns + suf.text}
+		d.current.Subj = IRI{str: ns + string(suf.text)}
 	case tokenPropertyListStart:
 		// Blank node is subject of a new triple
-		d.bnodeN++
-		d.current.Subj = Blank{id: fmt.Sprintf("_:b%d", d.bnodeN)}
+		d.current.Subj = d.newBlank()
 		d.pushContext() // Subj = bnode, top context
 		d.current.Ctx = ctxList
 	case tokenCollectionStart:
 		if d.peek().typ == tokenCollectionEnd {
 			// An empty collection
-			d.current.Subj = IRI{str: "http://www.w3.org/1999/02/22-rdf-syntax-ns#nil"}
+			d.current.Subj = rdfNil
 			break
 		}
-		d.bnodeN++
-		d.current.Subj = Blank{id: fmt.Sprintf("_:b%d", d.bnodeN)}
+		d.current.Subj = d.newBlank()
 		d.pushContext()
-		d.current.Pred = IRI{str: "http://www.w3.org/1999/02/22-rdf-syntax-ns#first"}
+		d.current.Pred = rdfFirst
 		d.current.Ctx = ctxColl
 		return parseObject
 	case tokenError:
-		d.errorf("%d:%d: syntax error: %v", tok.line, tok.col, tok.text)
+		d.errorf(tok, SyntaxError, "syntax error: %s", string(tok.text))
 	default:
-		d.errorf("unexpected %v as subject", tok.typ)
+		d.errorf(tok, UnexpectedToken, "unexpected %v as subject", tok.typ)
 	}
 
 	return parsePredicate
@@ -284,22 +364,22 @@ func parsePredicate(d *ttlDecoder) parseFn {
 	tok := d.next()
 	switch tok.typ {
 	case tokenIRIAbs:
-		d.current.Pred = IRI{str: tok.text}
+		d.current.Pred = IRI{str: string(tok.text)}
 	case tokenIRIRel:
-		d.current.Pred = IRI{str: d.base.str + tok.text}
+		d.current.Pred = IRI{str: d.resolve(string(tok.text))}
 	case tokenRDFType:
-		d.current.Pred = IRI{str: "http://www.w3.org/1999/02/22-rdf-syntax-ns#type"}
+		d.current.Pred = rdfType
 	case tokenPrefixLabel:
-		ns, ok := d.ns[tok.text]
+		ns, ok := d.ns[string(tok.text)]
 		if !ok {
-			d.errorf("missing namespace for prefix: '%s'", tok.text)
+			d.errorf(tok, UndefinedPrefix, "missing namespace for prefix: '%s'", string(tok.text))
 		}
 		suf := d.expect1As("IRI suffix", tokenIRISuffix)
-		d.current.Pred = IRI{str: ns + suf.text}
+		d.current.Pred = IRI{str: ns + string(suf.text)}
 	case tokenError:
-		d.errorf("%d:%d: syntax error: %v", tok.line, tok.col, tok.text)
+		d.errorf(tok, SyntaxError, "syntax error: %s", string(tok.text))
 	default:
-		d.errorf("%d:%d: unexpected %v as predicate", tok.line, tok.col, tok.typ)
+		d.errorf(tok, UnexpectedToken, "unexpected %v as predicate", tok.typ)
 	}
 
 	return parseObject
@@ -309,16 +389,15 @@ func parseObject(d *ttlDecoder) parseFn {
 	tok := d.next()
 	switch tok.typ {
 	case tokenIRIAbs:
-		d.current.Obj = IRI{str: tok.text}
+		d.current.Obj = IRI{str: string(tok.text)}
 	case tokenIRIRel:
-		d.current.Obj = IRI{str: d.base.str + tok.text}
+		d.current.Obj = IRI{str: d.resolve(string(tok.text))}
 	case tokenBNode:
-		d.current.Obj = Blank{id: tok.text}
+		d.current.Obj = d.blank(string(tok.text))
 	case tokenAnonBNode:
-		d.bnodeN++
-		d.current.Obj = Blank{id: fmt.Sprintf("_:b%d", d.bnodeN)}
+		d.current.Obj = d.newBlank()
 	case tokenLiteral, tokenLiteral3:
-		val := tok.text
+		val := string(tok.text)
 		l := Literal{
 			str:      val,
 			DataType: xsdString,
@@ -328,58 +407,59 @@ func parseObject(d *ttlDecoder) parseFn {
 		case tokenLangMarker:
 			d.next() // consume peeked token
 			tok = d.expect1As("literal language", tokenLang)
-			l.lang = tok.text
+			l.lang = d.langTag(tok)
 			l.DataType = rdfLangString
 		case tokenDataTypeMarker:
 			d.next() // consume peeked token
-			tok = d.expectAs("literal datatype", tokenIRIAbs, tokenPrefixLabel)
+			tok = d.expectAs("literal datatype", tokenIRIAbs, tokenIRIRel, tokenPrefixLabel)
 			switch tok.typ {
 			case tokenIRIAbs:
-				l.DataType = IRI{str: tok.text}
+				l.DataType = IRI{str: string(tok.text)}
+			case tokenIRIRel:
+				l.DataType = IRI{str: d.resolve(string(tok.text))}
 			case tokenPrefixLabel:
-				ns, ok := d.ns[tok.text]
+				ns, ok := d.ns[string(tok.text)]
 				if !ok {
-					d.errorf("missing namespace for prefix: '%s'", tok.text)
+					d.errorf(tok, UndefinedPrefix, "missing namespace for prefix: '%s'", string(tok.text))
 				}
 				tok2 := d.expect1As("IRI suffix", tokenIRISuffix)
-				l.DataType = IRI{str: ns + tok2.text}
+				l.DataType = IRI{str: ns + string(tok2.text)}
 			}
 		}
 		d.current.Obj = l
 	case tokenLiteralDouble:
 		d.current.Obj = Literal{
-			str:      tok.text,
+			str:      string(tok.text),
 			DataType: xsdDouble,
 		}
 	case tokenLiteralDecimal:
 		d.current.Obj = Literal{
-			str:      tok.text,
+			str:      string(tok.text),
 			DataType: xsdDecimal,
 		}
 	case tokenLiteralInteger:
 		d.current.Obj = Literal{
-			str:      tok.text,
+			str:      string(tok.text),
 			DataType: xsdInteger,
 		}
 	case tokenLiteralBoolean:
 		d.current.Obj = Literal{
-			str:      tok.text,
+			str:      string(tok.text),
 			DataType: xsdBoolean,
 		}
 	case tokenPrefixLabel:
-		ns, ok := d.ns[tok.text]
+		ns, ok := d.ns[string(tok.text)]
 		if !ok {
-			d.errorf("missing namespace for prefix: '%s'", tok.text)
+			d.errorf(tok, UndefinedPrefix, "missing namespace for prefix: '%s'", string(tok.text))
 		}
 		suf := d.expect1As("IRI suffix", tokenIRISuffix)
-		d.current.Obj = IRI{str: ns + suf.text}
+		d.current.Obj = IRI{str: ns + string(suf.text)}
 	case tokenPropertyListStart:
 		// Blank node is object of current triple
 		// Save current context, to be restored after the list ends
 		d.pushContext()
 
-		d.bnodeN++
-		d.current.Obj = Blank{id: fmt.Sprintf("_:b%d", d.bnodeN)}
+		d.current.Obj = d.newBlank()
 		d.emit()
 
 		// Set blank node as subject of the next triple. Push to stack and return.
@@ -393,26 +473,25 @@ func parseObject(d *ttlDecoder) parseFn {
 		if d.peek().typ == tokenCollectionEnd {
 			// an empty collection
 			d.next() // consume ')'
-			d.current.Obj = IRI{str: "http://www.w3.org/1999/02/22-rdf-syntax-ns#nil"}
+			d.current.Obj = rdfNil
 			break
 		}
 		// Blank node is object of current triple
 		// Save current context, to be restored after the collection ends
 		d.pushContext()
 
-		d.bnodeN++
-		d.current.Obj = Blank{id: fmt.Sprintf("_:b%d", d.bnodeN)}
+		d.current.Obj = d.newBlank()
 		d.emit()
 		d.current.Subj = d.current.Obj.(Subject)
-		d.current.Pred = IRI{str: "http://www.w3.org/1999/02/22-rdf-syntax-ns#first"}
+		d.current.Pred = rdfFirst
 		d.current.Obj = nil
 		d.current.Ctx = ctxColl
 		d.pushContext()
 		return nil
 	case tokenError:
-		d.errorf("%d:%d: syntax error: %v", tok.line, tok.col, tok.text)
+		d.errorf(tok, SyntaxError, "syntax error: %s", string(tok.text))
 	default:
-		d.errorf("%d:%d: unexpected %v as object", tok.line, tok.col, tok.typ)
+		d.errorf(tok, UnexpectedToken, "unexpected %v as object", tok.typ)
 	}
 
 	// We now have a full tripe, emit it.
@@ -421,9 +500,62 @@ func parseObject(d *ttlDecoder) parseFn {
 	return parseEnd
 }
 
+// newBlank returns a new anonymous blank node.
+func (d *ttlDecoder) newBlank() Blank {
+	if d.bnodes != nil {
+		return Blank{id: "_:" + d.bnodes.New()}
+	}
+	d.bnodeN++
+	return Blank{id: fmt.Sprintf("_:b%d", d.bnodeN)}
+}
+
+// blank returns the blank node with the given label (including the '_:' prefix),
+// scoped to the document if a BlankAllocator is set.
+func (d *ttlDecoder) blank(label string) Blank {
+	if d.bnodes == nil {
+		return Blank{id: label}
+	}
+	return Blank{id: "_:" + d.bnodes.Scope(label[2:])}
+}
+
+// langTag returns the language tag of the token in canonical case.
+func (d *ttlDecoder) langTag(tok token) string {
+	lang, err := parseLangTag(string(tok.text))
+	if err != nil {
+		e := parseErrorAt(Turtle, tok, InvalidTerm, "bad literal: %v", err)
+		e.Err = err
+		panic(e)
+	}
+	return lang
+}
+
+// skipStatement skips the remaining tokens of a malformed statement, and
+// discards the triples parsed from it. Parsing resumes after the '.' which
+// terminates the statement.
+func (d *ttlDecoder) skipStatement() {
+	d.triples = d.triples[:0]
+	d.ctxStack = d.ctxStack[:0]
+	d.current = ctxTriple{}
+
+	for tok := d.last; tok.typ != tokenDot && tok.typ != tokenEOF; {
+		tok = d.next()
+	}
+}
+
+// resolve resolves a relative IRI reference against the document base IRI.
+func (d *ttlDecoder) resolve(ref string) string {
+	return resolveIRI(d.base.str, ref)
+}
+
 // pushContext pushes the current triple and context to the context stack.
+// The stack holds one context more than the nesting depth of collections and
+// property lists.
 func (d *ttlDecoder) pushContext() {
 	d.ctxStack = append(d.ctxStack, d.current)
+	if max := d.l.lim.nesting; max > 0 && len(d.ctxStack)-1 > max {
+		tok := d.last
+		panic(d.l.lim.errorAt(ErrNestingTooDeep, tok.line, tok.col, tok.off, []byte(tok.lexeme())))
+	}
 }
 
 // popContext restores the next context on the stack as the current context.
@@ -456,8 +588,8 @@ func (d *ttlDecoder) next() token {
 	} else {
 		d.tokens[0] = d.l.nextToken()
 	}
-
-	return d.tokens[d.peekCount]
+	d.last = d.tokens[d.peekCount]
+	return d.last
 }
 
 // peek returns but does not consume the next token.
@@ -493,15 +625,14 @@ func (d *ttlDecoder) backup3(t2, t1 token) {
 // parseFn represents the state of the parser as a function that returns the next state.
 type parseFn func(*ttlDecoder) parseFn
 
-// errorf formats the error and terminates parsing.
-func (d *ttlDecoder) errorf(format string, args ...interface{}) {
-	format = fmt.Sprintf("%s", format)
-	panic(fmt.Errorf(format, args...))
+// errorf formats the error, located at the given token, and terminates parsing.
+func (d *ttlDecoder) errorf(tok token, kind ErrorKind, format string, args ...interface{}) {
+	panic(parseErrorAt(Turtle, tok, kind, format, args...))
 }
 
 // unexpected complains about the given token and terminates parsing.
 func (d *ttlDecoder) unexpected(t token, context string) {
-	d.errorf("%d:%d unexpected %v as %s", t.line, t.col, t.typ, context)
+	d.errorf(t, UnexpectedToken, "unexpected %v as %s", t.typ, context)
 }
 
 // recover catches non-runtime panics and binds the panic error
@@ -524,7 +655,7 @@ func (d *ttlDecoder) expect1As(context string, expected tokenType) token {
 	t := d.next()
 	if t.typ != expected {
 		if t.typ == tokenError {
-			d.errorf("%d:%d: syntax error: %s", t.line, t.col, t.text)
+			d.errorf(t, SyntaxError, "syntax error: %s", string(t.text))
 		} else {
 			d.unexpected(t, context)
 		}
@@ -541,72 +672,29 @@ func (d *ttlDecoder) expectAs(context string, expected ...tokenType) token {
 		}
 	}
 	if t.typ == tokenError {
-		d.errorf("syntax error: %s", t.text)
+		d.errorf(t, SyntaxError, "syntax error: %s", string(t.text))
 	} else {
 		d.unexpected(t, context)
 	}
 	return t
 }
 
-// parseLiteral
-func parseLiteral(val, datatype string) (interface{}, error) {
-	switch datatype {
-	case xsdString.str:
-		return val, nil
-	case xsdInteger.str:
-		i, err := strconv.Atoi(val)
-		if err != nil {
-			return nil, err
-		}
-		return i, nil
-	case xsdFloat.str, xsdDouble.str, xsdDecimal.str:
-		f, err := strconv.ParseFloat(val, 64)
-		if err != nil {
-			return nil, err
-		}
-		return f, nil
-	case xsdBoolean.str:
-		bo, err := strconv.ParseBool(val)
-		if err != nil {
-			return nil, err
-		}
-		return bo, nil
-	case xsdDateTime.str:
-		t, err := time.Parse(DateFormat, val)
-		if err != nil {
-			// Unfortunately, xsd:dateTime allows dates without timezone information
-			// Try parse again unspecified timzeone (defaulting to UTC)
-			t, err = time.Parse("2006-01-02T15:04:05", val)
-			if err != nil {
-				return nil, err
-			}
-			return t, nil
-		}
-		return t, nil
-	case xsdByte.str:
-		return []byte(val), nil
-		// TODO: other xsd dataypes that maps to Go data types
-	default:
-		return val, nil
-	}
-}
-
 // ctxTriple contains a Triple, plus the context in which the Triple appears.
 type ctxTriple struct {
 	Triple
-	Ctx context
+	Ctx ctxType
 }
 
-type context int
+type ctxType int
 
 const (
-	ctxTop context = iota
+	ctxTop ctxType = iota
 	ctxColl
 	ctxList
 )
 
 // TODO remove when done
-func (ctx context) String() string {
+func (ctx ctxType) String() string {
 	switch ctx {
 	case ctxTop:
 		return "top context"
@@ -316,7 +316,7 @@ ns1:spiderman	ns0:name	"Spiderman" .`,
 ns0:218	ns0:blurb	"This is a multi-line\nliteral with many quotes (\"\"\"\"\")\nand up to two sequential apostrophes ('')." ;
 	ns0:localName	"That Seventies Show"@en ,
 			"Cette Série des Années Soixante-dix"@fr ,
-			"Cette Série des Années Septante"@fr-be .
+			"Cette Série des Années Septante"@fr-BE .
 @prefix ns1:	<http://www.w3.org/2000/01/rdf-schema#> .
 ns0:218	ns1:label	"That Seventies Show" .`,
 
@@ -354,41 +354,41 @@ _:b	ns0:knows	_:c ;
 	ns0:name	"Bob" .
 _:c	ns0:name	"Eve" .`,
 
-	`@prefix rdf:	<http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
-@prefix ns0:	<http://example.org/> .
-ns0:foosubject	ns0:foopredicate2	rdf:nil ;
-	ns0:foopredicate	_:b1 .
+	`@prefix ns0:	<http://example.org/> .
+@prefix rdf:	<http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
 _:b1	rdf:first	ns0:fooa ;
 	rdf:rest	_:b2 .
 _:b2	rdf:first	ns0:foob ;
 	rdf:rest	_:b3 .
 _:b3	rdf:first	ns0:fooc ;
-	rdf:rest	rdf:nil .`,
+	rdf:rest	rdf:nil .
+ns0:foosubject	ns0:foopredicate	_:b1 ;
+	ns0:foopredicate2	rdf:nil .`,
 
 	`@prefix ns0:	<http://example.org/stuff/1.0/> .
-@prefix ns1:	<http://www.w3.org/TR/> .
-ns1:rdf-syntax-grammar	ns0:editor	_:b1 .
-@prefix ns2:	<http://purl.org/dc/elements/1.1/> .
-ns1:rdf-syntax-grammar	ns2:title	"RDF/XML Syntax Specification (Revised)" .
 _:b1	ns0:fullname	"Dave Beckett" .
-@prefix ns3:	<http://purl.org/net/dajobe/> .
-_:b1	ns0:homePage	ns3: .`,
+@prefix ns1:	<http://purl.org/net/dajobe/> .
+_:b1	ns0:homePage	ns1: .
+@prefix ns2:	<http://www.w3.org/TR/> .
+ns2:rdf-syntax-grammar	ns0:editor	_:b1 .
+@prefix ns3:	<http://purl.org/dc/elements/1.1/> .
+ns2:rdf-syntax-grammar	ns3:title	"RDF/XML Syntax Specification (Revised)" .`,
 
-	`@prefix ns0:	<http://example.org/stuff/1.0/> .
-ns0:a	ns0:b	_:b1 .
-@prefix rdf:	<http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+	`@prefix rdf:	<http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
 _:b1	rdf:first	"apple" ;
 	rdf:rest	_:b2 .
 _:b2	rdf:first	"banana" ;
-	rdf:rest	rdf:nil .`,
+	rdf:rest	rdf:nil .
+@prefix ns0:	<http://example.org/stuff/1.0/> .
+ns0:a	ns0:b	_:b1 .`,
 
-	`@prefix ns0:	<http://example.org/stuff/1.0/> .
-ns0:a	ns0:b	_:b1 .
-@prefix rdf:	<http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+	`@prefix rdf:	<http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
 _:b1	rdf:first	"apple" ;
 	rdf:rest	_:b2 .
 _:b2	rdf:first	"banana" ;
-	rdf:rest	rdf:nil .`,
+	rdf:rest	rdf:nil .
+@prefix ns0:	<http://example.org/stuff/1.0/> .
+ns0:a	ns0:b	_:b1 .`,
 
 	`@prefix ns0:	<http://example.org/stuff/1.0/> .
 ns0:a	ns0:b	"The first line\nThe second line\n  more" .`,
@@ -439,14 +439,14 @@ _:b4	rdf:first	2 ;
 	rdf:rest	rdf:nil .`,
 
 	`@prefix ns0:	<http://xmlns.com/foaf/0.1/> .
+_:b1	ns0:mbox	<mailto:timbl@w3.org> .
 @prefix ns1:	<http://www.w3.org/People/Eric/ericP-foaf.rdf#> .
 ns1:ericP	ns0:givenName	"Eric" .
 @prefix ns2:	<http://norman.walsh.name/knows/who/> .
 ns1:ericP	ns0:knows	ns2:dan-brickley ,
 			_:b1 .
 @prefix ns3:	<http://getopenid.com/> .
-ns1:ericP	ns0:knows	ns3:amyvdh .
-_:b1	ns0:mbox	<mailto:timbl@w3.org> .`,
+ns1:ericP	ns0:knows	ns3:amyvdh .`,
 
 	`@prefix ns0:	<http://books.example.com/product-types/> .
 @prefix ns1:	<http://purl.org/dc/terms/> .
@@ -473,6 +473,7 @@ func BenchmarkDecodeTTL(b *testing.B) {
 	for _, i := range ttlBenchInputs {
 		bf.WriteString(i)
 	}
+	b.ReportAllocs()
 	b.ResetTimer()
 	for n := 0; n < b.N; n++ {
 		dec := NewTripleDecoder(bytes.NewReader(bf.Bytes()), Turtle)
@@ -1758,7 +1759,7 @@ p:a·̀ͯ‿.⁀ <http://a.example/p> <http://a.example/o> .`, "", []Triple{
 		Triple{
 			Subj: IRI{str: "http://a.example/s"},
 			Pred: IRI{str: "http://a.example/p"},
-			Obj: Literal{str: `	`, DataType: xsdString},
+			Obj:  Literal{str: `	`, DataType: xsdString},
 		},
 	}},
 
@@ -1774,7 +1775,7 @@ p:a·̀ͯ‿.⁀ <http://a.example/p> <http://a.example/o> .`, "", []Triple{
 		Triple{
 			Subj: IRI{str: "http://a.example/s"},
 			Pred: IRI{str: "http://a.example/p"},
-			Obj: Literal{str: "", DataType: xsdString},
+			Obj:  Literal{str: "", DataType: xsdString},
 		},
 	}},
 
@@ -2176,7 +2177,7 @@ p:a·̀ͯ‿.⁀ <http://a.example/p> <http://a.example/o> .`, "", []Triple{
 		Triple{
 			Subj: IRI{str: "http://a.example/s"},
 			Pred: IRI{str: "http://a.example/p"},
-			Obj:  Literal{str: "chat", lang: "en-us", DataType: rdfLangString},
+			Obj:  Literal{str: "chat", lang: "en-US", DataType: rdfLangString},
 		},
 	}},
 
@@ -2386,6 +2387,17 @@ p:a·̀ͯ‿.⁀ <http://a.example/p> <http://a.example/o> .`, "", []Triple{
 		},
 	}},
 
+	// Relative datatype IRI, resolved against the base IRI.
+
+	{`@base <http://ex/> .
+<a> <b> "x"^^<dt> .`, "", []Triple{
+		Triple{
+			Subj: IRI{str: "http://ex/a"},
+			Pred: IRI{str: "http://ex/b"},
+			Obj:  Literal{str: "x", DataType: IRI{str: "http://ex/dt"}},
+		},
+	}},
+
 	//<#turtle-syntax-base-04> rdf:type rdft:TestTurtlePositiveSyntax ;
 	//   mf:name    "turtle-syntax-base-04" ;
 	//   rdfs:comment "base with relative IRIs" ;
@@ -2579,7 +2591,7 @@ x:_  x:p_1 x:o .`, "", []Triple{
 		Triple{
 			Subj: IRI{str: "http://www.w3.org/2013/TurtleTests/s"},
 			Pred: IRI{str: "http://www.w3.org/2013/TurtleTests/p"},
-			Obj:  Literal{str: "string", DataType: rdfLangString, lang: "en-uk"},
+			Obj:  Literal{str: "string", DataType: rdfLangString, lang: "en-UK"},
 		},
 	}},
 
@@ -2624,7 +2636,7 @@ x:_  x:p_1 x:o .`, "", []Triple{
 		Triple{
 			Subj: IRI{str: "http://www.w3.org/2013/TurtleTests/s"},
 			Pred: IRI{str: "http://www.w3.org/2013/TurtleTests/p"},
-			Obj:  Literal{str: "string", DataType: rdfLangString, lang: "en-uk"},
+			Obj:  Literal{str: "string", DataType: rdfLangString, lang: "en-UK"},
 		},
 	}},
 
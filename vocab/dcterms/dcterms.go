@@ -0,0 +1,130 @@
+// Package dcterms provides the terms of the DCMI Metadata Terms
+// (https://www.dublincore.org/specifications/dublin-core/dcmi-terms/), as rdf.IRI values.
+package dcterms
+
+import (
+	"github.com/knakk/rdf"
+	"github.com/knakk/rdf/internal/ns"
+)
+
+// NS is the namespace IRI of the vocabulary.
+const NS = ns.DCTerms
+
+// Classes:
+var (
+	Agent                        = iri("Agent")
+	AgentClass                   = iri("AgentClass")
+	BibliographicResource        = iri("BibliographicResource")
+	FileFormat                   = iri("FileFormat")
+	Frequency                    = iri("Frequency")
+	Jurisdiction                 = iri("Jurisdiction")
+	LicenseDocument              = iri("LicenseDocument")
+	LinguisticSystem             = iri("LinguisticSystem")
+	Location                     = iri("Location")
+	LocationPeriodOrJurisdiction = iri("LocationPeriodOrJurisdiction")
+	MediaType                    = iri("MediaType")
+	MediaTypeOrExtent            = iri("MediaTypeOrExtent")
+	MethodOfAccrual              = iri("MethodOfAccrual")
+	MethodOfInstruction          = iri("MethodOfInstruction")
+	PeriodOfTime                 = iri("PeriodOfTime")
+	PhysicalMedium               = iri("PhysicalMedium")
+	PhysicalResource             = iri("PhysicalResource")
+	Policy                       = iri("Policy")
+	ProvenanceStatement          = iri("ProvenanceStatement")
+	RightsStatement              = iri("RightsStatement")
+	SizeOrDuration               = iri("SizeOrDuration")
+	Standard                     = iri("Standard")
+)
+
+// Properties:
+var (
+	Abstract              = iri("abstract")
+	AccessRights          = iri("accessRights")
+	AccrualMethod         = iri("accrualMethod")
+	AccrualPeriodicity    = iri("accrualPeriodicity")
+	AccrualPolicy         = iri("accrualPolicy")
+	Alternative           = iri("alternative")
+	Audience              = iri("audience")
+	Available             = iri("available")
+	BibliographicCitation = iri("bibliographicCitation")
+	ConformsTo            = iri("conformsTo")
+	Contributor           = iri("contributor")
+	Coverage              = iri("coverage")
+	Created               = iri("created")
+	Creator               = iri("creator")
+	Date                  = iri("date")
+	DateAccepted          = iri("dateAccepted")
+	DateCopyrighted       = iri("dateCopyrighted")
+	DateSubmitted         = iri("dateSubmitted")
+	Description           = iri("description")
+	EducationLevel        = iri("educationLevel")
+	Extent                = iri("extent")
+	Format                = iri("format")
+	HasFormat             = iri("hasFormat")
+	HasPart               = iri("hasPart")
+	HasVersion            = iri("hasVersion")
+	Identifier            = iri("identifier")
+	InstructionalMethod   = iri("instructionalMethod")
+	IsFormatOf            = iri("isFormatOf")
+	IsPartOf              = iri("isPartOf")
+	IsReferencedBy        = iri("isReferencedBy")
+	IsReplacedBy          = iri("isReplacedBy")
+	IsRequiredBy          = iri("isRequiredBy")
+	IsVersionOf           = iri("isVersionOf")
+	Issued                = iri("issued")
+	Language              = iri("language")
+	License               = iri("license")
+	Mediator              = iri("mediator")
+	Medium                = iri("medium")
+	Modified              = iri("modified")
+	Provenance            = iri("provenance")
+	Publisher             = iri("publisher")
+	References            = iri("references")
+	Relation              = iri("relation")
+	Replaces              = iri("replaces")
+	Requires              = iri("requires")
+	Rights                = iri("rights")
+	RightsHolder          = iri("rightsHolder")
+	Source                = iri("source")
+	Spatial               = iri("spatial")
+	Subject               = iri("subject")
+	TableOfContents       = iri("tableOfContents")
+	Temporal              = iri("temporal")
+	Title                 = iri("title")
+	Type                  = iri("type")
+	Valid                 = iri("valid")
+)
+
+// Vocabulary encoding schemes and syntax encoding schemes:
+var (
+	Box      = iri("Box")
+	DCMIType = iri("DCMIType")
+	DDC      = iri("DDC")
+	IMT      = iri("IMT")
+	ISO3166  = iri("ISO3166")
+	ISO639_2 = iri("ISO639-2")
+	ISO639_3 = iri("ISO639-3")
+	LCC      = iri("LCC")
+	LCSH     = iri("LCSH")
+	MESH     = iri("MESH")
+	NLM      = iri("NLM")
+	Period   = iri("Period")
+	Point    = iri("Point")
+	RFC1766  = iri("RFC1766")
+	RFC3066  = iri("RFC3066")
+	RFC4646  = iri("RFC4646")
+	RFC5646  = iri("RFC5646")
+	TGN      = iri("TGN")
+	UDC      = iri("UDC")
+	URI      = iri("URI")
+	W3CDTF   = iri("W3CDTF")
+)
+
+// iri returns the IRI of the term with the given local name in the vocabulary.
+func iri(local string) rdf.IRI {
+	u, err := rdf.NewIRI(NS + local)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
@@ -0,0 +1,12 @@
+// Package vocab is the parent of packages providing the terms of commonly
+// used RDF vocabularies as rdf.IRI values, e.g.:
+//
+//  import "github.com/knakk/rdf/vocab/rdfs"
+//
+//  rdf.Triple{Subj: s, Pred: rdfs.Label, Obj: o}
+//
+// The Go name of a term is its local name with the first letter in upper case,
+// and any '-' or '.' replaced by '_'. If this makes the name of a property
+// clash with the name of a class (e.g. schema:text and schema:Text), the
+// property name gets the suffix "Property" (schema.TextProperty).
+package vocab
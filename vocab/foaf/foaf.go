@@ -0,0 +1,103 @@
+// Package foaf provides the terms of the Friend of a Friend vocabulary
+// (http://xmlns.com/foaf/spec/), as rdf.IRI values.
+package foaf
+
+import (
+	"github.com/knakk/rdf"
+	"github.com/knakk/rdf/internal/ns"
+)
+
+// NS is the namespace IRI of the vocabulary.
+const NS = ns.FOAF
+
+// Classes:
+var (
+	Agent                   = iri("Agent")
+	Document                = iri("Document")
+	Group                   = iri("Group")
+	Image                   = iri("Image")
+	LabelProperty           = iri("LabelProperty")
+	OnlineAccount           = iri("OnlineAccount")
+	OnlineChatAccount       = iri("OnlineChatAccount")
+	OnlineEcommerceAccount  = iri("OnlineEcommerceAccount")
+	OnlineGamingAccount     = iri("OnlineGamingAccount")
+	Organization            = iri("Organization")
+	Person                  = iri("Person")
+	PersonalProfileDocument = iri("PersonalProfileDocument")
+	Project                 = iri("Project")
+)
+
+// Properties:
+var (
+	Account                = iri("account")
+	AccountName            = iri("accountName")
+	AccountServiceHomepage = iri("accountServiceHomepage")
+	Age                    = iri("age")
+	AimChatID              = iri("aimChatID")
+	Based_near             = iri("based_near")
+	Birthday               = iri("birthday")
+	CurrentProject         = iri("currentProject")
+	Depiction              = iri("depiction")
+	Depicts                = iri("depicts")
+	DnaChecksum            = iri("dnaChecksum")
+	FamilyName             = iri("familyName")
+	Family_name            = iri("family_name")
+	FirstName              = iri("firstName")
+	Focus                  = iri("focus")
+	FundedBy               = iri("fundedBy")
+	Geekcode               = iri("geekcode")
+	Gender                 = iri("gender")
+	GivenName              = iri("givenName")
+	Givenname              = iri("givenname")
+	HoldsAccount           = iri("holdsAccount")
+	Homepage               = iri("homepage")
+	IcqChatID              = iri("icqChatID")
+	Img                    = iri("img")
+	Interest               = iri("interest")
+	IsPrimaryTopicOf       = iri("isPrimaryTopicOf")
+	JabberID               = iri("jabberID")
+	Knows                  = iri("knows")
+	LastName               = iri("lastName")
+	Logo                   = iri("logo")
+	Made                   = iri("made")
+	Maker                  = iri("maker")
+	Mbox                   = iri("mbox")
+	Mbox_sha1sum           = iri("mbox_sha1sum")
+	Member                 = iri("member")
+	MembershipClass        = iri("membershipClass")
+	MsnChatID              = iri("msnChatID")
+	MyersBriggs            = iri("myersBriggs")
+	Name                   = iri("name")
+	Nick                   = iri("nick")
+	Openid                 = iri("openid")
+	Page                   = iri("page")
+	PastProject            = iri("pastProject")
+	Phone                  = iri("phone")
+	Plan                   = iri("plan")
+	PrimaryTopic           = iri("primaryTopic")
+	Publications           = iri("publications")
+	SchoolHomepage         = iri("schoolHomepage")
+	Sha1                   = iri("sha1")
+	SkypeID                = iri("skypeID")
+	Status                 = iri("status")
+	Surname                = iri("surname")
+	Theme                  = iri("theme")
+	Thumbnail              = iri("thumbnail")
+	Tipjar                 = iri("tipjar")
+	Title                  = iri("title")
+	Topic                  = iri("topic")
+	Topic_interest         = iri("topic_interest")
+	Weblog                 = iri("weblog")
+	WorkInfoHomepage       = iri("workInfoHomepage")
+	WorkplaceHomepage      = iri("workplaceHomepage")
+	YahooChatID            = iri("yahooChatID")
+)
+
+// iri returns the IRI of the term with the given local name in the vocabulary.
+func iri(local string) rdf.IRI {
+	u, err := rdf.NewIRI(NS + local)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
@@ -0,0 +1,105 @@
+// Package owl provides the terms of the OWL 2 Web Ontology Language vocabulary
+// (http://www.w3.org/TR/owl2-syntax/), as rdf.IRI values.
+package owl
+
+import (
+	"github.com/knakk/rdf"
+	"github.com/knakk/rdf/internal/ns"
+)
+
+// NS is the namespace IRI of the vocabulary.
+const NS = ns.OWL
+
+// Classes:
+var (
+	AllDifferent              = iri("AllDifferent")
+	AllDisjointClasses        = iri("AllDisjointClasses")
+	AllDisjointProperties     = iri("AllDisjointProperties")
+	Annotation                = iri("Annotation")
+	AnnotationProperty        = iri("AnnotationProperty")
+	AsymmetricProperty        = iri("AsymmetricProperty")
+	Axiom                     = iri("Axiom")
+	Class                     = iri("Class")
+	DataRange                 = iri("DataRange")
+	DatatypeProperty          = iri("DatatypeProperty")
+	DeprecatedClass           = iri("DeprecatedClass")
+	DeprecatedProperty        = iri("DeprecatedProperty")
+	FunctionalProperty        = iri("FunctionalProperty")
+	InverseFunctionalProperty = iri("InverseFunctionalProperty")
+	IrreflexiveProperty       = iri("IrreflexiveProperty")
+	NamedIndividual           = iri("NamedIndividual")
+	NegativePropertyAssertion = iri("NegativePropertyAssertion")
+	Nothing                   = iri("Nothing")
+	ObjectProperty            = iri("ObjectProperty")
+	Ontology                  = iri("Ontology")
+	OntologyProperty          = iri("OntologyProperty")
+	ReflexiveProperty         = iri("ReflexiveProperty")
+	Restriction               = iri("Restriction")
+	SymmetricProperty         = iri("SymmetricProperty")
+	Thing                     = iri("Thing")
+	TransitiveProperty        = iri("TransitiveProperty")
+)
+
+// Properties:
+var (
+	AllValuesFrom           = iri("allValuesFrom")
+	AnnotatedProperty       = iri("annotatedProperty")
+	AnnotatedSource         = iri("annotatedSource")
+	AnnotatedTarget         = iri("annotatedTarget")
+	AssertionProperty       = iri("assertionProperty")
+	BackwardCompatibleWith  = iri("backwardCompatibleWith")
+	BottomDataProperty      = iri("bottomDataProperty")
+	BottomObjectProperty    = iri("bottomObjectProperty")
+	Cardinality             = iri("cardinality")
+	ComplementOf            = iri("complementOf")
+	DatatypeComplementOf    = iri("datatypeComplementOf")
+	Deprecated              = iri("deprecated")
+	DifferentFrom           = iri("differentFrom")
+	DisjointUnionOf         = iri("disjointUnionOf")
+	DisjointWith            = iri("disjointWith")
+	DistinctMembers         = iri("distinctMembers")
+	EquivalentClass         = iri("equivalentClass")
+	EquivalentProperty      = iri("equivalentProperty")
+	HasKey                  = iri("hasKey")
+	HasSelf                 = iri("hasSelf")
+	HasValue                = iri("hasValue")
+	Imports                 = iri("imports")
+	IncompatibleWith        = iri("incompatibleWith")
+	IntersectionOf          = iri("intersectionOf")
+	InverseOf               = iri("inverseOf")
+	MaxCardinality          = iri("maxCardinality")
+	MaxQualifiedCardinality = iri("maxQualifiedCardinality")
+	Members                 = iri("members")
+	MinCardinality          = iri("minCardinality")
+	MinQualifiedCardinality = iri("minQualifiedCardinality")
+	OnClass                 = iri("onClass")
+	OnDataRange             = iri("onDataRange")
+	OnDatatype              = iri("onDatatype")
+	OnProperties            = iri("onProperties")
+	OnProperty              = iri("onProperty")
+	OneOf                   = iri("oneOf")
+	PriorVersion            = iri("priorVersion")
+	PropertyChainAxiom      = iri("propertyChainAxiom")
+	PropertyDisjointWith    = iri("propertyDisjointWith")
+	QualifiedCardinality    = iri("qualifiedCardinality")
+	SameAs                  = iri("sameAs")
+	SomeValuesFrom          = iri("someValuesFrom")
+	SourceIndividual        = iri("sourceIndividual")
+	TargetIndividual        = iri("targetIndividual")
+	TargetValue             = iri("targetValue")
+	TopDataProperty         = iri("topDataProperty")
+	TopObjectProperty       = iri("topObjectProperty")
+	UnionOf                 = iri("unionOf")
+	VersionIRI              = iri("versionIRI")
+	VersionInfo             = iri("versionInfo")
+	WithRestrictions        = iri("withRestrictions")
+)
+
+// iri returns the IRI of the term with the given local name in the vocabulary.
+func iri(local string) rdf.IRI {
+	u, err := rdf.NewIRI(NS + local)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
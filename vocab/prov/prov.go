@@ -0,0 +1,108 @@
+// Package prov provides the terms of the PROV Ontology
+// (http://www.w3.org/TR/prov-o/), as rdf.IRI values.
+package prov
+
+import (
+	"github.com/knakk/rdf"
+	"github.com/knakk/rdf/internal/ns"
+)
+
+// NS is the namespace IRI of the vocabulary.
+const NS = ns.PROV
+
+// Classes:
+var (
+	Activity           = iri("Activity")
+	ActivityInfluence  = iri("ActivityInfluence")
+	Agent              = iri("Agent")
+	AgentInfluence     = iri("AgentInfluence")
+	Association        = iri("Association")
+	Attribution        = iri("Attribution")
+	Bundle             = iri("Bundle")
+	Collection         = iri("Collection")
+	Communication      = iri("Communication")
+	Delegation         = iri("Delegation")
+	Derivation         = iri("Derivation")
+	EmptyCollection    = iri("EmptyCollection")
+	End                = iri("End")
+	Entity             = iri("Entity")
+	EntityInfluence    = iri("EntityInfluence")
+	Generation         = iri("Generation")
+	Influence          = iri("Influence")
+	InstantaneousEvent = iri("InstantaneousEvent")
+	Invalidation       = iri("Invalidation")
+	Location           = iri("Location")
+	Organization       = iri("Organization")
+	Person             = iri("Person")
+	Plan               = iri("Plan")
+	PrimarySource      = iri("PrimarySource")
+	Quotation          = iri("Quotation")
+	Revision           = iri("Revision")
+	Role               = iri("Role")
+	SoftwareAgent      = iri("SoftwareAgent")
+	Start              = iri("Start")
+	Usage              = iri("Usage")
+)
+
+// Properties:
+var (
+	ActedOnBehalfOf        = iri("actedOnBehalfOf")
+	ActivityProperty       = iri("activity")
+	AgentProperty          = iri("agent")
+	AlternateOf            = iri("alternateOf")
+	AtLocation             = iri("atLocation")
+	AtTime                 = iri("atTime")
+	EndedAtTime            = iri("endedAtTime")
+	EntityProperty         = iri("entity")
+	Generated              = iri("generated")
+	GeneratedAtTime        = iri("generatedAtTime")
+	HadActivity            = iri("hadActivity")
+	HadGeneration          = iri("hadGeneration")
+	HadMember              = iri("hadMember")
+	HadPlan                = iri("hadPlan")
+	HadPrimarySource       = iri("hadPrimarySource")
+	HadRole                = iri("hadRole")
+	HadUsage               = iri("hadUsage")
+	Influenced             = iri("influenced")
+	Influencer             = iri("influencer")
+	Invalidated            = iri("invalidated")
+	InvalidatedAtTime      = iri("invalidatedAtTime")
+	QualifiedAssociation   = iri("qualifiedAssociation")
+	QualifiedAttribution   = iri("qualifiedAttribution")
+	QualifiedCommunication = iri("qualifiedCommunication")
+	QualifiedDelegation    = iri("qualifiedDelegation")
+	QualifiedDerivation    = iri("qualifiedDerivation")
+	QualifiedEnd           = iri("qualifiedEnd")
+	QualifiedGeneration    = iri("qualifiedGeneration")
+	QualifiedInfluence     = iri("qualifiedInfluence")
+	QualifiedInvalidation  = iri("qualifiedInvalidation")
+	QualifiedPrimarySource = iri("qualifiedPrimarySource")
+	QualifiedQuotation     = iri("qualifiedQuotation")
+	QualifiedRevision      = iri("qualifiedRevision")
+	QualifiedStart         = iri("qualifiedStart")
+	QualifiedUsage         = iri("qualifiedUsage")
+	SpecializationOf       = iri("specializationOf")
+	StartedAtTime          = iri("startedAtTime")
+	Used                   = iri("used")
+	Value                  = iri("value")
+	WasAssociatedWith      = iri("wasAssociatedWith")
+	WasAttributedTo        = iri("wasAttributedTo")
+	WasDerivedFrom         = iri("wasDerivedFrom")
+	WasEndedBy             = iri("wasEndedBy")
+	WasGeneratedBy         = iri("wasGeneratedBy")
+	WasInfluencedBy        = iri("wasInfluencedBy")
+	WasInformedBy          = iri("wasInformedBy")
+	WasInvalidatedBy       = iri("wasInvalidatedBy")
+	WasQuotedFrom          = iri("wasQuotedFrom")
+	WasRevisionOf          = iri("wasRevisionOf")
+	WasStartedBy           = iri("wasStartedBy")
+)
+
+// iri returns the IRI of the term with the given local name in the vocabulary.
+func iri(local string) rdf.IRI {
+	u, err := rdf.NewIRI(NS + local)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
@@ -0,0 +1,54 @@
+// Package rdf provides the terms of the RDF vocabulary
+// (http://www.w3.org/TR/rdf11-schema/), as rdf.IRI values.
+package rdf
+
+import (
+	"github.com/knakk/rdf"
+	"github.com/knakk/rdf/internal/ns"
+)
+
+// NS is the namespace IRI of the vocabulary.
+const NS = ns.RDF
+
+// Classes:
+var (
+	Alt             = iri("Alt")
+	Bag             = iri("Bag")
+	CompoundLiteral = iri("CompoundLiteral")
+	HTML            = iri("HTML")
+	JSON            = iri("JSON")
+	List            = iri("List")
+	PlainLiteral    = iri("PlainLiteral")
+	Property        = iri("Property")
+	Seq             = iri("Seq")
+	Statement       = iri("Statement")
+	XMLLiteral      = iri("XMLLiteral")
+	LangString      = iri("langString")
+)
+
+// Properties:
+var (
+	Direction = iri("direction")
+	First     = iri("first")
+	Language  = iri("language")
+	Object    = iri("object")
+	Predicate = iri("predicate")
+	Rest      = iri("rest")
+	Subject   = iri("subject")
+	Type      = iri("type")
+	Value     = iri("value")
+)
+
+// Individuals:
+var (
+	Nil = iri("nil")
+)
+
+// iri returns the IRI of the term with the given local name in the vocabulary.
+func iri(local string) rdf.IRI {
+	u, err := rdf.NewIRI(NS + local)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
@@ -0,0 +1,37 @@
+package rdf_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/knakk/rdf"
+	rdfvocab "github.com/knakk/rdf/vocab/rdf"
+)
+
+func TestCoreTerms(t *testing.T) {
+	input := `<http://ex/s> a <http://ex/C> ; <http://ex/p> ( "x"@en ) .`
+	triples, err := rdf.NewTripleDecoder(bytes.NewBufferString(input), rdf.Turtle).DecodeAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(triples) != 4 {
+		t.Fatalf("decoding %s => %d triples; want 4", input, len(triples))
+	}
+	preds := make(map[rdf.IRI]bool)
+	for _, tr := range triples {
+		preds[tr.Pred.(rdf.IRI)] = true
+	}
+	for _, p := range []rdf.IRI{rdfvocab.Type, rdfvocab.First, rdfvocab.Rest} {
+		if !preds[p] {
+			t.Errorf("decoding %s => no triple with predicate %v", input, p)
+		}
+	}
+	for _, tr := range triples {
+		if l, ok := tr.Obj.(rdf.Literal); ok && l.DataType != rdfvocab.LangString {
+			t.Errorf("decoding %s => literal datatype %v; want %v", input, l.DataType, rdfvocab.LangString)
+		}
+		if tr.Pred == rdfvocab.Rest && tr.Obj != rdfvocab.Nil {
+			t.Errorf("decoding %s => list rest %v; want %v", input, tr.Obj, rdfvocab.Nil)
+		}
+	}
+}
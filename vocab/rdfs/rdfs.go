@@ -0,0 +1,43 @@
+// Package rdfs provides the terms of the RDF Schema vocabulary
+// (http://www.w3.org/TR/rdf11-schema/), as rdf.IRI values.
+package rdfs
+
+import (
+	"github.com/knakk/rdf"
+	"github.com/knakk/rdf/internal/ns"
+)
+
+// NS is the namespace IRI of the vocabulary.
+const NS = ns.RDFS
+
+// Classes:
+var (
+	Class                       = iri("Class")
+	Container                   = iri("Container")
+	ContainerMembershipProperty = iri("ContainerMembershipProperty")
+	Datatype                    = iri("Datatype")
+	Literal                     = iri("Literal")
+	Resource                    = iri("Resource")
+)
+
+// Properties:
+var (
+	Comment       = iri("comment")
+	Domain        = iri("domain")
+	IsDefinedBy   = iri("isDefinedBy")
+	Label         = iri("label")
+	Member        = iri("member")
+	Range         = iri("range")
+	SeeAlso       = iri("seeAlso")
+	SubClassOf    = iri("subClassOf")
+	SubPropertyOf = iri("subPropertyOf")
+)
+
+// iri returns the IRI of the term with the given local name in the vocabulary.
+func iri(local string) rdf.IRI {
+	u, err := rdf.NewIRI(NS + local)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
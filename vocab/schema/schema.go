@@ -0,0 +1,180 @@
+// Package schema provides the terms of a selection of commonly used terms from the Schema.org vocabulary
+// (https://schema.org/), as rdf.IRI values.
+package schema
+
+import (
+	"github.com/knakk/rdf"
+	"github.com/knakk/rdf/internal/ns"
+)
+
+// NS is the namespace IRI of the vocabulary.
+const NS = ns.Schema
+
+// Types:
+var (
+	Action              = iri("Action")
+	AggregateRating     = iri("AggregateRating")
+	Article             = iri("Article")
+	Book                = iri("Book")
+	BreadcrumbList      = iri("BreadcrumbList")
+	Brand               = iri("Brand")
+	City                = iri("City")
+	ContactPoint        = iri("ContactPoint")
+	Country             = iri("Country")
+	CreativeWork        = iri("CreativeWork")
+	DataCatalog         = iri("DataCatalog")
+	DataDownload        = iri("DataDownload")
+	Dataset             = iri("Dataset")
+	Event               = iri("Event")
+	GeoCoordinates      = iri("GeoCoordinates")
+	ImageObject         = iri("ImageObject")
+	Intangible          = iri("Intangible")
+	ItemList            = iri("ItemList")
+	JobPosting          = iri("JobPosting")
+	Language            = iri("Language")
+	ListItem            = iri("ListItem")
+	LocalBusiness       = iri("LocalBusiness")
+	MediaObject         = iri("MediaObject")
+	Movie               = iri("Movie")
+	MusicRecording      = iri("MusicRecording")
+	Offer               = iri("Offer")
+	Organization        = iri("Organization")
+	Person              = iri("Person")
+	Place               = iri("Place")
+	PostalAddress       = iri("PostalAddress")
+	Product             = iri("Product")
+	PropertyValue       = iri("PropertyValue")
+	QuantitativeValue   = iri("QuantitativeValue")
+	Rating              = iri("Rating")
+	Recipe              = iri("Recipe")
+	Review              = iri("Review")
+	SoftwareApplication = iri("SoftwareApplication")
+	StructuredValue     = iri("StructuredValue")
+	Thing               = iri("Thing")
+	WebPage             = iri("WebPage")
+	WebSite             = iri("WebSite")
+)
+
+// Data types:
+var (
+	Boolean  = iri("Boolean")
+	Date     = iri("Date")
+	DateTime = iri("DateTime")
+	Float    = iri("Float")
+	Integer  = iri("Integer")
+	Number   = iri("Number")
+	Text     = iri("Text")
+	Time     = iri("Time")
+	URL      = iri("URL")
+)
+
+// Properties:
+var (
+	About                   = iri("about")
+	AdditionalType          = iri("additionalType")
+	Address                 = iri("address")
+	AddressCountry          = iri("addressCountry")
+	AddressLocality         = iri("addressLocality")
+	AddressRegion           = iri("addressRegion")
+	Affiliation             = iri("affiliation")
+	AggregateRatingProperty = iri("aggregateRating")
+	AlternateName           = iri("alternateName")
+	Audience                = iri("audience")
+	Author                  = iri("author")
+	Award                   = iri("award")
+	BestRating              = iri("bestRating")
+	BirthDate               = iri("birthDate")
+	BookFormat              = iri("bookFormat")
+	BrandProperty           = iri("brand")
+	Category                = iri("category")
+	Children                = iri("children")
+	Citation                = iri("citation")
+	ContactPointProperty    = iri("contactPoint")
+	ContentUrl              = iri("contentUrl")
+	Contributor             = iri("contributor")
+	CopyrightHolder         = iri("copyrightHolder")
+	CopyrightYear           = iri("copyrightYear")
+	Creator                 = iri("creator")
+	DateCreated             = iri("dateCreated")
+	DateModified            = iri("dateModified")
+	DatePublished           = iri("datePublished")
+	DeathDate               = iri("deathDate")
+	Description             = iri("description")
+	Distribution            = iri("distribution")
+	Duration                = iri("duration")
+	Editor                  = iri("editor")
+	Email                   = iri("email")
+	Encoding                = iri("encoding")
+	EncodingFormat          = iri("encodingFormat")
+	EndDate                 = iri("endDate")
+	FamilyName              = iri("familyName")
+	Founder                 = iri("founder")
+	FoundingDate            = iri("foundingDate")
+	Gender                  = iri("gender")
+	Genre                   = iri("genre")
+	Geo                     = iri("geo")
+	GivenName               = iri("givenName")
+	HasPart                 = iri("hasPart")
+	Headline                = iri("headline")
+	HomeLocation            = iri("homeLocation")
+	Identifier              = iri("identifier")
+	Image                   = iri("image")
+	InLanguage              = iri("inLanguage")
+	IsPartOf                = iri("isPartOf")
+	Isbn                    = iri("isbn")
+	Item                    = iri("item")
+	ItemListElement         = iri("itemListElement")
+	JobTitle                = iri("jobTitle")
+	Keywords                = iri("keywords")
+	Knows                   = iri("knows")
+	Latitude                = iri("latitude")
+	LegalName               = iri("legalName")
+	License                 = iri("license")
+	Location                = iri("location")
+	Logo                    = iri("logo")
+	Longitude               = iri("longitude")
+	MainEntity              = iri("mainEntity")
+	MainEntityOfPage        = iri("mainEntityOfPage")
+	MemberOf                = iri("memberOf")
+	Name                    = iri("name")
+	Nationality             = iri("nationality")
+	NumberOfPages           = iri("numberOfPages")
+	Offers                  = iri("offers")
+	Parent                  = iri("parent")
+	ParentOrganization      = iri("parentOrganization")
+	Position                = iri("position")
+	PostalCode              = iri("postalCode")
+	PotentialAction         = iri("potentialAction")
+	Price                   = iri("price")
+	PriceCurrency           = iri("priceCurrency")
+	PropertyID              = iri("propertyID")
+	Publisher               = iri("publisher")
+	RatingValue             = iri("ratingValue")
+	ReviewProperty          = iri("review")
+	ReviewRating            = iri("reviewRating")
+	SameAs                  = iri("sameAs")
+	Sibling                 = iri("sibling")
+	Sku                     = iri("sku")
+	Spouse                  = iri("spouse")
+	StartDate               = iri("startDate")
+	StreetAddress           = iri("streetAddress")
+	SubOrganization         = iri("subOrganization")
+	Telephone               = iri("telephone")
+	TextProperty            = iri("text")
+	UnitCode                = iri("unitCode")
+	UnitText                = iri("unitText")
+	Url                     = iri("url")
+	Value                   = iri("value")
+	Version                 = iri("version")
+	WorksFor                = iri("worksFor")
+	WorstRating             = iri("worstRating")
+)
+
+// iri returns the IRI of the term with the given local name in the vocabulary.
+func iri(local string) rdf.IRI {
+	u, err := rdf.NewIRI(NS + local)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
@@ -0,0 +1,202 @@
+// Package sh provides the terms of the Shapes Constraint Language (SHACL) vocabulary
+// (http://www.w3.org/TR/shacl/), as rdf.IRI values.
+package sh
+
+import (
+	"github.com/knakk/rdf"
+	"github.com/knakk/rdf/internal/ns"
+)
+
+// NS is the namespace IRI of the vocabulary.
+const NS = ns.SH
+
+// Classes:
+var (
+	AbstractResult            = iri("AbstractResult")
+	ConstraintComponent       = iri("ConstraintComponent")
+	Function                  = iri("Function")
+	NodeKind                  = iri("NodeKind")
+	NodeShape                 = iri("NodeShape")
+	Parameter                 = iri("Parameter")
+	Parameterizable           = iri("Parameterizable")
+	PropertyGroup             = iri("PropertyGroup")
+	PropertyShape             = iri("PropertyShape")
+	ResultAnnotation          = iri("ResultAnnotation")
+	Rule                      = iri("Rule")
+	SPARQLAskExecutable       = iri("SPARQLAskExecutable")
+	SPARQLAskValidator        = iri("SPARQLAskValidator")
+	SPARQLConstraint          = iri("SPARQLConstraint")
+	SPARQLConstructExecutable = iri("SPARQLConstructExecutable")
+	SPARQLExecutable          = iri("SPARQLExecutable")
+	SPARQLFunction            = iri("SPARQLFunction")
+	SPARQLRule                = iri("SPARQLRule")
+	SPARQLSelectExecutable    = iri("SPARQLSelectExecutable")
+	SPARQLSelectValidator     = iri("SPARQLSelectValidator")
+	SPARQLTarget              = iri("SPARQLTarget")
+	SPARQLTargetType          = iri("SPARQLTargetType")
+	SPARQLUpdateExecutable    = iri("SPARQLUpdateExecutable")
+	Severity                  = iri("Severity")
+	Shape                     = iri("Shape")
+	Target                    = iri("Target")
+	TargetType                = iri("TargetType")
+	TripleRule                = iri("TripleRule")
+	ValidationReport          = iri("ValidationReport")
+	ValidationResult          = iri("ValidationResult")
+	Validator                 = iri("Validator")
+)
+
+// Individuals:
+var (
+	BlankNode          = iri("BlankNode")
+	BlankNodeOrIRI     = iri("BlankNodeOrIRI")
+	BlankNodeOrLiteral = iri("BlankNodeOrLiteral")
+	IRI                = iri("IRI")
+	IRIOrLiteral       = iri("IRIOrLiteral")
+	Info               = iri("Info")
+	Literal            = iri("Literal")
+	Violation          = iri("Violation")
+	Warning            = iri("Warning")
+)
+
+// Constraint components:
+var (
+	AndConstraintComponent               = iri("AndConstraintComponent")
+	ClassConstraintComponent             = iri("ClassConstraintComponent")
+	ClosedConstraintComponent            = iri("ClosedConstraintComponent")
+	DatatypeConstraintComponent          = iri("DatatypeConstraintComponent")
+	DisjointConstraintComponent          = iri("DisjointConstraintComponent")
+	EqualsConstraintComponent            = iri("EqualsConstraintComponent")
+	HasValueConstraintComponent          = iri("HasValueConstraintComponent")
+	InConstraintComponent                = iri("InConstraintComponent")
+	LanguageInConstraintComponent        = iri("LanguageInConstraintComponent")
+	LessThanConstraintComponent          = iri("LessThanConstraintComponent")
+	LessThanOrEqualsConstraintComponent  = iri("LessThanOrEqualsConstraintComponent")
+	MaxCountConstraintComponent          = iri("MaxCountConstraintComponent")
+	MaxExclusiveConstraintComponent      = iri("MaxExclusiveConstraintComponent")
+	MaxInclusiveConstraintComponent      = iri("MaxInclusiveConstraintComponent")
+	MaxLengthConstraintComponent         = iri("MaxLengthConstraintComponent")
+	MinCountConstraintComponent          = iri("MinCountConstraintComponent")
+	MinExclusiveConstraintComponent      = iri("MinExclusiveConstraintComponent")
+	MinInclusiveConstraintComponent      = iri("MinInclusiveConstraintComponent")
+	MinLengthConstraintComponent         = iri("MinLengthConstraintComponent")
+	NodeConstraintComponent              = iri("NodeConstraintComponent")
+	NodeKindConstraintComponent          = iri("NodeKindConstraintComponent")
+	NotConstraintComponent               = iri("NotConstraintComponent")
+	OrConstraintComponent                = iri("OrConstraintComponent")
+	PatternConstraintComponent           = iri("PatternConstraintComponent")
+	PropertyConstraintComponent          = iri("PropertyConstraintComponent")
+	QualifiedMaxCountConstraintComponent = iri("QualifiedMaxCountConstraintComponent")
+	QualifiedMinCountConstraintComponent = iri("QualifiedMinCountConstraintComponent")
+	SPARQLConstraintComponent            = iri("SPARQLConstraintComponent")
+	UniqueLangConstraintComponent        = iri("UniqueLangConstraintComponent")
+	XoneConstraintComponent              = iri("XoneConstraintComponent")
+)
+
+// Properties:
+var (
+	AlternativePath              = iri("alternativePath")
+	And                          = iri("and")
+	AnnotationProperty           = iri("annotationProperty")
+	AnnotationValue              = iri("annotationValue")
+	AnnotationVarName            = iri("annotationVarName")
+	Ask                          = iri("ask")
+	Class                        = iri("class")
+	Closed                       = iri("closed")
+	Condition                    = iri("condition")
+	Conforms                     = iri("conforms")
+	Construct                    = iri("construct")
+	Datatype                     = iri("datatype")
+	Deactivated                  = iri("deactivated")
+	Declare                      = iri("declare")
+	DefaultValue                 = iri("defaultValue")
+	Description                  = iri("description")
+	Detail                       = iri("detail")
+	Disjoint                     = iri("disjoint")
+	Entailment                   = iri("entailment")
+	Equals                       = iri("equals")
+	Expression                   = iri("expression")
+	FilterShape                  = iri("filterShape")
+	Flags                        = iri("flags")
+	FocusNode                    = iri("focusNode")
+	Group                        = iri("group")
+	HasValue                     = iri("hasValue")
+	IgnoredProperties            = iri("ignoredProperties")
+	In                           = iri("in")
+	Intersection                 = iri("intersection")
+	InversePath                  = iri("inversePath")
+	LabelTemplate                = iri("labelTemplate")
+	LanguageIn                   = iri("languageIn")
+	LessThan                     = iri("lessThan")
+	LessThanOrEquals             = iri("lessThanOrEquals")
+	MaxCount                     = iri("maxCount")
+	MaxExclusive                 = iri("maxExclusive")
+	MaxInclusive                 = iri("maxInclusive")
+	MaxLength                    = iri("maxLength")
+	Message                      = iri("message")
+	MinCount                     = iri("minCount")
+	MinExclusive                 = iri("minExclusive")
+	MinInclusive                 = iri("minInclusive")
+	MinLength                    = iri("minLength")
+	Name                         = iri("name")
+	Namespace                    = iri("namespace")
+	Node                         = iri("node")
+	NodeKindProperty             = iri("nodeKind")
+	NodeValidator                = iri("nodeValidator")
+	Not                          = iri("not")
+	Object                       = iri("object")
+	OneOrMorePath                = iri("oneOrMorePath")
+	Optional                     = iri("optional")
+	Or                           = iri("or")
+	Order                        = iri("order")
+	ParameterProperty            = iri("parameter")
+	Path                         = iri("path")
+	Pattern                      = iri("pattern")
+	Predicate                    = iri("predicate")
+	Prefix                       = iri("prefix")
+	Prefixes                     = iri("prefixes")
+	Property                     = iri("property")
+	PropertyValidator            = iri("propertyValidator")
+	QualifiedMaxCount            = iri("qualifiedMaxCount")
+	QualifiedMinCount            = iri("qualifiedMinCount")
+	QualifiedValueShape          = iri("qualifiedValueShape")
+	QualifiedValueShapesDisjoint = iri("qualifiedValueShapesDisjoint")
+	Result                       = iri("result")
+	ResultAnnotationProperty     = iri("resultAnnotation")
+	ResultMessage                = iri("resultMessage")
+	ResultPath                   = iri("resultPath")
+	ResultSeverity               = iri("resultSeverity")
+	ReturnType                   = iri("returnType")
+	RuleProperty                 = iri("rule")
+	Select                       = iri("select")
+	SeverityProperty             = iri("severity")
+	ShapesGraph                  = iri("shapesGraph")
+	ShapesGraphWellFormed        = iri("shapesGraphWellFormed")
+	SourceConstraint             = iri("sourceConstraint")
+	SourceConstraintComponent    = iri("sourceConstraintComponent")
+	SourceShape                  = iri("sourceShape")
+	Sparql                       = iri("sparql")
+	Subject                      = iri("subject")
+	SuggestedShapesGraph         = iri("suggestedShapesGraph")
+	TargetProperty               = iri("target")
+	TargetClass                  = iri("targetClass")
+	TargetNode                   = iri("targetNode")
+	TargetObjectsOf              = iri("targetObjectsOf")
+	TargetSubjectsOf             = iri("targetSubjectsOf")
+	Union                        = iri("union")
+	UniqueLang                   = iri("uniqueLang")
+	Update                       = iri("update")
+	ValidatorProperty            = iri("validator")
+	Value                        = iri("value")
+	Xone                         = iri("xone")
+	ZeroOrMorePath               = iri("zeroOrMorePath")
+	ZeroOrOnePath                = iri("zeroOrOnePath")
+)
+
+// iri returns the IRI of the term with the given local name in the vocabulary.
+func iri(local string) rdf.IRI {
+	u, err := rdf.NewIRI(NS + local)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
@@ -0,0 +1,60 @@
+// Package skos provides the terms of the Simple Knowledge Organization System vocabulary
+// (http://www.w3.org/TR/skos-reference/), as rdf.IRI values.
+package skos
+
+import (
+	"github.com/knakk/rdf"
+	"github.com/knakk/rdf/internal/ns"
+)
+
+// NS is the namespace IRI of the vocabulary.
+const NS = ns.SKOS
+
+// Classes:
+var (
+	Collection        = iri("Collection")
+	Concept           = iri("Concept")
+	ConceptScheme     = iri("ConceptScheme")
+	OrderedCollection = iri("OrderedCollection")
+)
+
+// Properties:
+var (
+	AltLabel           = iri("altLabel")
+	BroadMatch         = iri("broadMatch")
+	Broader            = iri("broader")
+	BroaderTransitive  = iri("broaderTransitive")
+	ChangeNote         = iri("changeNote")
+	CloseMatch         = iri("closeMatch")
+	Definition         = iri("definition")
+	EditorialNote      = iri("editorialNote")
+	ExactMatch         = iri("exactMatch")
+	Example            = iri("example")
+	HasTopConcept      = iri("hasTopConcept")
+	HiddenLabel        = iri("hiddenLabel")
+	HistoryNote        = iri("historyNote")
+	InScheme           = iri("inScheme")
+	MappingRelation    = iri("mappingRelation")
+	Member             = iri("member")
+	MemberList         = iri("memberList")
+	NarrowMatch        = iri("narrowMatch")
+	Narrower           = iri("narrower")
+	NarrowerTransitive = iri("narrowerTransitive")
+	Notation           = iri("notation")
+	Note               = iri("note")
+	PrefLabel          = iri("prefLabel")
+	Related            = iri("related")
+	RelatedMatch       = iri("relatedMatch")
+	ScopeNote          = iri("scopeNote")
+	SemanticRelation   = iri("semanticRelation")
+	TopConceptOf       = iri("topConceptOf")
+)
+
+// iri returns the IRI of the term with the given local name in the vocabulary.
+func iri(local string) rdf.IRI {
+	u, err := rdf.NewIRI(NS + local)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
@@ -0,0 +1,71 @@
+// Package xsd provides the terms of the XML Schema datatypes
+// (http://www.w3.org/TR/xmlschema11-2/), as rdf.IRI values.
+package xsd
+
+import (
+	"github.com/knakk/rdf"
+	"github.com/knakk/rdf/internal/ns"
+)
+
+// NS is the namespace IRI of the vocabulary.
+const NS = ns.XSD
+
+// Datatypes:
+var (
+	ENTITY             = iri("ENTITY")
+	ID                 = iri("ID")
+	IDREF              = iri("IDREF")
+	NCName             = iri("NCName")
+	NMTOKEN            = iri("NMTOKEN")
+	NOTATION           = iri("NOTATION")
+	Name               = iri("Name")
+	QName              = iri("QName")
+	AnyAtomicType      = iri("anyAtomicType")
+	AnySimpleType      = iri("anySimpleType")
+	AnyType            = iri("anyType")
+	AnyURI             = iri("anyURI")
+	Base64Binary       = iri("base64Binary")
+	Boolean            = iri("boolean")
+	Byte               = iri("byte")
+	Date               = iri("date")
+	DateTime           = iri("dateTime")
+	DateTimeStamp      = iri("dateTimeStamp")
+	DayTimeDuration    = iri("dayTimeDuration")
+	Decimal            = iri("decimal")
+	Double             = iri("double")
+	Duration           = iri("duration")
+	Float              = iri("float")
+	GDay               = iri("gDay")
+	GMonth             = iri("gMonth")
+	GMonthDay          = iri("gMonthDay")
+	GYear              = iri("gYear")
+	GYearMonth         = iri("gYearMonth")
+	HexBinary          = iri("hexBinary")
+	Int                = iri("int")
+	Integer            = iri("integer")
+	Language           = iri("language")
+	Long               = iri("long")
+	NegativeInteger    = iri("negativeInteger")
+	NonNegativeInteger = iri("nonNegativeInteger")
+	NonPositiveInteger = iri("nonPositiveInteger")
+	NormalizedString   = iri("normalizedString")
+	PositiveInteger    = iri("positiveInteger")
+	Short              = iri("short")
+	String             = iri("string")
+	Time               = iri("time")
+	Token              = iri("token")
+	UnsignedByte       = iri("unsignedByte")
+	UnsignedInt        = iri("unsignedInt")
+	UnsignedLong       = iri("unsignedLong")
+	UnsignedShort      = iri("unsignedShort")
+	YearMonthDuration  = iri("yearMonthDuration")
+)
+
+// iri returns the IRI of the term with the given local name in the vocabulary.
+func iri(local string) rdf.IRI {
+	u, err := rdf.NewIRI(NS + local)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
@@ -0,0 +1,30 @@
+package xsd_test
+
+import (
+	"testing"
+
+	"github.com/knakk/rdf"
+	"github.com/knakk/rdf/vocab/xsd"
+)
+
+func TestCoreDatatypes(t *testing.T) {
+	tests := []struct {
+		v    interface{}
+		want rdf.IRI
+	}{
+		{"a", xsd.String},
+		{true, xsd.Boolean},
+		{1, xsd.Integer},
+		{3.14, xsd.Double},
+		{[]byte("a"), xsd.Base64Binary},
+	}
+	for _, tt := range tests {
+		l, err := rdf.NewLiteral(tt.v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if l.DataType != tt.want {
+			t.Errorf("NewLiteral(%#v).DataType => %v; want %v", tt.v, l.DataType, tt.want)
+		}
+	}
+}
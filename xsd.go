@@ -0,0 +1,1074 @@
+package rdf
+
+import (
+	"encoding/base64"
+	hexenc "encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Date represents the value of a xsd:date literal; a calendar date with an
+// optional timezone.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+	Zone  *time.Location // nil if the date has no timezone
+}
+
+// String returns the date in xsd:date lexical form.
+func (d Date) String() string {
+	return fmt.Sprintf("%s-%02d-%02d%s", formatYear(d.Year), d.Month, d.Day, formatZone(d.Zone))
+}
+
+// Time represents the value of a xsd:time literal; a time of day with an
+// optional timezone.
+type Time struct {
+	Hour       int
+	Minute     int
+	Second     int
+	Nanosecond int
+	Zone       *time.Location // nil if the time has no timezone
+}
+
+// String returns the time in xsd:time lexical form.
+func (t Time) String() string {
+	return fmt.Sprintf("%02d:%02d:%02d%s%s", t.Hour, t.Minute, t.Second, formatNanos(t.Nanosecond), formatZone(t.Zone))
+}
+
+// GYear represents the value of a xsd:gYear literal; a Gregorian calendar
+// year with an optional timezone.
+type GYear struct {
+	Year int
+	Zone *time.Location // nil if the year has no timezone
+}
+
+// String returns the year in xsd:gYear lexical form.
+func (y GYear) String() string {
+	return formatYear(y.Year) + formatZone(y.Zone)
+}
+
+// GYearMonth represents the value of a xsd:gYearMonth literal; a month in a
+// Gregorian calendar year, with an optional timezone.
+type GYearMonth struct {
+	Year  int
+	Month time.Month
+	Zone  *time.Location // nil if the month has no timezone
+}
+
+// String returns the month in xsd:gYearMonth lexical form.
+func (ym GYearMonth) String() string {
+	return fmt.Sprintf("%s-%02d%s", formatYear(ym.Year), ym.Month, formatZone(ym.Zone))
+}
+
+// Duration represents the value of a xsd:duration or xsd:yearMonthDuration
+// literal. The length of a month varies, so a duration is made up of a
+// number of months, and an exact day-time part. For negative durations,
+// both parts are negative.
+type Duration struct {
+	Months int           // years*12 + months
+	Time   time.Duration // days, hours, minutes and seconds
+}
+
+// String returns the duration in xsd:duration lexical form.
+func (d Duration) String() string {
+	months, dur := d.Months, d.Time
+	var b strings.Builder
+	if months < 0 || dur < 0 {
+		b.WriteByte('-')
+		months, dur = -months, -dur
+	}
+	b.WriteByte('P')
+	if y := months / 12; y != 0 {
+		fmt.Fprintf(&b, "%dY", y)
+	}
+	if m := months % 12; m != 0 {
+		fmt.Fprintf(&b, "%dM", m)
+	}
+	if days := dur / (24 * time.Hour); days != 0 {
+		fmt.Fprintf(&b, "%dD", days)
+		dur -= days * 24 * time.Hour
+	}
+	if dur != 0 || b.Len() <= 2 {
+		b.WriteByte('T')
+		if h := dur / time.Hour; h != 0 {
+			fmt.Fprintf(&b, "%dH", h)
+			dur -= h * time.Hour
+		}
+		if m := dur / time.Minute; m != 0 {
+			fmt.Fprintf(&b, "%dM", m)
+			dur -= m * time.Minute
+		}
+		if dur != 0 || strings.HasSuffix(b.String(), "T") {
+			fmt.Fprintf(&b, "%d%sS", dur/time.Second, formatNanos(int(dur%time.Second)))
+		}
+	}
+	return b.String()
+}
+
+// parseTyped parses the lexical form s of the given datatype into its
+// corresponding Go value. See Literal.Typed for the mapping of datatypes.
+func parseTyped(s string, dt IRI) (interface{}, error) {
+	switch dt {
+	case xsdString, rdfLangString:
+		return s, nil
+	case xsdBoolean:
+		switch s {
+		case "true", "1":
+			return true, nil
+		case "false", "0":
+			return false, nil
+		}
+	case xsdDecimal:
+		if isDecimal(s) {
+			r, ok := new(big.Rat).SetString(s)
+			if ok {
+				return r, nil
+			}
+		}
+	case xsdInteger:
+		if i, ok := parseBigInt(s); ok {
+			return i, nil
+		}
+	case xsdNonNegativeInteger, xsdPositiveInteger, xsdNonPositiveInteger, xsdNegativeInteger:
+		if i, ok := parseBigInt(s); ok {
+			sign := i.Sign()
+			if (dt == xsdNonNegativeInteger && sign >= 0) ||
+				(dt == xsdPositiveInteger && sign > 0) ||
+				(dt == xsdNonPositiveInteger && sign <= 0) ||
+				(dt == xsdNegativeInteger && sign < 0) {
+				return i, nil
+			}
+		}
+	case xsdLong, xsdInt, xsdShort, xsdByte:
+		if isInteger(s) {
+			if i, ok := parseInt(s, dt); ok {
+				return i, nil
+			}
+		}
+	case xsdUnsignedLong, xsdUnsignedInt, xsdUnsignedShort, xsdUnsignedByte:
+		if isInteger(s) {
+			if i, ok := parseUint(s, dt); ok {
+				return i, nil
+			}
+		}
+	case xsdDouble:
+		if f, ok := parseFloat(s, 64); ok {
+			return f, nil
+		}
+	case xsdFloat:
+		if f, ok := parseFloat(s, 32); ok {
+			return float32(f), nil
+		}
+	case xsdDateTime, xsdDateTimeStamp:
+		if t, _, ok := parseDateTime(s, dt == xsdDateTimeStamp); ok {
+			return t, nil
+		}
+	case xsdDate:
+		if d, ok := parseDate(s); ok {
+			return d, nil
+		}
+	case xsdTime:
+		if t, ok := parseTime(s); ok {
+			return t, nil
+		}
+	case xsdYear:
+		if y, rest, ok := parseYear(s); ok {
+			if zone, ok := parseZone(rest); ok {
+				return GYear{Year: y, Zone: zone}, nil
+			}
+		}
+	case xsdYearMonth:
+		if y, rest, ok := parseYear(s); ok && len(rest) >= 3 && rest[0] == '-' {
+			m, ok1 := parse2Digits(rest[1:3])
+			zone, ok2 := parseZone(rest[3:])
+			if ok1 && ok2 && m >= 1 && m <= 12 {
+				return GYearMonth{Year: y, Month: time.Month(m), Zone: zone}, nil
+			}
+		}
+	case xsdDuration:
+		if d, ok := parseDuration(s, true, true); ok {
+			return d, nil
+		}
+	case xsdYearMonthDuration:
+		if d, ok := parseDuration(s, true, false); ok {
+			return d, nil
+		}
+	case xsdDayTimeDuration:
+		if d, ok := parseDuration(s, false, true); ok {
+			return d.Time, nil
+		}
+	case xsdHexBinary:
+		if b, err := hexenc.DecodeString(s); err == nil {
+			return b, nil
+		}
+	case xsdBase64Binary:
+		if b, err := base64.StdEncoding.DecodeString(strings.Replace(s, " ", "", -1)); err == nil {
+			return b, nil
+		}
+	case xsdAnyURI:
+		if u, err := url.Parse(s); err == nil {
+			return u, nil
+		}
+	default:
+		return s, nil
+	}
+	return nil, fmt.Errorf("invalid lexical form for %s: %q", dt.Serialize(NTriples), s)
+}
+
+// isInteger checks if s is a valid xsd:integer lexical form: [\-+]?[0-9]+
+func isInteger(s string) bool {
+	if len(s) > 0 && (s[0] == '+' || s[0] == '-') {
+		s = s[1:]
+	}
+	if len(s) == 0 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isDigit(rune(s[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+// isDecimal checks if s is a valid xsd:decimal lexical form:
+// (\+|-)?([0-9]+(\.[0-9]*)?|\.[0-9]+)
+func isDecimal(s string) bool {
+	if len(s) > 0 && (s[0] == '+' || s[0] == '-') {
+		s = s[1:]
+	}
+	digits, dot := 0, false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case isDigit(rune(s[i])):
+			digits++
+		case s[i] == '.' && !dot:
+			dot = true
+		default:
+			return false
+		}
+	}
+	return digits > 0
+}
+
+// parseBigInt parses a xsd:integer lexical form.
+func parseBigInt(s string) (*big.Int, bool) {
+	if !isInteger(s) {
+		return nil, false
+	}
+	return new(big.Int).SetString(s, 10)
+}
+
+// parseInt parses the lexical form of one of the limited-range integer
+// types xsd:long, xsd:int, xsd:short and xsd:byte.
+func parseInt(s string, dt IRI) (interface{}, bool) {
+	switch dt {
+	case xsdLong:
+		i, err := strconv.ParseInt(s, 10, 64)
+		return i, err == nil
+	case xsdInt:
+		i, err := strconv.ParseInt(s, 10, 32)
+		return int32(i), err == nil
+	case xsdShort:
+		i, err := strconv.ParseInt(s, 10, 16)
+		return int16(i), err == nil
+	default: // xsdByte
+		i, err := strconv.ParseInt(s, 10, 8)
+		return int8(i), err == nil
+	}
+}
+
+// parseUint parses the lexical form of one of the unsigned integer types.
+func parseUint(s string, dt IRI) (interface{}, bool) {
+	switch s[0] {
+	case '+':
+		// strconv.ParseUint does not accept a sign
+		s = s[1:]
+	case '-':
+		// "-0" is a valid unsigned integer
+		if strings.Trim(s[1:], "0") != "" {
+			return nil, false
+		}
+		s = "0"
+	}
+	switch dt {
+	case xsdUnsignedLong:
+		i, err := strconv.ParseUint(s, 10, 64)
+		return i, err == nil
+	case xsdUnsignedInt:
+		i, err := strconv.ParseUint(s, 10, 32)
+		return uint32(i), err == nil
+	case xsdUnsignedShort:
+		i, err := strconv.ParseUint(s, 10, 16)
+		return uint16(i), err == nil
+	default: // xsdUnsignedByte
+		i, err := strconv.ParseUint(s, 10, 8)
+		return uint8(i), err == nil
+	}
+}
+
+// parseFloat parses a xsd:double or xsd:float lexical form:
+// (\+|-)?([0-9]+(\.[0-9]*)?|\.[0-9]+)([Ee](\+|-)?[0-9]+)?|(\+|-)?INF|NaN
+func parseFloat(s string, bitSize int) (float64, bool) {
+	switch s {
+	case "INF", "+INF":
+		return math.Inf(1), true
+	case "-INF":
+		return math.Inf(-1), true
+	case "NaN":
+		return math.NaN(), true
+	}
+	mantissa := s
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		if !isInteger(s[i+1:]) {
+			return 0, false
+		}
+		mantissa = s[:i]
+	}
+	if !isDecimal(mantissa) {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(s, bitSize)
+	if err != nil {
+		// Out of range values are rounded to ±INF, as in XSD.
+		if ne, ok := err.(*strconv.NumError); !ok || ne.Err != strconv.ErrRange {
+			return 0, false
+		}
+	}
+	return f, true
+}
+
+// parse2Digits parses a string of exactly two decimal digits.
+func parse2Digits(s string) (int, bool) {
+	if len(s) != 2 || !isDigit(rune(s[0])) || !isDigit(rune(s[1])) {
+		return 0, false
+	}
+	return int(s[0]-'0')*10 + int(s[1]-'0'), true
+}
+
+// parseYear parses the year part of a xsd date type; -?([1-9][0-9]{3,}|0[0-9]{3}),
+// returning the year and the rest of the string.
+func parseYear(s string) (year int, rest string, ok bool) {
+	i := 0
+	if len(s) > 0 && s[0] == '-' {
+		i++
+	}
+	j := i
+	for j < len(s) && isDigit(rune(s[j])) {
+		j++
+	}
+	if j-i < 4 || (j-i > 4 && s[i] == '0') {
+		return 0, s, false
+	}
+	y, err := strconv.Atoi(s[:j])
+	if err != nil {
+		return 0, s, false
+	}
+	return y, s[j:], true
+}
+
+// parseZone parses an optional timezone: (Z|(\+|-)hh:mm)?
+// It returns a nil location if s is empty.
+func parseZone(s string) (*time.Location, bool) {
+	switch {
+	case s == "":
+		return nil, true
+	case s == "Z":
+		return time.UTC, true
+	case len(s) == 6 && (s[0] == '+' || s[0] == '-') && s[3] == ':':
+		h, ok1 := parse2Digits(s[1:3])
+		m, ok2 := parse2Digits(s[4:6])
+		if !ok1 || !ok2 || m > 59 || h > 14 || (h == 14 && m != 0) {
+			return nil, false
+		}
+		offset := h*3600 + m*60
+		if s[0] == '-' {
+			offset = -offset
+		}
+		if offset == 0 {
+			return time.UTC, true
+		}
+		return time.FixedZone("", offset), true
+	default:
+		return nil, false
+	}
+}
+
+// parseDateParts parses the date part of xsd:date and xsd:dateTime
+// literals, returning the rest of the string.
+func parseDateParts(s string) (y int, m time.Month, d int, rest string, ok bool) {
+	y, rest, ok = parseYear(s)
+	if !ok || len(rest) < 6 || rest[0] != '-' || rest[3] != '-' {
+		return 0, 0, 0, s, false
+	}
+	mm, ok1 := parse2Digits(rest[1:3])
+	dd, ok2 := parse2Digits(rest[4:6])
+	if !ok1 || !ok2 || mm < 1 || mm > 12 || dd < 1 || dd > daysIn(time.Month(mm), y) {
+		return 0, 0, 0, s, false
+	}
+	return y, time.Month(mm), dd, rest[6:], true
+}
+
+// daysIn returns the number of days in the given month.
+func daysIn(m time.Month, year int) int {
+	return time.Date(year, m+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// parseTimeParts parses hh:mm:ss(\.s+)?, returning the rest of the string.
+// The time 24:00:00 is returned as 24 hours, and must be normalized by the caller.
+func parseTimeParts(s string) (h, m, sec, nsec int, rest string, ok bool) {
+	if len(s) < 8 || s[2] != ':' || s[5] != ':' {
+		return 0, 0, 0, 0, s, false
+	}
+	h, ok1 := parse2Digits(s[0:2])
+	m, ok2 := parse2Digits(s[3:5])
+	sec, ok3 := parse2Digits(s[6:8])
+	if !ok1 || !ok2 || !ok3 || h > 24 || m > 59 || sec > 59 {
+		return 0, 0, 0, 0, s, false
+	}
+	rest = s[8:]
+	if len(rest) > 0 && rest[0] == '.' {
+		i := 1
+		for i < len(rest) && isDigit(rune(rest[i])) {
+			if i <= 9 {
+				nsec = nsec*10 + int(rest[i]-'0')
+			}
+			i++
+		}
+		if i == 1 {
+			return 0, 0, 0, 0, s, false
+		}
+		for j := i; j <= 9; j++ {
+			nsec *= 10
+		}
+		rest = rest[i:]
+	}
+	if h == 24 && (m != 0 || sec != 0 || nsec != 0) {
+		return 0, 0, 0, 0, s, false
+	}
+	return h, m, sec, nsec, rest, true
+}
+
+// parseDateTime parses a xsd:dateTime lexical form, and reports whether it has
+// a timezone. Values without a timezone are taken to be in UTC. If
+// zoneRequired is true (xsd:dateTimeStamp), the timezone must be present.
+func parseDateTime(s string, zoneRequired bool) (t time.Time, zoned, ok bool) {
+	y, mon, d, rest, ok := parseDateParts(s)
+	if !ok || len(rest) == 0 || rest[0] != 'T' {
+		return time.Time{}, false, false
+	}
+	h, m, sec, nsec, rest, ok := parseTimeParts(rest[1:])
+	if !ok {
+		return time.Time{}, false, false
+	}
+	zone, ok := parseZone(rest)
+	if !ok || (zoneRequired && zone == nil) {
+		return time.Time{}, false, false
+	}
+	zoned = zone != nil
+	if !zoned {
+		zone = time.UTC
+	}
+	// time.Date normalizes 24:00:00 to the first instant of the next day.
+	return time.Date(y, mon, d, h, m, sec, nsec, zone), zoned, true
+}
+
+// parseDate parses a xsd:date lexical form.
+func parseDate(s string) (Date, bool) {
+	y, m, d, rest, ok := parseDateParts(s)
+	if !ok {
+		return Date{}, false
+	}
+	zone, ok := parseZone(rest)
+	if !ok {
+		return Date{}, false
+	}
+	return Date{Year: y, Month: m, Day: d, Zone: zone}, true
+}
+
+// parseTime parses a xsd:time lexical form.
+func parseTime(s string) (Time, bool) {
+	h, m, sec, nsec, rest, ok := parseTimeParts(s)
+	if !ok {
+		return Time{}, false
+	}
+	zone, ok := parseZone(rest)
+	if !ok {
+		return Time{}, false
+	}
+	if h == 24 {
+		h = 0
+	}
+	return Time{Hour: h, Minute: m, Second: sec, Nanosecond: nsec, Zone: zone}, true
+}
+
+// parseDuration parses a xsd:duration lexical form:
+// -?P([0-9]+Y)?([0-9]+M)?([0-9]+D)?(T([0-9]+H)?([0-9]+M)?([0-9]+(\.[0-9]+)?S)?)?
+// The flags yearMonth and dayTime tells which parts are allowed.
+func parseDuration(s string, yearMonth, dayTime bool) (Duration, bool) {
+	var d Duration
+	neg := false
+	if len(s) > 0 && s[0] == '-' {
+		neg = true
+		s = s[1:]
+	}
+	if len(s) < 2 || s[0] != 'P' || s[len(s)-1] == 'T' {
+		return d, false
+	}
+	s = s[1:]
+	inTime := false
+	designators := "YMD" // allowed designators, in order
+	for len(s) > 0 {
+		if s[0] == 'T' {
+			if inTime || !dayTime {
+				return d, false
+			}
+			inTime = true
+			designators = "HMS"
+			s = s[1:]
+			continue
+		}
+		i := 0
+		for i < len(s) && isDigit(rune(s[i])) {
+			i++
+		}
+		n, err := strconv.ParseInt(s[:i], 10, 64)
+		if i == 0 || err != nil {
+			return d, false
+		}
+		var frac time.Duration
+		if i < len(s) && s[i] == '.' && inTime {
+			// fractional seconds
+			j := i + 1
+			for j < len(s) && isDigit(rune(s[j])) {
+				j++
+			}
+			if j == i+1 || j == len(s) || s[j] != 'S' {
+				return d, false
+			}
+			nsec := 0
+			for k := i + 1; k < i+10; k++ {
+				nsec *= 10
+				if k < j {
+					nsec += int(s[k] - '0')
+				}
+			}
+			frac = time.Duration(nsec)
+			i = j
+		}
+		if i == len(s) {
+			return d, false
+		}
+		k := strings.IndexByte(designators, s[i])
+		if k < 0 {
+			return d, false
+		}
+		designators = designators[k+1:]
+		ok := true
+		switch {
+		case !inTime && s[i] == 'Y':
+			var months int64
+			months, ok = addScaled(int64(d.Months), n, 12)
+			d.Months = int(months)
+		case !inTime && s[i] == 'M':
+			var months int64
+			months, ok = addScaled(int64(d.Months), n, 1)
+			d.Months = int(months)
+		case !inTime && s[i] == 'D':
+			d.Time, ok = addDuration(d.Time, n, 24*time.Hour)
+		case s[i] == 'H':
+			d.Time, ok = addDuration(d.Time, n, time.Hour)
+		case s[i] == 'M':
+			d.Time, ok = addDuration(d.Time, n, time.Minute)
+		case s[i] == 'S':
+			if d.Time, ok = addDuration(d.Time, n, time.Second); ok {
+				d.Time, ok = addDuration(d.Time, int64(frac), 1)
+			}
+		}
+		if !ok {
+			// too large to be represented
+			return d, false
+		}
+		s = s[i+1:]
+	}
+	if !yearMonth && d.Months != 0 || !dayTime && d.Time != 0 {
+		return d, false
+	}
+	if neg {
+		d.Months, d.Time = -d.Months, -d.Time
+	}
+	return d, true
+}
+
+// addScaled returns sum + n*unit, or false if the result overflows an int64.
+// All the arguments are non-negative.
+func addScaled(sum, n, unit int64) (int64, bool) {
+	if n > (math.MaxInt64-sum)/unit {
+		return 0, false
+	}
+	return sum + n*unit, true
+}
+
+// addDuration is addScaled for durations.
+func addDuration(sum time.Duration, n int64, unit time.Duration) (time.Duration, bool) {
+	v, ok := addScaled(int64(sum), n, int64(unit))
+	return time.Duration(v), ok
+}
+
+// formatYear formats a year with at least 4 digits.
+func formatYear(y int) string {
+	if y < 0 {
+		return fmt.Sprintf("-%04d", -y)
+	}
+	return fmt.Sprintf("%04d", y)
+}
+
+// formatZone formats a timezone as Z or (+|-)hh:mm. A nil location
+// gives the empty string.
+func formatZone(loc *time.Location) string {
+	if loc == nil {
+		return ""
+	}
+	_, offset := time.Date(2000, 1, 1, 0, 0, 0, 0, loc).Zone()
+	if offset == 0 {
+		return "Z"
+	}
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	return fmt.Sprintf("%c%02d:%02d", sign, offset/3600, offset%3600/60)
+}
+
+// formatNanos formats nanoseconds as fractional seconds, without trailing zeros.
+func formatNanos(nsec int) string {
+	if nsec == 0 {
+		return ""
+	}
+	return strings.TrimRight(fmt.Sprintf(".%09d", nsec), "0")
+}
+
+// valueSpace groups datatypes whose values can be compared with each other.
+type valueSpace int
+
+const (
+	spaceOther    valueSpace = iota // unknown datatypes, and strings
+	spaceAtomic                     // values comparable only within the same datatype
+	spaceNumeric                    // numbers of any numeric datatype
+	spaceDateTime                   // xsd:dateTime and xsd:dateTimeStamp
+	spaceDuration                   // xsd:duration and its subtypes
+)
+
+// valueSpaceOf returns the value space of the given datatype.
+func valueSpaceOf(dt IRI) valueSpace {
+	switch dt {
+	case xsdDecimal, xsdInteger, xsdDouble, xsdFloat,
+		xsdLong, xsdInt, xsdShort, xsdByte,
+		xsdUnsignedLong, xsdUnsignedInt, xsdUnsignedShort, xsdUnsignedByte,
+		xsdPositiveInteger, xsdNonNegativeInteger, xsdNegativeInteger, xsdNonPositiveInteger:
+		return spaceNumeric
+	case xsdDateTime, xsdDateTimeStamp:
+		return spaceDateTime
+	case xsdDuration, xsdYearMonthDuration, xsdDayTimeDuration:
+		return spaceDuration
+	case xsdBoolean, xsdDate, xsdTime, xsdYear, xsdYearMonth, xsdHexBinary, xsdBase64Binary, xsdAnyURI:
+		return spaceAtomic
+	default:
+		return spaceOther
+	}
+}
+
+// numberAsRat returns the value of a number, as returned from Literal.Typed,
+// as a *big.Rat. For NaN and infinite values it returns nil and the float value.
+func numberAsRat(v interface{}) (*big.Rat, float64) {
+	switch n := v.(type) {
+	case *big.Rat:
+		return n, 0
+	case *big.Int:
+		return new(big.Rat).SetInt(n), 0
+	case int64:
+		return new(big.Rat).SetInt64(n), 0
+	case int32:
+		return new(big.Rat).SetInt64(int64(n)), 0
+	case int16:
+		return new(big.Rat).SetInt64(int64(n)), 0
+	case int8:
+		return new(big.Rat).SetInt64(int64(n)), 0
+	case uint64:
+		return new(big.Rat).SetUint64(n), 0
+	case uint32:
+		return new(big.Rat).SetUint64(uint64(n)), 0
+	case uint16:
+		return new(big.Rat).SetUint64(uint64(n)), 0
+	case uint8:
+		return new(big.Rat).SetUint64(uint64(n)), 0
+	case float32:
+		return numberAsRat(float64(n))
+	case float64:
+		if math.IsInf(n, 0) || math.IsNaN(n) {
+			return nil, n
+		}
+		return new(big.Rat).SetFloat64(n), 0
+	default:
+		panic(fmt.Errorf("numberAsRat: not a number: %#v", v))
+	}
+}
+
+// compareNumbers compares two numbers, as returned from Literal.Typed. It
+// returns false if they cannot be compared, i.e. if either one is NaN.
+func compareNumbers(a, b interface{}) (int, bool) {
+	ra, fa := numberAsRat(a)
+	rb, fb := numberAsRat(b)
+	if ra != nil && rb != nil {
+		return ra.Cmp(rb), true
+	}
+	if math.IsNaN(fa) || math.IsNaN(fb) {
+		return 0, false
+	}
+	// At least one of the numbers is infinite; a finite number has fa or fb set to 0.
+	switch {
+	case fa < fb:
+		return -1, true
+	case fa > fb:
+		return 1, true
+	default:
+		return 0, true
+	}
+}
+
+// asDuration returns a duration value, as returned from Literal.Typed, as a Duration.
+func asDuration(v interface{}) Duration {
+	if d, ok := v.(time.Duration); ok {
+		return Duration{Time: d}
+	}
+	return v.(Duration)
+}
+
+// canonicalForm returns the canonical lexical form of s in the given datatype,
+// or an error if s is not a valid lexical form.
+func canonicalForm(s string, dt IRI) (string, error) {
+	v, err := parseTyped(s, dt)
+	if err != nil {
+		return s, err
+	}
+	switch dt {
+	case xsdBoolean:
+		return strconv.FormatBool(v.(bool)), nil
+	case xsdInteger, xsdLong, xsdInt, xsdShort, xsdByte,
+		xsdUnsignedLong, xsdUnsignedInt, xsdUnsignedShort, xsdUnsignedByte,
+		xsdPositiveInteger, xsdNonNegativeInteger, xsdNegativeInteger, xsdNonPositiveInteger:
+		return fmt.Sprint(v), nil
+	case xsdDecimal:
+		return canonicalDecimal(s), nil
+	case xsdDouble:
+		return canonicalFloat(v.(float64), 64), nil
+	case xsdFloat:
+		return canonicalFloat(float64(v.(float32)), 32), nil
+	case xsdDateTime, xsdDateTimeStamp:
+		t := v.(time.Time)
+		zone := ""
+		if hasZone(s) {
+			t = t.UTC()
+			zone = "Z"
+		}
+		return fmt.Sprintf("%s-%02d-%02dT%02d:%02d:%02d%s%s",
+			formatYear(t.Year()), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(),
+			formatNanos(t.Nanosecond()), zone), nil
+	case xsdTime:
+		t := v.(Time)
+		if t.Zone != nil {
+			_, offset := time.Date(2000, 1, 1, 0, 0, 0, 0, t.Zone).Zone()
+			secs := ((t.Hour*3600+t.Minute*60+t.Second-offset)%86400 + 86400) % 86400
+			t = Time{Hour: secs / 3600, Minute: secs % 3600 / 60, Second: secs % 60, Nanosecond: t.Nanosecond, Zone: time.UTC}
+		}
+		return t.String(), nil
+	case xsdDayTimeDuration:
+		return Duration{Time: v.(time.Duration)}.String(), nil
+	case xsdHexBinary:
+		return strings.ToUpper(hexenc.EncodeToString(v.([]byte))), nil
+	case xsdBase64Binary:
+		return base64.StdEncoding.EncodeToString(v.([]byte)), nil
+	case xsdDate, xsdYear, xsdYearMonth, xsdDuration, xsdYearMonthDuration:
+		return v.(fmt.Stringer).String(), nil
+	default:
+		return s, nil
+	}
+}
+
+// hasZone checks if a date/time lexical form ends with a timezone.
+func hasZone(s string) bool {
+	n := len(s)
+	return (n > 0 && s[n-1] == 'Z') || (n >= 6 && (s[n-6] == '+' || s[n-6] == '-') && s[n-3] == ':')
+}
+
+// canonicalDecimal returns the canonical form of a valid xsd:decimal lexical
+// form; no leading '+', no superfluous leading or trailing zeros, and at least
+// one digit on each side of the decimal point.
+func canonicalDecimal(s string) string {
+	sign := ""
+	switch s[0] {
+	case '-':
+		sign = "-"
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+	ip, fp := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		ip, fp = s[:i], s[i+1:]
+	}
+	ip = strings.TrimLeft(ip, "0")
+	fp = strings.TrimRight(fp, "0")
+	if ip == "" {
+		ip = "0"
+	}
+	if fp == "" {
+		fp = "0"
+		if ip == "0" {
+			sign = ""
+		}
+	}
+	return sign + ip + "." + fp
+}
+
+// formatFloat returns a xsd:double or xsd:float lexical form of f; the
+// shortest decimal representation, or INF, -INF or NaN.
+func formatFloat(f float64, bitSize int) string {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "INF"
+	case math.IsInf(f, -1):
+		return "-INF"
+	}
+	return strconv.FormatFloat(f, 'g', -1, bitSize)
+}
+
+// formatRat returns the canonical xsd:decimal lexical form of r, or false if
+// r has no finite decimal expansion, i.e. if its denominator has other prime
+// factors than 2 and 5.
+func formatRat(r *big.Rat) (string, bool) {
+	d := new(big.Int).Set(r.Denom())
+	var twos, fives int
+	m := new(big.Int)
+	for _, f := range []struct {
+		p int64
+		n *int
+	}{{2, &twos}, {5, &fives}} {
+		p := big.NewInt(f.p)
+		for {
+			q, rem := new(big.Int).QuoRem(d, p, m)
+			if rem.Sign() != 0 {
+				break
+			}
+			d = q
+			*f.n++
+		}
+	}
+	if d.Cmp(big.NewInt(1)) != 0 {
+		return "", false
+	}
+	digits := twos
+	if fives > digits {
+		digits = fives
+	}
+	return canonicalDecimal(r.FloatString(digits)), true
+}
+
+// canonicalFloat returns the canonical xsd:double or xsd:float lexical form of
+// f; a mantissa with a single non-zero digit before the decimal point, and an
+// exponent without leading zeros, e.g. "1.5E3".
+func canonicalFloat(f float64, bitSize int) string {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "INF"
+	case math.IsInf(f, -1):
+		return "-INF"
+	}
+	s := strconv.FormatFloat(f, 'E', -1, bitSize)
+	i := strings.IndexByte(s, 'E')
+	mantissa, exp := s[:i], s[i+1:]
+	if !strings.Contains(mantissa, ".") {
+		mantissa += ".0"
+	}
+	e, _ := strconv.Atoi(exp)
+	return mantissa + "E" + strconv.Itoa(e)
+}
+
+// LiteralAs returns the value of the literal converted to the Go type T.
+//
+// The value is first parsed as by Literal.Typed, and returned as it is if it
+// has type T. Otherwise it is converted, if possible without loss:
+//
+//  T                                   Literals accepted
+//  ---------------------------------   ------------------------------------------
+//  string                              any literal; T is its lexical form
+//  int, int8 .. int64, uint .. uint64  numbers with an integer value in range of T
+//  float64, float32                    any number, rounded to nearest
+//  *big.Int                            numbers with an integer value
+//  *big.Rat                            finite numbers
+//  time.Duration                       xsd:duration without years and months
+//  Duration                            xsd:dayTimeDuration
+//  net.IP                              strings holding an IP address
+//
+// An error is returned if the literal is not well-typed, or if its value
+// cannot be converted to T.
+func LiteralAs[T any](l Literal) (T, error) {
+	var t T
+	v, err := l.Typed()
+	if err != nil {
+		return t, err
+	}
+	if tv, ok := v.(T); ok {
+		return tv, nil
+	}
+	if !convertTyped(l, v, &t) {
+		return t, fmt.Errorf("cannot convert %s to %T", l.Serialize(NTriples), t)
+	}
+	return t, nil
+}
+
+// convertTyped converts the value v of the literal l, as returned from
+// Literal.Typed, and stores the result in the value pointed to by dst. It
+// returns false if v cannot be converted to the type of dst.
+func convertTyped(l Literal, v interface{}, dst interface{}) bool {
+	if p, ok := dst.(*string); ok {
+		*p = l.str
+		return true
+	}
+	if valueSpaceOf(l.DataType) == spaceNumeric {
+		return convertNumber(v, dst)
+	}
+	switch p := dst.(type) {
+	case *time.Duration:
+		if d, ok := v.(Duration); ok && d.Months == 0 {
+			*p = d.Time
+			return true
+		}
+	case *Duration:
+		if d, ok := v.(time.Duration); ok {
+			*p = Duration{Time: d}
+			return true
+		}
+	case *net.IP:
+		if s, ok := v.(string); ok {
+			if ip := net.ParseIP(s); ip != nil {
+				*p = ip
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// convertNumber converts a number, as returned from Literal.Typed, and stores
+// the result in the value pointed to by dst. It returns false if the number
+// cannot be represented in the type of dst.
+func convertNumber(v interface{}, dst interface{}) bool {
+	r, f := numberAsRat(v)
+	switch p := dst.(type) {
+	case *float64:
+		if r != nil {
+			f, _ = r.Float64()
+		}
+		*p = f
+		return true
+	case *float32:
+		if r != nil {
+			f32, _ := r.Float32()
+			f = float64(f32)
+		}
+		*p = float32(f)
+		return true
+	case **big.Rat:
+		*p = r
+		return r != nil
+	}
+	if r == nil || !r.IsInt() {
+		return false
+	}
+	n := r.Num()
+	switch p := dst.(type) {
+	case **big.Int:
+		*p = new(big.Int).Set(n)
+	case *int:
+		i, ok := bigIntN(n, strconv.IntSize)
+		*p = int(i)
+		return ok
+	case *int64:
+		i, ok := bigIntN(n, 64)
+		*p = i
+		return ok
+	case *int32:
+		i, ok := bigIntN(n, 32)
+		*p = int32(i)
+		return ok
+	case *int16:
+		i, ok := bigIntN(n, 16)
+		*p = int16(i)
+		return ok
+	case *int8:
+		i, ok := bigIntN(n, 8)
+		*p = int8(i)
+		return ok
+	case *uint:
+		u, ok := bigUintN(n, strconv.IntSize)
+		*p = uint(u)
+		return ok
+	case *uint64:
+		u, ok := bigUintN(n, 64)
+		*p = u
+		return ok
+	case *uint32:
+		u, ok := bigUintN(n, 32)
+		*p = uint32(u)
+		return ok
+	case *uint16:
+		u, ok := bigUintN(n, 16)
+		*p = uint16(u)
+		return ok
+	case *uint8:
+		u, ok := bigUintN(n, 8)
+		*p = uint8(u)
+		return ok
+	default:
+		return false
+	}
+	return true
+}
+
+// bigIntN returns n as an int64, and whether it fits in a signed integer of the given size.
+func bigIntN(n *big.Int, bitSize int) (int64, bool) {
+	if !n.IsInt64() {
+		return 0, false
+	}
+	i := n.Int64()
+	limit := int64(1) << (bitSize - 1)
+	if bitSize < 64 && (i < -limit || i >= limit) {
+		return 0, false
+	}
+	return i, true
+}
+
+// bigUintN returns n as an uint64, and whether it fits in an unsigned integer of the given size.
+func bigUintN(n *big.Int, bitSize int) (uint64, bool) {
+	if !n.IsUint64() {
+		return 0, false
+	}
+	u := n.Uint64()
+	if bitSize < 64 && u >= uint64(1)<<bitSize {
+		return 0, false
+	}
+	return u, true
+}
@@ -0,0 +1,280 @@
+package rdf
+
+import (
+	"math"
+	"math/big"
+	"net"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTyped(t *testing.T) {
+	plus2 := time.FixedZone("", 2*3600)
+	typedTests := []struct {
+		lex  string
+		dt   IRI
+		want interface{}
+	}{
+		{"abc", xsdString, "abc"},
+		{"true", xsdBoolean, true},
+		{"0", xsdBoolean, false},
+		{"123456789012345678901234567890", xsdInteger, bigInt("123456789012345678901234567890")},
+		{"+01", xsdInteger, big.NewInt(1)},
+		{"-1.50", xsdDecimal, big.NewRat(-3, 2)},
+		{".1", xsdDecimal, big.NewRat(1, 10)},
+		{"0", xsdNonNegativeInteger, big.NewInt(0)},
+		{"-7", xsdNegativeInteger, big.NewInt(-7)},
+		{"-9223372036854775808", xsdLong, int64(math.MinInt64)},
+		{"2147483647", xsdInt, int32(math.MaxInt32)},
+		{"-32768", xsdShort, int16(math.MinInt16)},
+		{"127", xsdByte, int8(127)},
+		{"18446744073709551615", xsdUnsignedLong, uint64(math.MaxUint64)},
+		{"-0", xsdUnsignedInt, uint32(0)},
+		{"+5", xsdUnsignedInt, uint32(5)},
+		{"+5", xsdUnsignedLong, uint64(5)},
+		{"65535", xsdUnsignedShort, uint16(65535)},
+		{"255", xsdUnsignedByte, uint8(255)},
+		{"1.5E3", xsdDouble, 1500.0},
+		{"-INF", xsdDouble, math.Inf(-1)},
+		{"0.5", xsdFloat, float32(0.5)},
+		{"2002-10-10T12:00:00-05:00", xsdDateTime, time.Date(2002, 10, 10, 12, 0, 0, 0, time.FixedZone("", -5*3600))},
+		{"2002-10-10T12:00:00.25", xsdDateTime, time.Date(2002, 10, 10, 12, 0, 0, 250000000, time.UTC)},
+		{"2002-10-10T24:00:00Z", xsdDateTime, time.Date(2002, 10, 11, 0, 0, 0, 0, time.UTC)},
+		{"2002-10-10Z", xsdDate, Date{Year: 2002, Month: 10, Day: 10, Zone: time.UTC}},
+		{"2000-02-29", xsdDate, Date{Year: 2000, Month: 2, Day: 29}},
+		{"13:20:00.5+02:00", xsdTime, Time{Hour: 13, Minute: 20, Nanosecond: 500000000, Zone: plus2}},
+		{"-0044", xsdYear, GYear{Year: -44}},
+		{"1999-05Z", xsdYearMonth, GYearMonth{Year: 1999, Month: 5, Zone: time.UTC}},
+		{"P1Y2M3DT4H5M6.5S", xsdDuration, Duration{Months: 14, Time: 76*time.Hour + 5*time.Minute + 6500*time.Millisecond}},
+		{"-P2Y", xsdYearMonthDuration, Duration{Months: -24}},
+		{"PT36H", xsdDayTimeDuration, 36 * time.Hour},
+		{"PT9223372036.854775807S", xsdDayTimeDuration, time.Duration(math.MaxInt64)},
+		{"0FB7", xsdHexBinary, []byte{0x0f, 0xb7}},
+		{"aGVp", xsdBase64Binary, []byte("hei")},
+		{"http://example.org/a?b#c", xsdAnyURI, &url.URL{Scheme: "http", Host: "example.org", Path: "/a", RawQuery: "b", Fragment: "c"}},
+		{"xyz", IRI{str: "http://example.org/custom"}, "xyz"},
+	}
+
+	for _, tt := range typedTests {
+		got, err := NewTypedLiteral(tt.lex, tt.dt).Typed()
+		if err != nil {
+			t.Errorf("NewTypedLiteral(%q, %v).Typed() failed with %v", tt.lex, tt.dt, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("NewTypedLiteral(%q, %v).Typed() => %#v; want %#v", tt.lex, tt.dt, got, tt.want)
+		}
+	}
+
+	errTests := []struct {
+		lex string
+		dt  IRI
+	}{
+		{"yes", xsdBoolean},
+		{"1.0", xsdInteger},
+		{"1e3", xsdDecimal},
+		{"1", xsdNegativeInteger},
+		{"128", xsdByte},
+		{"-1", xsdUnsignedByte},
+		{"+-0", xsdUnsignedInt},
+		{"inf", xsdDouble},
+		{"0x1p-2", xsdDouble},
+		{"2002-10-10T12:00:00", xsdDateTimeStamp},
+		{"2001-02-29", xsdDate},
+		{"24:00:01", xsdTime},
+		{"02002", xsdYear},
+		{"1999-13", xsdYearMonth},
+		{"P", xsdDuration},
+		{"P1YT", xsdDuration},
+		{"PT1M2H", xsdDuration},
+		{"P1Y", xsdDayTimeDuration},
+		{"P1D", xsdYearMonthDuration},
+		{"P1000000D", xsdDuration},
+		{"PT9223372037S", xsdDayTimeDuration},
+		{"P768614336404564651Y", xsdYearMonthDuration},
+		{"0FB", xsdHexBinary},
+		{"a!b", xsdBase64Binary},
+	}
+	for _, tt := range errTests {
+		if v, err := NewTypedLiteral(tt.lex, tt.dt).Typed(); err == nil {
+			t.Errorf("NewTypedLiteral(%q, %v).Typed() => %#v; want error", tt.lex, tt.dt, v)
+		}
+	}
+}
+
+func TestXSDValueStrings(t *testing.T) {
+	tests := []struct {
+		v    interface{ String() string }
+		want string
+	}{
+		{Date{Year: 2002, Month: 10, Day: 10, Zone: time.FixedZone("", -5*3600)}, "2002-10-10-05:00"},
+		{Time{Hour: 13, Minute: 20, Nanosecond: 500000000}, "13:20:00.5"},
+		{GYear{Year: -44, Zone: time.UTC}, "-0044Z"},
+		{GYearMonth{Year: 1999, Month: 5}, "1999-05"},
+		{Duration{}, "PT0S"},
+		{Duration{Months: 14, Time: 76*time.Hour + 5*time.Minute + 6500*time.Millisecond}, "P1Y2M3DT4H5M6.5S"},
+		{Duration{Months: -1}, "-P1M"},
+		{Duration{Time: 90 * time.Second}, "PT1M30S"},
+	}
+	for _, tt := range tests {
+		if got := tt.v.String(); got != tt.want {
+			t.Errorf("%#v.String() => %q; want %q", tt.v, got, tt.want)
+		}
+	}
+}
+
+func bigInt(s string) *big.Int {
+	i, _ := new(big.Int).SetString(s, 10)
+	return i
+}
+
+func TestCanonical(t *testing.T) {
+	tests := []struct {
+		lex  string
+		dt   IRI
+		want string
+	}{
+		{"1", xsdBoolean, "true"},
+		{"+0012", xsdInteger, "12"},
+		{"-0", xsdInteger, "0"},
+		{"007", xsdUnsignedByte, "7"},
+		{"+01.500", xsdDecimal, "1.5"},
+		{"-.0", xsdDecimal, "0.0"},
+		{"12", xsdDecimal, "12.0"},
+		{"100", xsdDouble, "1.0E2"},
+		{"-0.00125e1", xsdDouble, "-1.25E-2"},
+		{"+INF", xsdFloat, "INF"},
+		{"2002-10-10T12:00:00.500-05:00", xsdDateTime, "2002-10-10T17:00:00.5Z"},
+		{"2002-10-10T12:00:00", xsdDateTime, "2002-10-10T12:00:00"},
+		{"2002-10-10T24:00:00Z", xsdDateTime, "2002-10-11T00:00:00Z"},
+		{"2002-10-10+00:00", xsdDate, "2002-10-10Z"},
+		{"2002-10-10-05:00", xsdDate, "2002-10-10-05:00"},
+		{"2002-10+13:00", xsdYearMonth, "2002-10+13:00"},
+		{"01:30:00+02:00", xsdTime, "23:30:00Z"},
+		{"P0Y13M", xsdDuration, "P1Y1M"},
+		{"PT36H", xsdDayTimeDuration, "P1DT12H"},
+		{"0fb7", xsdHexBinary, "0FB7"},
+		{"aG Vp", xsdBase64Binary, "aGVp"},
+		{"  as is ", xsdString, "  as is "},
+	}
+	for _, tt := range tests {
+		l, err := NewTypedLiteral(tt.lex, tt.dt).Canonical()
+		if err != nil {
+			t.Errorf("NewTypedLiteral(%q, %v).Canonical() failed with %v", tt.lex, tt.dt, err)
+			continue
+		}
+		if l.String() != tt.want || l.DataType != tt.dt {
+			t.Errorf("NewTypedLiteral(%q, %v).Canonical() => %v; want %q", tt.lex, tt.dt, l.Serialize(NTriples), tt.want)
+		}
+	}
+
+	if _, err := NewTypedLiteral("x", xsdInteger).Canonical(); err == nil {
+		t.Error("NewTypedLiteral(\"x\", xsd:integer).Canonical() => <no error>; want error")
+	}
+}
+
+func TestIsWellTyped(t *testing.T) {
+	tests := []struct {
+		l    Literal
+		want bool
+	}{
+		{NewTypedLiteral("01", xsdInteger), true},
+		{NewTypedLiteral("1.0", xsdInteger), false},
+		{NewTypedLiteral("2020-02-30", xsdDate), false},
+		{NewTypedLiteral("P1000000D", xsdDuration), false},
+		{NewTypedLiteral("+5", xsdUnsignedLong), true},
+		{NewTypedLiteral("anything", IRI{str: "http://example.org/dt"}), true},
+		{Literal{str: "hei", lang: "no", DataType: rdfLangString}, true},
+		{Literal{str: "hei", DataType: rdfLangString}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.l.IsWellTyped(); got != tt.want {
+			t.Errorf("%v.IsWellTyped() => %v; want %v", tt.l.Serialize(NTriples), got, tt.want)
+		}
+	}
+}
+
+func TestLiteralAs(t *testing.T) {
+	lit := NewTypedLiteral
+	convTests := []struct {
+		conv func() (interface{}, error)
+		want interface{}
+	}{
+		{func() (interface{}, error) { return LiteralAs[int](lit("42", xsdInteger)) }, 42},
+		{func() (interface{}, error) { return LiteralAs[int8](lit("-128", xsdLong)) }, int8(-128)},
+		{func() (interface{}, error) { return LiteralAs[uint16](lit("65535", xsdInteger)) }, uint16(65535)},
+		{func() (interface{}, error) { return LiteralAs[int64](lit("2.0", xsdDecimal)) }, int64(2)},
+		{func() (interface{}, error) { return LiteralAs[int32](lit("1E3", xsdDouble)) }, int32(1000)},
+		{func() (interface{}, error) { return LiteralAs[float64](lit("0.25", xsdDecimal)) }, 0.25},
+		{func() (interface{}, error) { return LiteralAs[float32](lit("7", xsdByte)) }, float32(7)},
+		{func() (interface{}, error) { return LiteralAs[*big.Int](lit("5", xsdUnsignedByte)) }, big.NewInt(5)},
+		{func() (interface{}, error) { return LiteralAs[*big.Rat](lit("0.5", xsdFloat)) }, big.NewRat(1, 2)},
+		{func() (interface{}, error) { return LiteralAs[string](lit("01", xsdInteger)) }, "01"},
+		{func() (interface{}, error) { return LiteralAs[bool](lit("1", xsdBoolean)) }, true},
+		{func() (interface{}, error) { return LiteralAs[time.Duration](lit("P1DT1S", xsdDuration)) }, 24*time.Hour + time.Second},
+		{func() (interface{}, error) { return LiteralAs[Duration](lit("PT1M", xsdDayTimeDuration)) }, Duration{Time: time.Minute}},
+		{func() (interface{}, error) { return LiteralAs[net.IP](lit("::1", xsdString)) }, net.IPv6loopback},
+		{func() (interface{}, error) { return lit("-7", xsdInt).Int64() }, int64(-7)},
+		{func() (interface{}, error) { return lit("7", xsdNonNegativeInteger).Uint64() }, uint64(7)},
+		{func() (interface{}, error) { return lit("1.5", xsdDecimal).Float64() }, 1.5},
+		{func() (interface{}, error) { return lit("false", xsdBoolean).Bool() }, false},
+		{func() (interface{}, error) { return lit("2002-10-10T12:00:00Z", xsdDateTime).Time() }, time.Date(2002, 10, 10, 12, 0, 0, 0, time.UTC)},
+	}
+
+	for i, tt := range convTests {
+		got, err := tt.conv()
+		if err != nil {
+			t.Errorf("%d: conversion failed with %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%d: conversion => %#v; want %#v", i, got, tt.want)
+		}
+	}
+
+	errTests := []struct {
+		conv func() error
+		want string
+	}{
+		{func() error { _, err := LiteralAs[int8](lit("128", xsdInteger)); return err }, `cannot convert "128"^^<http://www.w3.org/2001/XMLSchema#integer> to int8`},
+		{func() error { _, err := LiteralAs[uint](lit("-1", xsdInt)); return err }, `cannot convert "-1"^^<http://www.w3.org/2001/XMLSchema#int> to uint`},
+		{func() error { _, err := LiteralAs[int](lit("1.5", xsdDecimal)); return err }, `cannot convert "1.5"^^<http://www.w3.org/2001/XMLSchema#decimal> to int`},
+		{func() error { _, err := LiteralAs[int](lit("NaN", xsdDouble)); return err }, `cannot convert "NaN"^^<http://www.w3.org/2001/XMLSchema#double> to int`},
+		{func() error { _, err := LiteralAs[time.Duration](lit("P1M", xsdDuration)); return err }, `cannot convert "P1M"^^<http://www.w3.org/2001/XMLSchema#duration> to time.Duration`},
+		{func() error { _, err := LiteralAs[net.IP](lit("localhost", xsdString)); return err }, `cannot convert "localhost" to net.IP`},
+		{func() error { _, err := lit("x", xsdInteger).Int64(); return err }, `invalid lexical form for <http://www.w3.org/2001/XMLSchema#integer>: "x"`},
+		{func() error { _, err := lit("1", xsdInteger).Bool(); return err }, `cannot convert "1"^^<http://www.w3.org/2001/XMLSchema#integer> to bool`},
+	}
+
+	for i, tt := range errTests {
+		err := tt.conv()
+		if err == nil {
+			t.Errorf("%d: conversion => <no error>; want %v", i, tt.want)
+			continue
+		}
+		if !strings.HasSuffix(err.Error(), tt.want) {
+			t.Errorf("%d: conversion failed with %v; want %v", i, err, tt.want)
+		}
+	}
+
+	// Modifying the returned values does not modify the literals.
+	li, _ := NewLiteral(big.NewInt(5))
+	i, _ := LiteralAs[*big.Int](li)
+	i.SetInt64(6)
+	v, _ := li.Typed()
+	v.(*big.Int).SetInt64(7)
+	if n, _ := li.Int64(); n != 5 {
+		t.Errorf("modifying the *big.Int changed the literal to %d", n)
+	}
+	lr, _ := NewLiteral(big.NewRat(1, 2))
+	r, _ := LiteralAs[*big.Rat](lr)
+	r.SetInt64(2)
+	v, _ = lr.Typed()
+	v.(*big.Rat).SetInt64(3)
+	if f, _ := lr.Float64(); f != 0.5 {
+		t.Errorf("modifying the *big.Rat changed the literal to %v", f)
+	}
+}